@@ -3,29 +3,106 @@ package assurance
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"math"
 	"strings"
 	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
 )
 
 // AssuranceReport contains details of the reliability calculation for AI explanation
 type AssuranceReport struct {
-	HolonID      string
-	FinalScore   float64
-	SelfScore    float64 // Score based on own evidence
-	WeakestLink  string  // ID of the dependency pulling the score down
-	DecayPenalty float64
-	Factors      []string // Textual explanations for AI
+	HolonID      string     `json:"holon_id"`
+	FinalScore   float64    `json:"r_eff"`
+	SelfScore    float64    `json:"self_score"`             // Score based on own evidence
+	WeakestLink  string     `json:"weakest_link,omitempty"` // ID of the dependency pulling the score down
+	DecayPenalty float64    `json:"decay_penalty"`          // What expiry and suspicion took off SelfScore
+	Formality    int        `json:"formality"`              // F: weakest formality across self and dependencies (F0–F9)
+	ClaimScope   ClaimScope `json:"claim_scope"`            // G: where the claim holds after propagation
+	PhiProfile   string     `json:"phi_profile"`            // Φ(CL) profile that produced the CL penalties
+	DecayProfile string     `json:"decay_profile"`          // Decay profiles that produced the evidence decay
+	Factors      []string   `json:"factors"`                // Textual explanations for AI
+
+	Evidence     []EvidenceScore   `json:"evidence"`     // Own evidence as it counts towards SelfScore
+	Dependencies []DependencyScore `json:"dependencies"` // Dependencies as they bound FinalScore
+	WeakLinks    []string          `json:"weak_links"`   // Every dependency tied at the lowest effective score
+
+	// CriticalPath runs from this holon down the weakest links to the holon
+	// whose own evidence bounds R_eff; BoundingEvidence is the lowest-scoring
+	// evidence there.
+	CriticalPath     []string `json:"critical_path"`
+	BoundingEvidence []string `json:"bounding_evidence"`
+
+	Actions []Action `json:"actions,omitempty"` // Filled by Explain, ranked by gain
+}
+
+// EvidenceScore is how one evidence record counts towards a self score.
+// Decay is what expiry and suspicion took off its verdict score.
+type EvidenceScore struct {
+	ID      string  `json:"id"`
+	Type    string  `json:"type"`
+	Class   string  `json:"class"`
+	Verdict string  `json:"verdict"`
+	Score   float64 `json:"score"`
+	Weight  float64 `json:"weight"`
+	Decay   float64 `json:"decay,omitempty"`
+	Expired bool    `json:"expired,omitempty"`
+	Waived  bool    `json:"waived,omitempty"`
+	Suspect bool    `json:"suspect,omitempty"`
+}
+
+// DependencyScore is one dependency and what it leaves of R_eff after the
+// congruence penalty.
+type DependencyScore struct {
+	ID        string  `json:"id"`
+	CL        int     `json:"cl"`
+	Score     float64 `json:"r_eff"`
+	Penalty   float64 `json:"penalty"`
+	Effective float64 `json:"effective"`
+}
+
+// SuspectDecayFactor scales the score of evidence whose carrier changed since
+// it was recorded: the evidence may still hold, but it has not been re-checked.
+const SuspectDecayFactor = 0.5
+
+// Store is the part of db.Repository the calculator reads and caches into
+type Store interface {
+	GetHolon(ctx context.Context, id string) (db.Holon, error)
+	GetEvidence(ctx context.Context, holonID string) ([]db.Evidence, error)
+	GetSuspectEvidenceByHolon(ctx context.Context, holonID string) ([]db.SuspectEvidence, error)
+	GetWaiversByEvidence(ctx context.Context, evidenceID string) ([]db.Waiver, error)
+	GetComponentsOf(ctx context.Context, targetID string) ([]db.GetComponentsOfRow, error)
+	GetRelationsBySource(ctx context.Context, sourceID, relationType string) ([]db.Relation, error)
+	GetCollectionMembers(ctx context.Context, targetID string) ([]db.GetCollectionMembersRow, error)
+	CacheHolonRScore(ctx context.Context, id string, score float64) error
 }
 
 // Calculator handles assurance logic
 type Calculator struct {
-	DB *sql.DB
+	DB        Store
+	Phi       CLPenaltyProfile  // Congruence penalty Φ(CL)
+	Decay     DecayProfiles     // Evidence decay after valid_until, per evidence type
+	Weighting EvidenceWeighting // How evidence aggregates into the self score
+	Now       func() time.Time  // Evaluation time for evidence decay; nil means time.Now
 }
 
-// New creates a new Calculator
-func New(db *sql.DB) *Calculator {
-	return &Calculator{DB: db}
+// New creates a new Calculator using the normative Φ(CL) table
+func New(store Store) *Calculator {
+	return &Calculator{DB: store, Phi: DefaultCLPenaltyProfile}
+}
+
+// NewWithProfile creates a Calculator with a project-specific Φ(CL) profile
+func NewWithProfile(store Store, phi CLPenaltyProfile) *Calculator {
+	return &Calculator{DB: store, Phi: phi}
+}
+
+func (c *Calculator) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
 }
 
 // CalculateReliability calculates R for a holon (public API)
@@ -39,34 +116,46 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 	// Cycle detection: if already visited, return neutral score to break cycle
 	if visited[holonID] {
 		return &AssuranceReport{
-			HolonID:    holonID,
-			FinalScore: 1.0, // Neutral - don't penalize for cycle
-			SelfScore:  1.0,
-			Factors:    []string{"Cycle detected, skipping re-evaluation"},
+			HolonID:      holonID,
+			FinalScore:   1.0, // Neutral - don't penalize for cycle
+			SelfScore:    1.0,
+			Formality:    MaxFormality,
+			PhiProfile:   c.Phi.String(),
+			DecayProfile: c.Decay.Spec(),
+			Factors:      []string{"Cycle detected, skipping re-evaluation"},
 		}, nil
 	}
 	visited[holonID] = true
 
-	report := &AssuranceReport{HolonID: holonID}
+	report := &AssuranceReport{HolonID: holonID, PhiProfile: c.Phi.String(), DecayProfile: c.Decay.Spec()}
 
-	// 1. Calculate Self Score (based on Evidence)
-	// B.3.4: Check for expired evidence
-	rows, err := c.DB.QueryContext(ctx, "SELECT verdict, valid_until FROM evidence WHERE holon_id = ?", holonID)
+	selfF, selfG, err := c.loadClaim(ctx, holonID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close() //nolint:errcheck
+	report.Formality = selfF
+	report.ClaimScope = selfG
 
-	var totalScore, count float64
-	for rows.Next() {
-		var verdict string
-		var validUntil *time.Time
-		if err := rows.Scan(&verdict, &validUntil); err != nil {
-			continue
-		}
+	// 1. Calculate Self Score (based on Evidence)
+	// B.3.4: Check for expired evidence and evidence whose carrier changed
+	evidence, err := c.DB.GetEvidence(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+	suspects, err := c.DB.GetSuspectEvidenceByHolon(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+	changedPaths := make(map[string]string, len(suspects))
+	for _, s := range suspects {
+		changedPaths[s.EvidenceID] = s.ChangedPaths
+	}
 
+	now := c.now()
+	var scores, undecayed, weights []float64
+	for _, e := range evidence {
 		score := 0.0
-		switch strings.ToLower(verdict) {
+		switch strings.ToLower(e.Verdict) {
 		case "pass":
 			score = 1.0
 		case "degrade":
@@ -75,18 +164,53 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 			score = 0.0
 		}
 
-		// Evidence Decay Logic
-		if validUntil != nil && time.Now().After(*validUntil) {
-			report.Factors = append(report.Factors, "Evidence expired (Decay applied)")
-			score = 0.1                // Penalty for expiration, not zero but close
-			report.DecayPenalty += 0.9 // Track how much was lost
+		es := EvidenceScore{ID: e.ID, Type: e.Type, Class: EvidenceClass(e), Verdict: e.Verdict, Weight: c.Weighting.Weight(e)}
+		undecayed = append(undecayed, score)
+
+		// Evidence Decay Logic: past valid_until the type's profile takes
+		// over, unless a waiver suspends it
+		if e.ValidUntil.Valid && now.After(e.ValidUntil.Time) {
+			factor, waivedUntil, err := c.decayFactor(ctx, e, now)
+			if err != nil {
+				return nil, err
+			}
+			if !waivedUntil.IsZero() {
+				es.Waived = true
+				report.Factors = append(report.Factors, fmt.Sprintf("Evidence %s expired, decay waived until %s", e.ID, waivedUntil.Format("2006-01-02")))
+			} else {
+				es.Expired = true
+				es.Decay = score * (1 - factor)
+				score *= factor
+				report.Factors = append(report.Factors, fmt.Sprintf("Evidence %s expired: -%.2f (%s decay)", e.ID, es.Decay, c.Decay.For(e.Type).Spec()))
+			}
 		}
-		totalScore += score
-		count++
+
+		// Suspect decay: the artifact the evidence was taken from has changed
+		if paths, ok := changedPaths[e.ID]; ok {
+			report.Factors = append(report.Factors, fmt.Sprintf("Evidence %s suspect: carrier changed (%s)", e.ID, paths))
+			es.Decay += score * (1 - SuspectDecayFactor)
+			score *= SuspectDecayFactor
+			es.Suspect = true
+		}
+		es.Score = score
+		report.Evidence = append(report.Evidence, es)
+		scores = append(scores, score)
+		weights = append(weights, es.Weight)
 	}
 
-	if count > 0 {
-		report.SelfScore = totalScore / count // Or other aggregation logic
+	if len(scores) > 0 {
+		if c.Weighting.Spec() != DefaultEvidenceWeighting.Spec() {
+			report.Factors = append(report.Factors, fmt.Sprintf("Self score: %s", c.Weighting))
+		}
+		var ok bool
+		if report.SelfScore, ok = c.Weighting.Aggregate(scores, weights); !ok {
+			report.Factors = append(report.Factors, "All evidence weighted 0 (L0)")
+		} else {
+			// Decay is counted by what it took off the aggregate, so each
+			// item weighs in as much as its score does.
+			full, _ := c.Weighting.Aggregate(undecayed, weights)
+			report.DecayPenalty = full - report.SelfScore
+		}
 	} else {
 		report.SelfScore = 0.0 // L0: Unsubstantiated
 		report.Factors = append(report.Factors, "No evidence found (L0)")
@@ -100,33 +224,36 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 	// When calculating reliability for holonID:
 	//   - componentOf: find rows where target_id = holonID, dependency is source_id
 	//   - dependsOn:   find rows where source_id = holonID, dependency is target_id
-	depRows, err := c.DB.QueryContext(ctx, `
-		SELECT source_id AS dep_id, congruence_level FROM relations
-		WHERE target_id = ? AND relation_type = 'componentOf'
-		UNION
-		SELECT target_id AS dep_id, congruence_level FROM relations
-		WHERE source_id = ? AND relation_type = 'dependsOn'`, holonID, holonID)
-
+	components, err := c.DB.GetComponentsOf(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+	dependsOn, err := c.DB.GetRelationsBySource(ctx, holonID, "dependsOn")
 	if err != nil {
 		return nil, err
 	}
 
-	// Collect deps first to avoid holding cursor during recursive calls
 	type dep struct {
 		id string
 		cl int
 	}
 	var deps []dep
-	for depRows.Next() {
-		var d dep
-		if err := depRows.Scan(&d.id, &d.cl); err != nil {
-			continue
+	seen := make(map[dep]bool)
+	addDep := func(d dep) {
+		if !seen[d] {
+			seen[d] = true
+			deps = append(deps, d)
 		}
-		deps = append(deps, d)
 	}
-	_ = depRows.Close()
+	for _, r := range components {
+		addDep(dep{r.SourceID, congruenceLevel(r.CongruenceLevel)})
+	}
+	for _, r := range dependsOn {
+		addDep(dep{r.TargetID, congruenceLevel(r.CongruenceLevel)})
+	}
 
 	minDepScore := 1.0
+	depReports := make(map[string]*AssuranceReport, len(deps))
 	for _, d := range deps {
 		// Recursive call for dependency with visited map for cycle detection
 		depReport, err := c.calculateReliabilityWithVisited(ctx, d.id, visited)
@@ -134,9 +261,20 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 			depReport = &AssuranceReport{FinalScore: 0.0}
 		}
 
-		// CL Penalty: CL=3 (0.0), CL=2 (0.1), CL=1 (0.4), CL=0 (0.9)
-		penalty := calculateCLPenalty(d.cl)
+		// F follows the weakest link; G narrows to where every part holds.
+		if depReport.Formality < report.Formality {
+			report.Formality = depReport.Formality
+			report.Factors = append(report.Factors, fmt.Sprintf("Formality capped at %s by %s", FormatFormality(depReport.Formality), d.id))
+		}
+		report.ClaimScope = report.ClaimScope.Intersect(depReport.ClaimScope)
+
+		// CL Penalty: Φ(CL) from the configured profile
+		penalty := c.Phi.Penalty(d.cl)
 		effectiveR := math.Max(0, depReport.FinalScore-penalty)
+		depReports[d.id] = depReport
+		report.Dependencies = append(report.Dependencies, DependencyScore{
+			ID: d.id, CL: d.cl, Score: depReport.FinalScore, Penalty: penalty, Effective: effectiveR,
+		})
 
 		if effectiveR < minDepScore {
 			minDepScore = effectiveR
@@ -144,12 +282,25 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 		}
 
 		if penalty > 0 {
-			report.Factors = append(report.Factors, "CL Penalty applied for "+d.id)
+			report.Factors = append(report.Factors, fmt.Sprintf("CL Penalty applied for %s (Φ(CL%d)=%.2f)", d.id, d.cl, penalty))
 		}
 	}
 
 	hasDeps := len(deps) > 0
 
+	// Parallel members (memberOf) are alternatives: the whole holds wherever
+	// any member does, so their scopes are united before narrowing by self.
+	members, err := c.claimScopeOfMembers(ctx, holonID, visited)
+	if err != nil {
+		return nil, err
+	}
+	if members != nil {
+		report.ClaimScope = report.ClaimScope.Intersect(*members)
+	}
+	if report.ClaimScope.IsEmpty() {
+		report.Factors = append(report.Factors, "Claim scope is empty: parts hold under disjoint conditions")
+	}
+
 	// 3. Weakest Link Principle (WLNK)
 	// The final rating cannot be higher than the weakest link (self or dependency)
 	if hasDeps {
@@ -157,24 +308,130 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 	} else {
 		report.FinalScore = report.SelfScore
 	}
+	report.explainBound(minDepScore, depReports)
 
 	// Update cache (non-critical, log warning on failure)
-	if _, err := c.DB.ExecContext(ctx, "UPDATE holons SET cached_r_score = ? WHERE id = ?", report.FinalScore, holonID); err != nil {
+	if err := c.DB.CacheHolonRScore(ctx, holonID, report.FinalScore); err != nil {
 		report.Factors = append(report.Factors, "Warning: cache update failed")
 	}
 
 	return report, nil
 }
 
-func calculateCLPenalty(cl int) float64 {
-	switch cl {
-	case 3:
-		return 0.0
-	case 2:
-		return 0.1
-	case 1:
-		return 0.4
-	default:
-		return 0.9
+// decayFactor returns how much of expired evidence's score is left at now.
+// A waiver suspends decay: while one runs, the factor is 1 and waivedUntil
+// is its end; afterwards decay starts over from the end of the last waiver.
+func (c *Calculator) decayFactor(ctx context.Context, e db.Evidence, now time.Time) (factor float64, waivedUntil time.Time, err error) {
+	waivers, err := c.DB.GetWaiversByEvidence(ctx, e.ID)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	start := e.ValidUntil.Time
+	for _, w := range waivers {
+		if w.WaivedUntil.After(start) {
+			start = w.WaivedUntil
+		}
+	}
+	if !now.After(start) {
+		return 1, start, nil
+	}
+	return c.Decay.For(e.Type).Factor(now.Sub(start)), time.Time{}, nil
+}
+
+// explainBound records the weak links tied at minDepScore and the critical
+// path: down the weakest link while a dependency bounds R_eff, otherwise
+// ending here at the lowest-scoring own evidence.
+func (r *AssuranceReport) explainBound(minDepScore float64, depReports map[string]*AssuranceReport) {
+	for _, d := range r.Dependencies {
+		if r.WeakestLink != "" && d.Effective == minDepScore {
+			r.WeakLinks = append(r.WeakLinks, d.ID)
+		}
+	}
+
+	r.CriticalPath = []string{r.HolonID}
+	if len(r.Dependencies) > 0 && minDepScore < r.SelfScore {
+		if dep := depReports[r.WeakestLink]; dep != nil && dep.HolonID == r.WeakestLink {
+			r.CriticalPath = append(r.CriticalPath, dep.CriticalPath...)
+			r.BoundingEvidence = dep.BoundingEvidence
+		}
+		return
+	}
+
+	lowest := 1.0
+	for _, e := range r.Evidence {
+		lowest = math.Min(lowest, e.Score)
+	}
+	for _, e := range r.Evidence {
+		if e.Score == lowest && lowest < 1 {
+			r.BoundingEvidence = append(r.BoundingEvidence, e.ID)
+		}
+	}
+}
+
+// loadClaim reads the holon's own formality and claim scope. Holons without
+// a row (e.g. evidence attached by ID only) are F0 and unbounded.
+func (c *Calculator) loadClaim(ctx context.Context, holonID string) (int, ClaimScope, error) {
+	holon, err := c.DB.GetHolon(ctx, holonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return MinFormality, nil, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	g, err := ParseClaimScope(holon.ClaimScope.String)
+	if err != nil {
+		return 0, nil, fmt.Errorf("holon %s: %w", holonID, err)
+	}
+	return int(holon.Formality.Int64), g, nil
+}
+
+// congruenceLevel reads a stored CL, treating a missing value as the column
+// default of CL3.
+func congruenceLevel(cl sql.NullInt64) int {
+	if !cl.Valid {
+		return 3
+	}
+	return int(cl.Int64)
+}
+
+// claimScopeOfMembers returns the union of member scopes, or nil if the
+// holon has no members.
+func (c *Calculator) claimScopeOfMembers(ctx context.Context, holonID string, visited map[string]bool) (*ClaimScope, error) {
+	members, err := c.DB.GetCollectionMembers(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+	var memberIDs []string
+	for _, m := range members {
+		memberIDs = append(memberIDs, m.SourceID)
+	}
+
+	if len(memberIDs) == 0 {
+		return nil, nil
+	}
+
+	var union ClaimScope
+	contributed := false
+	for _, id := range memberIDs {
+		// Members are evaluated on their own path so a member shared with the
+		// dependency graph is not mistaken for a cycle.
+		branch := make(map[string]bool, len(visited))
+		for k, v := range visited {
+			branch[k] = v
+		}
+		memberReport, err := c.calculateReliabilityWithVisited(ctx, id, branch)
+		if err != nil {
+			continue
+		}
+		if !contributed {
+			union, contributed = memberReport.ClaimScope, true
+			continue
+		}
+		union = union.Union(memberReport.ClaimScope)
+	}
+	if !contributed {
+		return nil, nil
 	}
+	return &union, nil
 }
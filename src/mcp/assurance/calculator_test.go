@@ -2,43 +2,51 @@ package assurance
 
 import (
 	"context"
-	"database/sql"
+	"math"
+	"reflect"
 	"testing"
 	"time"
 
-	_ "modernc.org/sqlite"
+	"github.com/m0n0x41d/quint-code/db"
 )
 
-func setupTestDB(t *testing.T) *sql.DB {
-	// Use cache=shared to share DB across connections in the pool
-	db, err := sql.Open("sqlite", "file:memdb1?mode=memory&cache=shared")
-	if err != nil {
-		t.Fatalf("failed to open db: %v", err)
+func setupTestStore(t *testing.T) *db.MemoryStore {
+	t.Helper()
+	return db.NewMemoryStore()
+}
+
+func addTestEvidence(t *testing.T, store *db.MemoryStore, id, holonID, verdict string, validUntil time.Time) {
+	t.Helper()
+	if err := store.AddEvidence(context.Background(), id, holonID, "test", "", verdict, "", "", validUntil.Format(time.RFC3339)); err != nil {
+		t.Fatalf("failed to insert evidence: %v", err)
 	}
-	db.SetMaxOpenConns(1) // Ensure single connection to avoid issues
+}
+
+func addTestHolon(t *testing.T, store *db.MemoryStore, id string, formality int, claimScope string) {
+	t.Helper()
+	ctx := context.Background()
+	if err := store.CreateHolon(ctx, id, "hypothesis", "system", "L1", id, "", "default", "", ""); err != nil {
+		t.Fatalf("failed to insert holon: %v", err)
+	}
+	if err := store.UpdateHolonClaim(ctx, id, formality, claimScope); err != nil {
+		t.Fatalf("failed to set claim: %v", err)
+	}
+}
 
-	schema := `
-	CREATE TABLE holons (id TEXT PRIMARY KEY, cached_r_score REAL DEFAULT 0.0);
-	CREATE TABLE evidence (id TEXT PRIMARY KEY, holon_id TEXT, verdict TEXT, valid_until DATETIME);
-	CREATE TABLE relations (source_id TEXT, target_id TEXT, relation_type TEXT, congruence_level INTEGER);
-	`
-	if _, err := db.Exec(schema); err != nil {
-		t.Fatalf("failed to init schema: %v", err)
+func addTestRelation(t *testing.T, store *db.MemoryStore, source, relType, target string, cl int) {
+	t.Helper()
+	if err := store.CreateRelation(context.Background(), source, relType, target, cl); err != nil {
+		t.Fatalf("failed to insert relation: %v", err)
 	}
-	return db
 }
 
 func TestCalculateReliability_SelfScore(t *testing.T) {
-	db := setupTestDB(t)
-	defer db.Close()
+	store := setupTestStore(t)
 
 	// Insert evidence for holon A (PASS)
-	_, err := db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", time.Now().Add(24*time.Hour))
-	if err != nil {
-		t.Fatalf("failed to insert evidence: %v", err)
-	}
+	addTestEvidence(t, store, "e1", "A", "pass", time.Now().Add(24*time.Hour))
 
-	calc := New(db)
+	calc := New(store)
 	report, err := calc.CalculateReliability(context.Background(), "A")
 	if err != nil {
 		t.Fatalf("CalculateReliability failed: %v", err)
@@ -50,17 +58,13 @@ func TestCalculateReliability_SelfScore(t *testing.T) {
 }
 
 func TestCalculateReliability_EvidenceDecay(t *testing.T) {
-	db := setupTestDB(t)
-	defer db.Close()
+	store := setupTestStore(t)
 
 	// Insert expired evidence for holon A
 	expired := time.Now().Add(-24 * time.Hour)
-	_, err := db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", expired)
-	if err != nil {
-		t.Fatalf("failed to insert evidence: %v", err)
-	}
+	addTestEvidence(t, store, "e1", "A", "pass", expired)
 
-	calc := New(db)
+	calc := New(store)
 	report, err := calc.CalculateReliability(context.Background(), "A")
 	if err != nil {
 		t.Fatalf("CalculateReliability failed: %v", err)
@@ -72,17 +76,45 @@ func TestCalculateReliability_EvidenceDecay(t *testing.T) {
 	}
 }
 
+func TestCalculateReliability_SuspectDecay(t *testing.T) {
+	store := setupTestStore(t)
+
+	valid := time.Now().Add(24 * time.Hour)
+	addTestEvidence(t, store, "e1", "A", "pass", valid)
+	addTestEvidence(t, store, "e2", "A", "pass", valid)
+	_ = store.MarkEvidenceSuspect(context.Background(), "e1", "A", "cache/redis.go", "", "")
+
+	calc := New(store)
+	report, err := calc.CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+
+	// (0.5 + 1.0) / 2
+	if report.FinalScore != 0.75 {
+		t.Errorf("Expected score 0.75 with one suspect evidence, got %f", report.FinalScore)
+	}
+	if report.DecayPenalty != 0.25 {
+		t.Errorf("Expected decay penalty 1.0-0.75 = 0.25, got %f", report.DecayPenalty)
+	}
+	want := []string{
+		"Evidence e1 suspect: carrier changed (cache/redis.go)",
+	}
+	if !reflect.DeepEqual(report.Factors, want) {
+		t.Errorf("Unexpected factors: %v", report.Factors)
+	}
+}
+
 func TestCalculateReliability_WeakestLink(t *testing.T) {
-	db := setupTestDB(t)
-	defer db.Close()
+	store := setupTestStore(t)
 
-	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", time.Now().Add(24*time.Hour))
-	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e2', 'B', 'fail', ?)", time.Now().Add(24*time.Hour))
+	addTestEvidence(t, store, "e1", "A", "pass", time.Now().Add(24*time.Hour))
+	addTestEvidence(t, store, "e2", "B", "fail", time.Now().Add(24*time.Hour))
 
 	// B is component of A
-	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'A', 'componentOf', 3)")
+	addTestRelation(t, store, "B", "componentOf", "A", 3)
 
-	calc := New(db)
+	calc := New(store)
 	report, err := calc.CalculateReliability(context.Background(), "A")
 	if err != nil {
 		t.Fatalf("CalculateReliability failed: %v", err)
@@ -95,15 +127,14 @@ func TestCalculateReliability_WeakestLink(t *testing.T) {
 }
 
 func TestCalculateReliability_CLPenalty(t *testing.T) {
-	db := setupTestDB(t)
-	defer db.Close()
+	store := setupTestStore(t)
 
-	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", time.Now().Add(24*time.Hour))
-	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e2', 'B', 'pass', ?)", time.Now().Add(24*time.Hour))
+	addTestEvidence(t, store, "e1", "A", "pass", time.Now().Add(24*time.Hour))
+	addTestEvidence(t, store, "e2", "B", "pass", time.Now().Add(24*time.Hour))
 
-	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'A', 'componentOf', 1)")
+	addTestRelation(t, store, "B", "componentOf", "A", 1)
 
-	calc := New(db)
+	calc := New(store)
 	report, err := calc.CalculateReliability(context.Background(), "A")
 	if err != nil {
 		t.Fatalf("CalculateReliability failed: %v", err)
@@ -115,21 +146,20 @@ func TestCalculateReliability_CLPenalty(t *testing.T) {
 }
 
 func TestCalculateReliability_CycleDetection(t *testing.T) {
-	db := setupTestDB(t)
-	defer db.Close()
+	store := setupTestStore(t)
 
 	// Create A→B→C→A cycle via componentOf relations
 	// A contains B, B contains C, C contains A (circular)
-	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", time.Now().Add(24*time.Hour))
-	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e2', 'B', 'pass', ?)", time.Now().Add(24*time.Hour))
-	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e3', 'C', 'pass', ?)", time.Now().Add(24*time.Hour))
+	addTestEvidence(t, store, "e1", "A", "pass", time.Now().Add(24*time.Hour))
+	addTestEvidence(t, store, "e2", "B", "pass", time.Now().Add(24*time.Hour))
+	addTestEvidence(t, store, "e3", "C", "pass", time.Now().Add(24*time.Hour))
 
 	// B is component of A, C is component of B, A is component of C (cycle!)
-	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'A', 'componentOf', 3)")
-	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('C', 'B', 'componentOf', 3)")
-	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('A', 'C', 'componentOf', 3)")
+	addTestRelation(t, store, "B", "componentOf", "A", 3)
+	addTestRelation(t, store, "C", "componentOf", "B", 3)
+	addTestRelation(t, store, "A", "componentOf", "C", 3)
 
-	calc := New(db)
+	calc := New(store)
 	report, err := calc.CalculateReliability(context.Background(), "A")
 
 	// Should not error or hang - cycle should be detected and handled gracefully
@@ -143,3 +173,269 @@ func TestCalculateReliability_CycleDetection(t *testing.T) {
 		t.Errorf("Expected score 1.0 (cycle handled gracefully), got %f", report.FinalScore)
 	}
 }
+
+func TestCalculateReliability_FormalityWeakestLink(t *testing.T) {
+	store := setupTestStore(t)
+
+	addTestHolon(t, store, "A", 7, "")
+	addTestHolon(t, store, "B", 2, "")
+	addTestHolon(t, store, "C", 5, "")
+	addTestRelation(t, store, "B", "componentOf", "A", 3)
+	addTestRelation(t, store, "A", "dependsOn", "C", 3)
+
+	calc := New(store)
+	report, err := calc.CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+
+	if report.Formality != 2 {
+		t.Errorf("Expected F2 (weakest of F7, F2, F5), got F%d", report.Formality)
+	}
+}
+
+func TestCalculateReliability_ClaimScopePropagation(t *testing.T) {
+	store := setupTestStore(t)
+
+	// Whole W has parts P1 and P2: G narrows to where both hold.
+	addTestHolon(t, store, "W", 0, "")
+	addTestHolon(t, store, "P1", 0, `{"env":["prod","staging"],"db":["postgres"]}`)
+	addTestHolon(t, store, "P2", 0, `{"env":["prod"]}`)
+	addTestRelation(t, store, "P1", "componentOf", "W", 3)
+	addTestRelation(t, store, "P2", "componentOf", "W", 3)
+
+	// Decision D has parallel members M1 and M2: G widens to where either holds.
+	addTestHolon(t, store, "D", 0, "")
+	addTestHolon(t, store, "M1", 0, `{"env":["prod"],"region":["eu"]}`)
+	addTestHolon(t, store, "M2", 0, `{"env":["staging"]}`)
+	addTestRelation(t, store, "M1", "memberOf", "D", 3)
+	addTestRelation(t, store, "M2", "memberOf", "D", 3)
+
+	calc := New(store)
+	ctx := context.Background()
+
+	whole, err := calc.CalculateReliability(ctx, "W")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if got := whole.ClaimScope.String(); got != "db=postgres; env=prod" {
+		t.Errorf("Expected intersection 'db=postgres; env=prod', got %q", got)
+	}
+
+	decision, err := calc.CalculateReliability(ctx, "D")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if got := decision.ClaimScope.String(); got != "env=prod,staging" {
+		t.Errorf("Expected union 'env=prod,staging', got %q", got)
+	}
+}
+
+func TestCalculateReliability_DisjointClaimScope(t *testing.T) {
+	store := setupTestStore(t)
+
+	addTestHolon(t, store, "A", 0, `{"env":["prod"]}`)
+	addTestHolon(t, store, "B", 0, `{"env":["dev"]}`)
+	addTestRelation(t, store, "B", "componentOf", "A", 3)
+
+	report, err := New(store).CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if !report.ClaimScope.IsEmpty() {
+		t.Errorf("Expected empty claim scope for disjoint parts, got %q", report.ClaimScope)
+	}
+}
+
+func TestCalculateReliability_CustomPhiProfile(t *testing.T) {
+	store := setupTestStore(t)
+
+	addTestEvidence(t, store, "e1", "A", "pass", time.Now().Add(24*time.Hour))
+	addTestEvidence(t, store, "e2", "B", "pass", time.Now().Add(24*time.Hour))
+	addTestRelation(t, store, "B", "componentOf", "A", 1)
+
+	phi, err := ParseCLPenaltyProfile("custom:1,0.25,0.1,0")
+	if err != nil {
+		t.Fatalf("ParseCLPenaltyProfile failed: %v", err)
+	}
+
+	report, err := NewWithProfile(store, phi).CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+
+	if report.FinalScore != 0.75 {
+		t.Errorf("Expected score 0.75 under custom Φ(CL1)=0.25, got %f", report.FinalScore)
+	}
+	if report.PhiProfile != phi.String() {
+		t.Errorf("Expected report to record profile %q, got %q", phi.String(), report.PhiProfile)
+	}
+
+	report, _ = New(store).CalculateReliability(context.Background(), "A")
+	if report.PhiProfile != DefaultCLPenaltyProfile.String() {
+		t.Errorf("Expected default profile recorded, got %q", report.PhiProfile)
+	}
+}
+
+func TestCalculateReliability_DecayProfiles(t *testing.T) {
+	store := setupTestStore(t)
+	ctx := context.Background()
+
+	expiry := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := store.AddEvidence(ctx, "bench", "A", "benchmark", "", "pass", "", "", expiry.Format(time.RFC3339)); err != nil {
+		t.Fatalf("failed to insert evidence: %v", err)
+	}
+	addTestEvidence(t, store, "unit", "A", "degrade", expiry)
+
+	decay, err := ParseDecayProfiles("none,benchmark=half-life:10d")
+	if err != nil {
+		t.Fatalf("ParseDecayProfiles failed: %v", err)
+	}
+	calc := New(store)
+	calc.Decay = decay
+	calc.Now = func() time.Time { return expiry.Add(20 * 24 * time.Hour) }
+
+	report, err := calc.CalculateReliability(ctx, "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	// bench: 1.0 halved twice; unit: 0.5, never decays.
+	if report.FinalScore != 0.375 {
+		t.Errorf("Expected score (0.25+0.5)/2 = 0.375, got %f", report.FinalScore)
+	}
+	if report.DecayPenalty != 0.375 {
+		t.Errorf("Expected decay penalty 0.75-0.375 = 0.375, got %f", report.DecayPenalty)
+	}
+	for _, e := range report.Evidence {
+		if want := map[string]float64{"bench": 0.75, "unit": 0}[e.ID]; e.Decay != want || !e.Expired {
+			t.Errorf("Expected expired %s to lose %.2f, got %+v", e.ID, want, e)
+		}
+	}
+	if report.DecayProfile != "none,benchmark=half-life:10d" {
+		t.Errorf("Expected report to record the decay profiles, got %q", report.DecayProfile)
+	}
+
+	// The default step decays degrade evidence to a tenth of its score.
+	calc.Decay = DefaultDecayProfiles
+	report, _ = calc.CalculateReliability(ctx, "A")
+	if math.Abs(report.FinalScore-(0.1+0.05)/2) > 1e-9 {
+		t.Errorf("Expected step decay to leave (0.1+0.05)/2, got %f", report.FinalScore)
+	}
+}
+
+func TestCalculateReliability_DecayPenaltyIsEffectOnR(t *testing.T) {
+	store := setupTestStore(t)
+	ctx := context.Background()
+
+	expiry := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := store.AddEvidence(ctx, "bench", "A", "benchmark", "", "pass", "", "", expiry.Format(time.RFC3339)); err != nil {
+		t.Fatalf("failed to insert evidence: %v", err)
+	}
+	addTestEvidence(t, store, "unit", "A", "pass", expiry.AddDate(1, 0, 0))
+	addTestEvidence(t, store, "smoke", "A", "degrade", expiry.AddDate(1, 0, 0))
+
+	weighting, err := ParseEvidenceWeighting("mean,type:benchmark=3")
+	if err != nil {
+		t.Fatalf("ParseEvidenceWeighting failed: %v", err)
+	}
+	score := func(at time.Time) *AssuranceReport {
+		calc := New(store)
+		calc.Weighting = weighting
+		calc.Now = func() time.Time { return at }
+		report, err := calc.CalculateReliability(ctx, "A")
+		if err != nil {
+			t.Fatalf("CalculateReliability failed: %v", err)
+		}
+		return report
+	}
+
+	before := score(expiry.Add(-time.Hour))
+	after := score(expiry.Add(time.Hour))
+	// (3·1 + 1 + 0.5)/5 = 0.9 before, (3·0.1 + 1 + 0.5)/5 = 0.36 after.
+	if before.DecayPenalty != 0 {
+		t.Errorf("Expected no decay penalty before expiry, got %f", before.DecayPenalty)
+	}
+	if delta := before.FinalScore - after.FinalScore; math.Abs(after.DecayPenalty-delta) > 1e-9 {
+		t.Errorf("Expected decay penalty to equal the R delta %.2f, got %.2f", delta, after.DecayPenalty)
+	}
+	if math.Abs(after.DecayPenalty-0.54) > 1e-9 {
+		t.Errorf("Expected decay penalty 0.54, got %f", after.DecayPenalty)
+	}
+}
+
+func TestCalculateReliability_WaiverSuspendsDecay(t *testing.T) {
+	store := setupTestStore(t)
+	ctx := context.Background()
+	day := 24 * time.Hour
+
+	expiry := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	addTestEvidence(t, store, "e1", "A", "pass", expiry)
+	if err := store.CreateWaiver(ctx, "w1", "e1", "user", expiry.Add(10*day), "re-run next sprint"); err != nil {
+		t.Fatalf("CreateWaiver failed: %v", err)
+	}
+
+	calc := New(store)
+	calc.Decay, _ = ParseDecayProfiles("linear:10d")
+	at := func(d time.Time) *AssuranceReport {
+		t.Helper()
+		calc.Now = func() time.Time { return d }
+		report, err := calc.CalculateReliability(ctx, "A")
+		if err != nil {
+			t.Fatalf("CalculateReliability failed: %v", err)
+		}
+		return report
+	}
+
+	if r := at(expiry.Add(5 * day)); r.FinalScore != 1.0 || !r.Evidence[0].Waived || r.Evidence[0].Expired {
+		t.Errorf("Expected the waiver to suspend decay, got %f %+v", r.FinalScore, r.Evidence[0])
+	}
+	// Decay starts when the waiver runs out, not at valid_until.
+	if r := at(expiry.Add(15 * day)); math.Abs(r.FinalScore-0.55) > 1e-9 || r.Evidence[0].Waived {
+		t.Errorf("Expected five days of linear decay after the waiver, got %f %+v", r.FinalScore, r.Evidence[0])
+	}
+}
+
+func TestCalculateReliability_EvidenceWeighting(t *testing.T) {
+	store := setupTestStore(t)
+	ctx := context.Background()
+
+	// An agent's reasoning passes; the ingested load test degrades.
+	if err := store.AddEvidence(ctx, "reasoning", "A", "verification", "", "pass", "L1", "internal-logic", ""); err != nil {
+		t.Fatalf("failed to insert evidence: %v", err)
+	}
+	if err := store.AddEvidence(ctx, "load-test", "A", "test-report", "", "degrade", "L2", "load.json#sha256=ab12", ""); err != nil {
+		t.Fatalf("failed to insert evidence: %v", err)
+	}
+
+	calc := New(store)
+	report, _ := calc.CalculateReliability(ctx, "A")
+	if report.FinalScore != 0.75 {
+		t.Errorf("Expected the plain mean 0.75 by default, got %f", report.FinalScore)
+	}
+
+	calc.Weighting, _ = ParseEvidenceWeighting("class:empirical=2,class:other=0.5,level:L2=1.5")
+	report, _ = calc.CalculateReliability(ctx, "A")
+	// Weights 0.5 and 3: (0.5·1 + 3·0.5) / 3.5
+	if math.Abs(report.FinalScore-2.0/3.5) > 1e-9 {
+		t.Errorf("Expected the load test to dominate at %f, got %f", 2.0/3.5, report.FinalScore)
+	}
+	for _, e := range report.Evidence {
+		if want := map[string]float64{"reasoning": 0.5, "load-test": 3}[e.ID]; e.Weight != want {
+			t.Errorf("Expected %s to weigh %.1f, got %+v", e.ID, want, e)
+		}
+	}
+	want := "Self score: weighted mean, weights class:empirical=2,class:other=0.5,level:L2=1.5"
+	if report.Factors[len(report.Factors)-1] != want {
+		t.Errorf("Expected the rule in the factors, got %v", report.Factors)
+	}
+
+	calc.Weighting, _ = ParseEvidenceWeighting("min")
+	if report, _ = calc.CalculateReliability(ctx, "A"); report.FinalScore != 0.5 {
+		t.Errorf("Expected min to take the load test's 0.5, got %f", report.FinalScore)
+	}
+
+	calc.Weighting, _ = ParseEvidenceWeighting("type:verification=0,type:test-report=0")
+	if report, _ = calc.CalculateReliability(ctx, "A"); report.FinalScore != 0 {
+		t.Errorf("Expected no self score with all evidence weighted 0, got %f", report.FinalScore)
+	}
+}
@@ -0,0 +1,220 @@
+package assurance
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Formality (F) ranges from F0 (informal prose) to F9 (machine-checked proof).
+const (
+	MinFormality = 0
+	MaxFormality = 9
+)
+
+// FormatFormality renders a formality level as "F<n>".
+func FormatFormality(f int) string {
+	return fmt.Sprintf("F%d", f)
+}
+
+// ParseFormality accepts "F3", "f3" or "3". An empty string is F0.
+func ParseFormality(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return MinFormality, nil
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(s), "F"))
+	if err != nil || n < MinFormality || n > MaxFormality {
+		return 0, fmt.Errorf("invalid formality %q: expected F0-F9", s)
+	}
+	return n, nil
+}
+
+// ClaimScope (G) is the set of conditions under which a claim holds, keyed by
+// dimension, e.g. {"env": ["prod"], "db": ["postgres", "mysql"]}.
+//
+// A dimension that is absent is unconstrained, so a nil ClaimScope holds
+// everywhere. A dimension present with no values holds nowhere: the claim
+// is vacuous.
+type ClaimScope map[string][]string
+
+// ParseClaimScope accepts a JSON object ({"env":["prod"]}) or the shorthand
+// "env=prod,staging; region=eu". Empty input and "*" are unbounded.
+func ParseClaimScope(s string) (ClaimScope, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "*" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(s, "{") {
+		var raw map[string][]string
+		if err := json.Unmarshal([]byte(s), &raw); err != nil {
+			return nil, fmt.Errorf("invalid claim scope JSON: %w", err)
+		}
+		return normalizeClaimScope(raw), nil
+	}
+
+	raw := make(map[string][]string)
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		dim, values, ok := strings.Cut(part, "=")
+		dim = strings.TrimSpace(dim)
+		if !ok || dim == "" {
+			return nil, fmt.Errorf("invalid claim scope %q: expected dimension=value[,value]", part)
+		}
+		vals := []string{}
+		for _, v := range strings.Split(values, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				vals = append(vals, v)
+			}
+		}
+		raw[dim] = append(raw[dim], vals...)
+	}
+	return normalizeClaimScope(raw), nil
+}
+
+func normalizeClaimScope(raw map[string][]string) ClaimScope {
+	if len(raw) == 0 {
+		return nil
+	}
+	g := make(ClaimScope, len(raw))
+	for dim, values := range raw {
+		g[dim] = dedupeSorted(values)
+	}
+	return g
+}
+
+func dedupeSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := []string{}
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// IsUnbounded reports whether the scope places no constraint at all.
+func (g ClaimScope) IsUnbounded() bool {
+	return len(g) == 0
+}
+
+// IsEmpty reports whether some dimension admits no value, i.e. the claim
+// holds nowhere.
+func (g ClaimScope) IsEmpty() bool {
+	for _, values := range g {
+		if len(values) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Intersect returns the conditions under which both claims hold. Used for
+// parts of a whole: the whole is only supported where every part is.
+func (g ClaimScope) Intersect(other ClaimScope) ClaimScope {
+	if g.IsUnbounded() {
+		return other.clone()
+	}
+	if other.IsUnbounded() {
+		return g.clone()
+	}
+
+	out := g.clone()
+	for dim, values := range other {
+		current, ok := out[dim]
+		if !ok {
+			out[dim] = append([]string{}, values...)
+			continue
+		}
+		allowed := make(map[string]bool, len(values))
+		for _, v := range values {
+			allowed[v] = true
+		}
+		kept := []string{}
+		for _, v := range current {
+			if allowed[v] {
+				kept = append(kept, v)
+			}
+		}
+		out[dim] = kept
+	}
+	return out
+}
+
+// Union returns the conditions under which at least one claim holds. Used
+// for parallel members: a dimension constrained by only one side becomes
+// unconstrained because the other side already covers it.
+func (g ClaimScope) Union(other ClaimScope) ClaimScope {
+	if g.IsUnbounded() || other.IsUnbounded() {
+		return nil
+	}
+	if g.IsEmpty() {
+		return other.clone()
+	}
+	if other.IsEmpty() {
+		return g.clone()
+	}
+
+	out := make(ClaimScope)
+	for dim, values := range g {
+		if otherValues, ok := other[dim]; ok {
+			out[dim] = dedupeSorted(append(append([]string{}, values...), otherValues...))
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func (g ClaimScope) clone() ClaimScope {
+	if g == nil {
+		return nil
+	}
+	out := make(ClaimScope, len(g))
+	for dim, values := range g {
+		out[dim] = append([]string{}, values...)
+	}
+	return out
+}
+
+// String renders the scope in shorthand form; "*" is unbounded and "∅"
+// marks a dimension with no admissible value.
+func (g ClaimScope) String() string {
+	if g.IsUnbounded() {
+		return "*"
+	}
+	dims := make([]string, 0, len(g))
+	for dim := range g {
+		dims = append(dims, dim)
+	}
+	sort.Strings(dims)
+
+	parts := make([]string, 0, len(dims))
+	for _, dim := range dims {
+		values := strings.Join(g[dim], ",")
+		if values == "" {
+			values = "∅"
+		}
+		parts = append(parts, dim+"="+values)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// JSON returns the canonical storage form, or "" for an unbounded scope.
+func (g ClaimScope) JSON() string {
+	if g.IsUnbounded() {
+		return ""
+	}
+	data, _ := json.Marshal(map[string][]string(g))
+	return string(data)
+}
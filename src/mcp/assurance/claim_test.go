@@ -0,0 +1,76 @@
+package assurance
+
+import "testing"
+
+func TestParseFormality(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"F3", 3, false},
+		{"f9", 9, false},
+		{"4", 4, false},
+		{"F10", 0, true},
+		{"high", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormality(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormality(%q) = %d, %v; want %d, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseClaimScope(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "*"},
+		{"*", "*"},
+		{"env=prod,staging; db=postgres", "db=postgres; env=prod,staging"},
+		{`{"env":["staging","prod","prod"]}`, "env=prod,staging"},
+		{"env=", "env=∅"},
+	}
+	for _, tt := range tests {
+		g, err := ParseClaimScope(tt.in)
+		if err != nil {
+			t.Fatalf("ParseClaimScope(%q) failed: %v", tt.in, err)
+		}
+		if got := g.String(); got != tt.want {
+			t.Errorf("ParseClaimScope(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := ParseClaimScope("just text"); err == nil {
+		t.Error("Expected error for scope without dimension")
+	}
+}
+
+func TestClaimScope_IntersectUnion(t *testing.T) {
+	a, _ := ParseClaimScope("env=prod,staging; db=postgres")
+	b, _ := ParseClaimScope("env=prod; region=eu")
+	none, _ := ParseClaimScope("env=dev")
+
+	if got := a.Intersect(b).String(); got != "db=postgres; env=prod; region=eu" {
+		t.Errorf("Intersect = %q", got)
+	}
+	if got := a.Intersect(nil).String(); got != a.String() {
+		t.Errorf("Intersect with unbounded should be identity, got %q", got)
+	}
+	if !a.Intersect(none).IsEmpty() {
+		t.Error("Expected disjoint intersection to be empty")
+	}
+
+	if got := a.Union(b).String(); got != "env=prod,staging" {
+		t.Errorf("Union = %q", got)
+	}
+	if !a.Union(nil).IsUnbounded() {
+		t.Error("Union with unbounded should be unbounded")
+	}
+	if got := a.Intersect(none).Union(b).String(); got != b.String() {
+		t.Errorf("Union with empty should be identity, got %q", got)
+	}
+}
@@ -0,0 +1,179 @@
+package assurance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decay profiles for evidence past its valid_until.
+const (
+	DecayStep     = "step"
+	DecayLinear   = "linear"
+	DecayHalfLife = "half-life"
+	DecayNone     = "none"
+)
+
+// DecayFloor is the factor expired evidence decays to: not zero, because
+// expired evidence still says more than no evidence at all.
+const DecayFloor = 0.1
+
+// DecayProfile is one decay curve.
+type DecayProfile struct {
+	Name   string
+	Period time.Duration // linear: time to reach the floor; half-life: time to halve
+}
+
+// DecayProfiles maps evidence types to decay profiles.
+type DecayProfiles struct {
+	Default DecayProfile
+	ByType  map[string]DecayProfile
+}
+
+// DefaultDecayProfiles decays every type with a step at valid_until.
+var DefaultDecayProfiles = DecayProfiles{Default: DecayProfile{Name: DecayStep}}
+
+// ParseDecayProfile parses a single profile spec. An empty spec is step.
+func ParseDecayProfile(spec string) (DecayProfile, error) {
+	name, args, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	switch name {
+	case "", DecayStep, DecayNone:
+		if args != "" {
+			return DecayProfile{}, fmt.Errorf("decay profile %s takes no parameters", name)
+		}
+		if name == "" {
+			name = DecayStep
+		}
+		return DecayProfile{Name: name}, nil
+
+	case DecayLinear, DecayHalfLife:
+		if args == "" {
+			return DecayProfile{}, fmt.Errorf("decay profile %s needs a period, e.g. %s:30d", name, name)
+		}
+		period, err := parseDecayPeriod(args)
+		if err != nil {
+			return DecayProfile{}, err
+		}
+		return DecayProfile{Name: name, Period: period}, nil
+	}
+	return DecayProfile{}, fmt.Errorf("unknown decay profile %q (expected %s, %s, %s or %s)", name, DecayStep, DecayLinear, DecayHalfLife, DecayNone)
+}
+
+// ParseDecayProfiles parses a comma-separated list of [type=]profile specs,
+// e.g. "step,benchmark=half-life:30d". A bare profile is the default for
+// types without one of their own; an empty spec is DefaultDecayProfiles.
+func ParseDecayProfiles(spec string) (DecayProfiles, error) {
+	d := DecayProfiles{Default: DefaultDecayProfiles.Default}
+	for _, part := range strings.Split(spec, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		typ, profile, hasType := strings.Cut(part, "=")
+		if !hasType {
+			profile, typ = typ, ""
+		}
+		p, err := ParseDecayProfile(profile)
+		if err != nil {
+			return DecayProfiles{}, err
+		}
+		typ = strings.ToLower(strings.TrimSpace(typ))
+		if !hasType || typ == "*" {
+			d.Default = p
+			continue
+		}
+		if typ == "" {
+			return DecayProfiles{}, fmt.Errorf("missing evidence type in %q", part)
+		}
+		if d.ByType == nil {
+			d.ByType = make(map[string]DecayProfile)
+		}
+		d.ByType[typ] = p
+	}
+	return d, nil
+}
+
+// For returns the profile of an evidence type.
+func (d DecayProfiles) For(evidenceType string) DecayProfile {
+	if p, ok := d.ByType[strings.ToLower(evidenceType)]; ok {
+		return p
+	}
+	if d.Default.Name == "" {
+		return DefaultDecayProfiles.Default
+	}
+	return d.Default
+}
+
+// Factor is how much of a score is left elapsed time after decay started.
+func (p DecayProfile) Factor(elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 1
+	}
+	switch p.Name {
+	case DecayNone:
+		return 1
+	case DecayLinear:
+		return math.Max(DecayFloor, 1-(1-DecayFloor)*float64(elapsed)/float64(p.Period))
+	case DecayHalfLife:
+		return math.Max(DecayFloor, math.Pow(0.5, float64(elapsed)/float64(p.Period)))
+	}
+	return DecayFloor
+}
+
+// Spec returns the canonical spec, which round-trips through
+// ParseDecayProfile.
+func (p DecayProfile) Spec() string {
+	if p.Period == 0 {
+		return p.Name
+	}
+	return p.Name + ":" + formatDecayPeriod(p.Period)
+}
+
+// Spec returns the canonical spec, with the default first and types in
+// alphabetical order.
+func (d DecayProfiles) Spec() string {
+	parts := []string{d.For("").Spec()}
+	types := make([]string, 0, len(d.ByType))
+	for typ := range d.ByType {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	for _, typ := range types {
+		parts = append(parts, typ+"="+d.ByType[typ].Spec())
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseDecayPeriod parses a Go duration or whole days and weeks ("30d", "2w").
+func parseDecayPeriod(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	var period time.Duration
+	var err error
+	switch {
+	case strings.HasSuffix(s, "d"), strings.HasSuffix(s, "w"):
+		var n int
+		n, err = strconv.Atoi(s[:len(s)-1])
+		period = time.Duration(n) * 24 * time.Hour
+		if strings.HasSuffix(s, "w") {
+			period *= 7
+		}
+	default:
+		period, err = time.ParseDuration(s)
+	}
+	if err != nil || period <= 0 {
+		return 0, fmt.Errorf("invalid decay period %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return period, nil
+}
+
+func formatDecayPeriod(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return d.String()
+}
@@ -0,0 +1,72 @@
+package assurance
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseDecayProfiles(t *testing.T) {
+	tests := []struct {
+		spec     string
+		wantSpec string
+	}{
+		{"", "step"},
+		{"none", "none"},
+		{"step, benchmark=half-life:30d", "step,benchmark=half-life:30d"},
+		{"external=linear:2w,*=none,Test=linear:36h", "none,external=linear:14d,test=linear:36h0m0s"},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecayProfiles(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseDecayProfiles(%q) failed: %v", tt.spec, err)
+		}
+		if d.Spec() != tt.wantSpec {
+			t.Errorf("%q: Spec() = %q, want %q", tt.spec, d.Spec(), tt.wantSpec)
+		}
+		if again, err := ParseDecayProfiles(d.Spec()); err != nil || again.Spec() != d.Spec() {
+			t.Errorf("%q: Spec() does not round-trip: %v %v", tt.spec, again, err)
+		}
+	}
+
+	d, _ := ParseDecayProfiles("none,benchmark=half-life:30d")
+	if p := d.For("Benchmark"); p.Name != DecayHalfLife || p.Period != 30*24*time.Hour {
+		t.Errorf("Expected benchmark to use half-life:30d, got %+v", p)
+	}
+	if p := d.For("test"); p.Name != DecayNone {
+		t.Errorf("Expected other types to use the default, got %+v", p)
+	}
+
+	for _, bad := range []string{"cliff", "linear", "half-life:soon", "linear:-3d", "step:1d", "=none"} {
+		if _, err := ParseDecayProfiles(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestDecayProfile_Factor(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		spec    string
+		elapsed time.Duration
+		want    float64
+	}{
+		{"step", 0, 1},
+		{"step", time.Second, DecayFloor},
+		{"none", 365 * day, 1},
+		{"linear:10d", 5 * day, 0.55},
+		{"linear:10d", 20 * day, DecayFloor},
+		{"half-life:10d", 10 * day, 0.5},
+		{"half-life:10d", 20 * day, 0.25},
+		{"half-life:10d", 100 * day, DecayFloor},
+	}
+	for _, tt := range tests {
+		p, err := ParseDecayProfile(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseDecayProfile(%q) failed: %v", tt.spec, err)
+		}
+		if got := p.Factor(tt.elapsed); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s after %v: factor %f, want %f", tt.spec, tt.elapsed, got, tt.want)
+		}
+	}
+}
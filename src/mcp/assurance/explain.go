@@ -0,0 +1,145 @@
+package assurance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Action is one validation step and the R_eff it would lead to.
+type Action struct {
+	Description string   `json:"description"`
+	HolonIDs    []string `json:"holon_ids"`
+	EvidenceIDs []string `json:"evidence_ids,omitempty"`
+	REff        float64  `json:"r_eff"`
+	Gain        float64  `json:"gain"`
+}
+
+// gainEpsilon ignores gains that are only floating point noise.
+const gainEpsilon = 1e-9
+
+// Explain calculates the report of a holon and ranks the actions that would
+// raise its R_eff, highest gain first, scoring each over an Overlay. Actions
+// that gain nothing are left out.
+func (c *Calculator) Explain(ctx context.Context, holonID string) (*AssuranceReport, error) {
+	report, err := c.CalculateReliability(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := c.candidateActions(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range candidates {
+		overlay := NewOverlay(c.DB)
+		for _, id := range a.EvidenceIDs {
+			overlay.RefreshEvidence(id)
+		}
+		if len(a.EvidenceIDs) == 0 {
+			for _, id := range a.HolonIDs {
+				overlay.AddEvidence(id, "pass", time.Time{})
+			}
+		}
+		calc := &Calculator{DB: overlay, Phi: c.Phi, Now: c.Now}
+		after, err := calc.CalculateReliability(ctx, holonID)
+		if err != nil {
+			return nil, err
+		}
+		if gain := after.FinalScore - report.FinalScore; gain > gainEpsilon {
+			a.REff, a.Gain = after.FinalScore, gain
+			report.Actions = append(report.Actions, a)
+		}
+	}
+	sort.SliceStable(report.Actions, func(i, j int) bool {
+		return report.Actions[i].Gain > report.Actions[j].Gain
+	})
+	return report, nil
+}
+
+// candidateActions lists the validation steps across the holon and
+// everything it depends on, walking the dependencies breadth first.
+func (c *Calculator) candidateActions(ctx context.Context, root *AssuranceReport) ([]Action, error) {
+	var actions []Action
+	seen := map[string]bool{}
+	queue := []string{root.HolonID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		r, err := c.CalculateReliability(ctx, id)
+		if err != nil {
+			continue
+		}
+		var weak []string
+		for _, e := range r.Evidence {
+			if e.Score < 1 {
+				weak = append(weak, e.ID)
+				actions = append(actions, Action{
+					Description: fmt.Sprintf("Refresh %s evidence %s of %s", evidenceState(e), e.ID, id),
+					HolonIDs:    []string{id},
+					EvidenceIDs: []string{e.ID},
+				})
+			}
+		}
+		switch {
+		case len(r.Evidence) == 0:
+			actions = append(actions, Action{
+				Description: fmt.Sprintf("Test %s, which has no evidence", id),
+				HolonIDs:    []string{id},
+			})
+		case len(weak) > 1:
+			actions = append(actions, Action{
+				Description: fmt.Sprintf("Re-validate %s (%d evidence records)", id, len(weak)),
+				HolonIDs:    []string{id},
+				EvidenceIDs: weak,
+			})
+		}
+		for _, d := range r.Dependencies {
+			queue = append(queue, d.ID)
+		}
+	}
+
+	if len(root.WeakLinks) > 1 {
+		tied := Action{
+			Description: fmt.Sprintf("Re-validate the tied weak links %s", strings.Join(root.WeakLinks, ", ")),
+			HolonIDs:    root.WeakLinks,
+		}
+		for _, id := range root.WeakLinks {
+			r, err := c.CalculateReliability(ctx, id)
+			if err != nil {
+				continue
+			}
+			for _, e := range r.Evidence {
+				if e.Score < 1 {
+					tied.EvidenceIDs = append(tied.EvidenceIDs, e.ID)
+				}
+			}
+		}
+		if len(tied.EvidenceIDs) > 0 {
+			actions = append(actions, tied)
+		}
+	}
+	return actions, nil
+}
+
+// evidenceState says why evidence does not count in full.
+func evidenceState(e EvidenceScore) string {
+	var states []string
+	if e.Expired {
+		states = append(states, "expired")
+	}
+	if e.Suspect {
+		states = append(states, "suspect")
+	}
+	if v := strings.ToLower(e.Verdict); v != "pass" {
+		states = append(states, v)
+	}
+	return strings.Join(states, ", ")
+}
@@ -0,0 +1,101 @@
+package assurance
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestExplain(t *testing.T) {
+	ctx := context.Background()
+	week := time.Now().Add(7 * 24 * time.Hour)
+
+	t.Run("ranks actions by gain", func(t *testing.T) {
+		store := setupTestStore(t)
+		addTestEvidence(t, store, "e-a", "A", "pass", week)
+		addTestRelation(t, store, "B", "componentOf", "A", 3)
+		addTestEvidence(t, store, "e-b1", "B", "fail", week)
+		addTestEvidence(t, store, "e-b2", "B", "degrade", week)
+		addTestEvidence(t, store, "e-b3", "B", "pass", week)
+
+		report, err := New(store).Explain(ctx, "A")
+		if err != nil {
+			t.Fatalf("Explain failed: %v", err)
+		}
+		if want := []string{"A", "B"}; !reflect.DeepEqual(report.CriticalPath, want) {
+			t.Errorf("Expected critical path %v, got %v", want, report.CriticalPath)
+		}
+		if want := []string{"e-b1"}; !reflect.DeepEqual(report.BoundingEvidence, want) {
+			t.Errorf("Expected bounding evidence %v, got %v", want, report.BoundingEvidence)
+		}
+
+		want := []struct {
+			evidence []string
+			gain     float64
+		}{
+			{[]string{"e-b1", "e-b2"}, 0.5}, // B at 1.0
+			{[]string{"e-b1"}, 2.5/3 - 0.5}, // B at 0.83
+			{[]string{"e-b2"}, 2.0/3 - 0.5}, // B at 0.67
+		}
+		if len(report.Actions) != len(want) {
+			t.Fatalf("Expected %d actions, got %+v", len(want), report.Actions)
+		}
+		for i, w := range want {
+			a := report.Actions[i]
+			sort.Strings(a.EvidenceIDs)
+			if !reflect.DeepEqual(a.EvidenceIDs, w.evidence) || math.Abs(a.Gain-w.gain) > 1e-9 {
+				t.Errorf("Action %d: expected %v with gain %.3f, got %v with gain %.3f", i+1, w.evidence, w.gain, a.EvidenceIDs, a.Gain)
+			}
+		}
+
+		// Explaining does not change the store.
+		if r, _ := New(store).CalculateReliability(ctx, "B"); r.FinalScore != 0.5 {
+			t.Errorf("Expected B to stay at 0.5, got %f", r.FinalScore)
+		}
+	})
+
+	t.Run("tied weak links", func(t *testing.T) {
+		store := setupTestStore(t)
+		addTestEvidence(t, store, "e-a", "A", "pass", week)
+		addTestRelation(t, store, "B", "componentOf", "A", 3)
+		addTestRelation(t, store, "C", "componentOf", "A", 3)
+		addTestEvidence(t, store, "e-b", "B", "degrade", week)
+		addTestEvidence(t, store, "e-c", "C", "degrade", week)
+
+		report, err := New(store).Explain(ctx, "A")
+		if err != nil {
+			t.Fatalf("Explain failed: %v", err)
+		}
+		if want := []string{"B", "C"}; !reflect.DeepEqual(report.WeakLinks, want) {
+			t.Errorf("Expected weak links %v, got %v", want, report.WeakLinks)
+		}
+		// Refreshing B or C alone leaves the other holding A at 0.5.
+		if len(report.Actions) != 1 {
+			t.Fatalf("Expected only the combined action to gain, got %+v", report.Actions)
+		}
+		if a := report.Actions[0]; !reflect.DeepEqual(a.HolonIDs, []string{"B", "C"}) || a.REff != 1.0 {
+			t.Errorf("Expected re-validating B and C to reach 1.0, got %+v", a)
+		}
+	})
+
+	t.Run("bounded by own evidence", func(t *testing.T) {
+		store := setupTestStore(t)
+		addTestEvidence(t, store, "e-a", "A", "degrade", week)
+		addTestRelation(t, store, "A", "dependsOn", "B", 3)
+		addTestEvidence(t, store, "e-b", "B", "pass", week)
+
+		report, err := New(store).Explain(ctx, "A")
+		if err != nil {
+			t.Fatalf("Explain failed: %v", err)
+		}
+		if !reflect.DeepEqual(report.CriticalPath, []string{"A"}) || !reflect.DeepEqual(report.BoundingEvidence, []string{"e-a"}) {
+			t.Errorf("Expected A's own evidence to bound it, got path %v and evidence %v", report.CriticalPath, report.BoundingEvidence)
+		}
+		if len(report.WeakLinks) != 0 {
+			t.Errorf("Expected no weak links below self, got %v", report.WeakLinks)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package assurance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// Overlay is a Store that layers hypothetical changes over another Store, so
+// a Calculator can answer "what if" without anything being written: added
+// evidence, evidence expiring on another date, a relation at another CL, or
+// a dependency removed. Cached scores are discarded.
+type Overlay struct {
+	base       Store
+	added      map[string][]db.Evidence // holon ID → hypothetical evidence
+	validUntil map[string]time.Time     // evidence ID → overridden valid_until
+	refreshed  map[string]bool          // evidence IDs re-run and passed
+	cl         map[dependency]int
+	removed    map[dependency]bool
+}
+
+// dependency is an edge from a holon to something it relies on, whether
+// recorded as componentOf or dependsOn.
+type dependency struct {
+	holon, on string
+}
+
+// NewOverlay returns an Overlay over base with no changes.
+func NewOverlay(base Store) *Overlay {
+	return &Overlay{
+		base:       base,
+		added:      make(map[string][]db.Evidence),
+		validUntil: make(map[string]time.Time),
+		refreshed:  make(map[string]bool),
+		cl:         make(map[dependency]int),
+		removed:    make(map[dependency]bool),
+	}
+}
+
+// AddEvidence adds hypothetical evidence with the given verdict to a holon
+// and returns its ID. A zero validUntil never expires.
+func (o *Overlay) AddEvidence(holonID, verdict string, validUntil time.Time) string {
+	n := 0
+	for _, ev := range o.added {
+		n += len(ev)
+	}
+	e := db.Evidence{
+		ID:      fmt.Sprintf("what-if-%d", n+1),
+		HolonID: holonID,
+		Type:    "what-if",
+		Verdict: verdict,
+	}
+	if !validUntil.IsZero() {
+		e.ValidUntil = sql.NullTime{Time: validUntil, Valid: true}
+	}
+	o.added[holonID] = append(o.added[holonID], e)
+	return e.ID
+}
+
+// ExpireEvidence makes evidence expire at at: evaluated at that time or
+// later, it no longer counts as valid.
+func (o *Overlay) ExpireEvidence(evidenceID string, at time.Time) {
+	o.validUntil[evidenceID] = at.Add(-time.Nanosecond)
+}
+
+// RefreshEvidence treats evidence as re-run today and passed: no longer
+// failed, expired or suspect.
+func (o *Overlay) RefreshEvidence(evidenceID string) {
+	o.refreshed[evidenceID] = true
+	delete(o.validUntil, evidenceID)
+}
+
+// SetCongruence changes the CL of holonID's dependency on dependencyID.
+func (o *Overlay) SetCongruence(holonID, dependencyID string, cl int) {
+	o.cl[dependency{holonID, dependencyID}] = cl
+}
+
+// RemoveDependency drops holonID's dependency on dependencyID.
+func (o *Overlay) RemoveDependency(holonID, dependencyID string) {
+	o.removed[dependency{holonID, dependencyID}] = true
+}
+
+func (o *Overlay) GetHolon(ctx context.Context, id string) (db.Holon, error) {
+	return o.base.GetHolon(ctx, id)
+}
+
+func (o *Overlay) GetEvidence(ctx context.Context, holonID string) ([]db.Evidence, error) {
+	evidence, err := o.base.GetEvidence(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+	evidence = append(append([]db.Evidence(nil), evidence...), o.added[holonID]...)
+	for i, e := range evidence {
+		if at, ok := o.validUntil[e.ID]; ok {
+			evidence[i].ValidUntil = sql.NullTime{Time: at, Valid: true}
+		}
+		if o.refreshed[e.ID] {
+			evidence[i].Verdict = "pass"
+			evidence[i].ValidUntil = sql.NullTime{}
+		}
+	}
+	return evidence, nil
+}
+
+func (o *Overlay) GetSuspectEvidenceByHolon(ctx context.Context, holonID string) ([]db.SuspectEvidence, error) {
+	suspects, err := o.base.GetSuspectEvidenceByHolon(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+	var out []db.SuspectEvidence
+	for _, s := range suspects {
+		if !o.refreshed[s.EvidenceID] {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (o *Overlay) GetWaiversByEvidence(ctx context.Context, evidenceID string) ([]db.Waiver, error) {
+	return o.base.GetWaiversByEvidence(ctx, evidenceID)
+}
+
+// GetComponentsOf returns the parts of targetID: the whole depends on each
+// part.
+func (o *Overlay) GetComponentsOf(ctx context.Context, targetID string) ([]db.GetComponentsOfRow, error) {
+	rows, err := o.base.GetComponentsOf(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	var out []db.GetComponentsOfRow
+	for _, r := range rows {
+		d := dependency{targetID, r.SourceID}
+		if o.removed[d] {
+			continue
+		}
+		if cl, ok := o.cl[d]; ok {
+			r.CongruenceLevel = sql.NullInt64{Int64: int64(cl), Valid: true}
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (o *Overlay) GetRelationsBySource(ctx context.Context, sourceID, relationType string) ([]db.Relation, error) {
+	rels, err := o.base.GetRelationsBySource(ctx, sourceID, relationType)
+	if err != nil || relationType != "dependsOn" {
+		return rels, err
+	}
+	var out []db.Relation
+	for _, r := range rels {
+		d := dependency{sourceID, r.TargetID}
+		if o.removed[d] {
+			continue
+		}
+		if cl, ok := o.cl[d]; ok {
+			r.CongruenceLevel = sql.NullInt64{Int64: int64(cl), Valid: true}
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (o *Overlay) GetCollectionMembers(ctx context.Context, targetID string) ([]db.GetCollectionMembersRow, error) {
+	return o.base.GetCollectionMembers(ctx, targetID)
+}
+
+// CacheHolonRScore discards the score: simulated results are never cached.
+func (o *Overlay) CacheHolonRScore(ctx context.Context, id string, score float64) error {
+	return nil
+}
@@ -0,0 +1,65 @@
+package assurance
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestOverlay(t *testing.T) {
+	store := setupTestStore(t)
+	ctx := context.Background()
+	week := time.Now().Add(7 * 24 * time.Hour)
+
+	addTestHolon(t, store, "A", 0, "")
+	addTestEvidence(t, store, "e1", "A", "pass", week)
+	addTestEvidence(t, store, "e2", "B", "fail", week)
+	addTestRelation(t, store, "B", "componentOf", "A", 3)
+	addTestRelation(t, store, "A", "dependsOn", "C", 3)
+	addTestEvidence(t, store, "e3", "C", "pass", week)
+
+	score := func(calc *Calculator) float64 {
+		t.Helper()
+		report, err := calc.CalculateReliability(ctx, "A")
+		if err != nil {
+			t.Fatalf("CalculateReliability failed: %v", err)
+		}
+		return report.FinalScore
+	}
+
+	o := NewOverlay(store)
+	if got := score(New(o)); got != 0.0 {
+		t.Fatalf("Expected B to hold A at 0.0, got %f", got)
+	}
+
+	o.AddEvidence("B", "pass", time.Time{})
+	if got := score(New(o)); got != 0.5 {
+		t.Errorf("Expected a passing what-if test to lift B and A to 0.5, got %f", got)
+	}
+	o.RemoveDependency("A", "B")
+	if got := score(New(o)); got != 1.0 {
+		t.Errorf("Expected A at 1.0 without B, got %f", got)
+	}
+	o.SetCongruence("A", "C", 1)
+	if got := score(New(o)); got != 0.6 {
+		t.Errorf("Expected Φ(CL1) on C to give 0.6, got %f", got)
+	}
+	if h, _ := store.GetHolon(ctx, "A"); h.CachedRScore.Float64 != 0 {
+		t.Errorf("Expected simulated scores not to be cached, got %v", h.CachedRScore.Float64)
+	}
+
+	o.ExpireEvidence("e3", time.Now().Add(24*time.Hour))
+	calc := New(o)
+	calc.Now = func() time.Time { return time.Now().Add(48 * time.Hour) }
+	if got := score(calc); got != 0.0 {
+		t.Errorf("Expected C's expired evidence to bring A to 0.0 in two days, got %f", got)
+	}
+
+	// Nothing reached the store.
+	if ev, _ := store.GetEvidence(ctx, "B"); len(ev) != 1 {
+		t.Errorf("Expected the what-if evidence to stay in the overlay, got %d records", len(ev))
+	}
+	if report, _ := New(store).CalculateReliability(ctx, "A"); report.FinalScore != 0.0 {
+		t.Errorf("Expected the store to be unchanged, got %f", report.FinalScore)
+	}
+}
@@ -0,0 +1,127 @@
+package assurance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Φ(CL) profiles: fpf-b13, linear[:max] and custom:p0,p1,p2,p3.
+const (
+	ProfileNormative = "fpf-b13"
+	ProfileLinear    = "linear"
+	ProfileCustom    = "custom"
+)
+
+const defaultLinearMax = 0.9
+
+// CLPenaltyProfile is a named Φ(CL) table indexed by congruence level.
+type CLPenaltyProfile struct {
+	Name      string
+	Penalties [4]float64
+}
+
+// DefaultCLPenaltyProfile is the FPF B.1.3 normative table.
+var DefaultCLPenaltyProfile = CLPenaltyProfile{
+	Name:      ProfileNormative,
+	Penalties: [4]float64{0.9, 0.4, 0.1, 0.0},
+}
+
+// ParseCLPenaltyProfile parses a profile spec. An empty spec is the
+// normative table.
+func ParseCLPenaltyProfile(spec string) (CLPenaltyProfile, error) {
+	spec = strings.TrimSpace(spec)
+	name, args, _ := strings.Cut(spec, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	var p CLPenaltyProfile
+	switch name {
+	case "", ProfileNormative:
+		if args != "" {
+			return p, fmt.Errorf("profile %s takes no parameters", ProfileNormative)
+		}
+		return DefaultCLPenaltyProfile, nil
+
+	case ProfileLinear:
+		max := defaultLinearMax
+		if args != "" {
+			v, err := strconv.ParseFloat(strings.TrimSpace(args), 64)
+			if err != nil {
+				return p, fmt.Errorf("invalid linear profile max %q: %w", args, err)
+			}
+			max = v
+		}
+		p = CLPenaltyProfile{Name: ProfileLinear}
+		for cl := 0; cl <= 3; cl++ {
+			p.Penalties[cl] = max * float64(3-cl) / 3
+		}
+
+	case ProfileCustom:
+		parts := strings.Split(args, ",")
+		if len(parts) != 4 {
+			return p, fmt.Errorf("custom profile needs 4 penalties (CL0..CL3), got %d", len(parts))
+		}
+		p = CLPenaltyProfile{Name: ProfileCustom}
+		for cl, part := range parts {
+			v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return p, fmt.Errorf("invalid penalty for CL%d: %w", cl, err)
+			}
+			p.Penalties[cl] = v
+		}
+
+	default:
+		return p, fmt.Errorf("unknown CL penalty profile %q (expected %s, %s or %s)", name, ProfileNormative, ProfileLinear, ProfileCustom)
+	}
+
+	return p, p.validate()
+}
+
+// validate enforces the B.1.3 constraints: penalties lie in [0,1] and never
+// grow as congruence improves.
+func (p CLPenaltyProfile) validate() error {
+	for cl, v := range p.Penalties {
+		if v < 0 || v > 1 {
+			return fmt.Errorf("penalty for CL%d must be within [0,1], got %.2f", cl, v)
+		}
+		if cl > 0 && v > p.Penalties[cl-1] {
+			return fmt.Errorf("penalty must not increase with CL: CL%d=%.2f > CL%d=%.2f", cl, v, cl-1, p.Penalties[cl-1])
+		}
+	}
+	return nil
+}
+
+// Penalty returns Φ(CL). Levels outside 0..3 are clamped.
+func (p CLPenaltyProfile) Penalty(cl int) float64 {
+	if cl < 0 {
+		cl = 0
+	}
+	if cl > 3 {
+		cl = 3
+	}
+	return p.Penalties[cl]
+}
+
+// Spec returns the canonical spec, which round-trips through
+// ParseCLPenaltyProfile.
+func (p CLPenaltyProfile) Spec() string {
+	switch p.Name {
+	case ProfileNormative:
+		return ProfileNormative
+	case ProfileLinear:
+		return ProfileLinear + ":" + formatPenalty(p.Penalties[0])
+	}
+	return fmt.Sprintf("%s:%s,%s,%s,%s", ProfileCustom,
+		formatPenalty(p.Penalties[0]), formatPenalty(p.Penalties[1]),
+		formatPenalty(p.Penalties[2]), formatPenalty(p.Penalties[3]))
+}
+
+// String identifies the profile together with its resolved table.
+func (p CLPenaltyProfile) String() string {
+	return fmt.Sprintf("%s [CL0=%.2f CL1=%.2f CL2=%.2f CL3=%.2f]", p.Name,
+		p.Penalties[0], p.Penalties[1], p.Penalties[2], p.Penalties[3])
+}
+
+func formatPenalty(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,39 @@
+package assurance
+
+import "testing"
+
+func TestParseCLPenaltyProfile(t *testing.T) {
+	tests := []struct {
+		spec     string
+		want     [4]float64
+		wantSpec string
+	}{
+		{"", [4]float64{0.9, 0.4, 0.1, 0.0}, "fpf-b13"},
+		{"fpf-b13", [4]float64{0.9, 0.4, 0.1, 0.0}, "fpf-b13"},
+		{"linear:0.6", [4]float64{0.6, 0.4, 0.2, 0.0}, "linear:0.6"},
+		{"custom:1, 0.5, 0.25, 0", [4]float64{1, 0.5, 0.25, 0}, "custom:1,0.5,0.25,0"},
+	}
+	for _, tt := range tests {
+		phi, err := ParseCLPenaltyProfile(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseCLPenaltyProfile(%q) failed: %v", tt.spec, err)
+		}
+		for cl := 0; cl <= 3; cl++ {
+			if diff := phi.Penalty(cl) - tt.want[cl]; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("%q: Φ(CL%d) = %f, want %f", tt.spec, cl, phi.Penalty(cl), tt.want[cl])
+			}
+		}
+		if phi.Spec() != tt.wantSpec {
+			t.Errorf("%q: Spec() = %q, want %q", tt.spec, phi.Spec(), tt.wantSpec)
+		}
+		if again, err := ParseCLPenaltyProfile(phi.Spec()); err != nil || again != phi {
+			t.Errorf("%q: Spec() does not round-trip: %v %v", tt.spec, again, err)
+		}
+	}
+
+	for _, bad := range []string{"steep", "custom:0.1,0.2", "custom:0.1,0.2,0.3,0.4", "linear:1.5", "fpf-b13:1"} {
+		if _, err := ParseCLPenaltyProfile(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
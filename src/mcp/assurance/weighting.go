@@ -0,0 +1,212 @@
+package assurance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// Aggregation rules for a holon's own evidence.
+const (
+	AggregateMean  = "mean"
+	AggregateMin   = "min"
+	AggregateBayes = "bayes"
+)
+
+// Carrier classes: what kind of artifact the evidence was taken from.
+const (
+	ClassFormal    = "formal"    // formal proof
+	ClassEmpirical = "empirical" // test run, benchmark, ingested report
+	ClassResearch  = "research"  // citation of external research
+	ClassAudit     = "audit"     // audit opinion
+	ClassOther     = "other"     // anything else, e.g. internal-logic reasoning
+)
+
+var carrierClasses = []string{ClassFormal, ClassEmpirical, ClassResearch, ClassAudit, ClassOther}
+
+// Weight keys name what a weight applies to.
+const (
+	weightByType  = "type"
+	weightByLevel = "level"
+	weightByClass = "class"
+)
+
+// EvidenceWeighting is an aggregation rule with evidence weights, keyed
+// "type:<evidence type>", "level:<assurance level>" or "class:<carrier class>".
+type EvidenceWeighting struct {
+	Rule    string
+	Weights map[string]float64
+}
+
+// DefaultEvidenceWeighting is the plain mean of all evidence.
+var DefaultEvidenceWeighting = EvidenceWeighting{Rule: AggregateMean}
+
+// ParseEvidenceWeighting parses a comma-separated list of a rule and
+// key=weight entries, e.g. "bayes,class:empirical=2,level:L2=1.5". An empty
+// spec is DefaultEvidenceWeighting.
+func ParseEvidenceWeighting(spec string) (EvidenceWeighting, error) {
+	w := EvidenceWeighting{Rule: AggregateMean}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, isWeight := strings.Cut(part, "=")
+		if !isWeight {
+			switch rule := strings.ToLower(part); rule {
+			case AggregateMean, AggregateMin, AggregateBayes:
+				w.Rule = rule
+			default:
+				return EvidenceWeighting{}, fmt.Errorf("unknown aggregation rule %q (expected %s, %s or %s)", part, AggregateMean, AggregateMin, AggregateBayes)
+			}
+			continue
+		}
+
+		key, err := weightKey(key)
+		if err != nil {
+			return EvidenceWeighting{}, err
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return EvidenceWeighting{}, fmt.Errorf("invalid weight %q for %s: use a number of 0 or more", value, key)
+		}
+		if w.Weights == nil {
+			w.Weights = make(map[string]float64)
+		}
+		w.Weights[key] = weight
+	}
+	return w, nil
+}
+
+// weightKey validates and normalizes a weight key.
+func weightKey(key string) (string, error) {
+	kind, name, ok := strings.Cut(strings.TrimSpace(key), ":")
+	kind, name = strings.ToLower(strings.TrimSpace(kind)), strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", fmt.Errorf("invalid weight key %q: use type:<name>, level:<L0-L2> or class:<class>", key)
+	}
+	switch kind {
+	case weightByType:
+		name = strings.ToLower(name)
+	case weightByLevel:
+		name = strings.ToUpper(name)
+		if name != "L0" && name != "L1" && name != "L2" {
+			return "", fmt.Errorf("invalid assurance level %q in %q: use L0, L1 or L2", name, key)
+		}
+	case weightByClass:
+		name = strings.ToLower(name)
+		known := false
+		for _, c := range carrierClasses {
+			known = known || c == name
+		}
+		if !known {
+			return "", fmt.Errorf("unknown carrier class %q in %q (expected %s)", name, key, strings.Join(carrierClasses, ", "))
+		}
+	default:
+		return "", fmt.Errorf("invalid weight key %q: use type:<name>, level:<L0-L2> or class:<class>", key)
+	}
+	return kind + ":" + name, nil
+}
+
+// Weight is the product of the weights that apply to the evidence.
+func (w EvidenceWeighting) Weight(e db.Evidence) float64 {
+	weight := 1.0
+	for _, key := range []string{
+		weightByType + ":" + strings.ToLower(e.Type),
+		weightByLevel + ":" + strings.ToUpper(e.AssuranceLevel.String),
+		weightByClass + ":" + EvidenceClass(e),
+	} {
+		if v, ok := w.Weights[key]; ok {
+			weight *= v
+		}
+	}
+	return weight
+}
+
+// Aggregate combines evidence scores under the rule. ok is false when no
+// evidence carries any weight.
+func (w EvidenceWeighting) Aggregate(scores, weights []float64) (score float64, ok bool) {
+	var sum, total float64
+	lowest := math.Inf(1)
+	for i, s := range scores {
+		if weights[i] == 0 {
+			continue
+		}
+		sum += weights[i] * s
+		total += weights[i]
+		lowest = math.Min(lowest, s)
+	}
+	if total == 0 {
+		return 0, false
+	}
+	switch w.Rule {
+	case AggregateMin:
+		return lowest, true
+	case AggregateBayes:
+		return (1 + sum) / (2 + total), true
+	}
+	return sum / total, true
+}
+
+// Spec returns the canonical spec, with the rule first and weights in
+// alphabetical order.
+func (w EvidenceWeighting) Spec() string {
+	parts := []string{w.rule()}
+	keys := make([]string, 0, len(w.Weights))
+	for key := range w.Weights {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, key+"="+strconv.FormatFloat(w.Weights[key], 'f', -1, 64))
+	}
+	return strings.Join(parts, ",")
+}
+
+// String describes the rule for a report's factors.
+func (w EvidenceWeighting) String() string {
+	rule := map[string]string{
+		AggregateMean:  "weighted mean",
+		AggregateMin:   "minimum",
+		AggregateBayes: "Bayesian update from a Beta(1,1) prior",
+	}[w.rule()]
+	if len(w.Weights) == 0 {
+		return rule + ", all evidence weighted 1"
+	}
+	return rule + ", weights " + strings.TrimPrefix(w.Spec(), w.rule()+",")
+}
+
+func (w EvidenceWeighting) rule() string {
+	if w.Rule == "" {
+		return AggregateMean
+	}
+	return w.Rule
+}
+
+// EvidenceClass classifies evidence by its carrier_ref and type:
+//
+//	formal      carrier "formal-logic", or a proof
+//	audit       carrier "auditor", or an audit report
+//	research    research, or a carrier URL
+//	empirical   carrier "test-runner", an ingested report digest, or a test or benchmark
+//	other       anything else, such as "internal-logic" verification
+func EvidenceClass(e db.Evidence) string {
+	ref := strings.ToLower(e.CarrierRef.String)
+	typ := strings.ToLower(e.Type)
+	switch {
+	case ref == "formal-logic" || strings.Contains(typ, "proof"):
+		return ClassFormal
+	case ref == "auditor" || strings.HasPrefix(typ, "audit"):
+		return ClassAudit
+	case typ == "research" || strings.Contains(ref, "://"):
+		return ClassResearch
+	case ref == "test-runner" || strings.Contains(ref, "#sha256="),
+		strings.Contains(typ, "test"), strings.Contains(typ, "benchmark"):
+		return ClassEmpirical
+	}
+	return ClassOther
+}
@@ -0,0 +1,82 @@
+package assurance
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+func TestParseEvidenceWeighting(t *testing.T) {
+	tests := []struct {
+		spec     string
+		wantSpec string
+	}{
+		{"", "mean"},
+		{"min", "min"},
+		{"class:Empirical=2, BAYES, level:l2=1.5, type:Audit_Report=0", "bayes,class:empirical=2,level:L2=1.5,type:audit_report=0"},
+	}
+	for _, tt := range tests {
+		w, err := ParseEvidenceWeighting(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseEvidenceWeighting(%q) failed: %v", tt.spec, err)
+		}
+		if w.Spec() != tt.wantSpec {
+			t.Errorf("%q: Spec() = %q, want %q", tt.spec, w.Spec(), tt.wantSpec)
+		}
+		if again, err := ParseEvidenceWeighting(w.Spec()); err != nil || again.Spec() != w.Spec() {
+			t.Errorf("%q: Spec() does not round-trip: %v %v", tt.spec, again, err)
+		}
+	}
+
+	for _, bad := range []string{"median", "class:opinion=1", "level:L3=1", "type:=1", "source:x=1", "class:formal=-1", "class:formal=heavy", "class:formal=NaN", "class:formal=+Inf"} {
+		if _, err := ParseEvidenceWeighting(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestEvidenceClass(t *testing.T) {
+	tests := []struct {
+		typ, ref string
+		want     string
+	}{
+		{"verification", "formal-logic", ClassFormal},
+		{"verification", "internal-logic", ClassOther},
+		{"audit_report", "auditor", ClassAudit},
+		{"research", "", ClassResearch},
+		{"internal", "https://example.com/paper.pdf", ClassResearch},
+		{"internal", "test-runner", ClassEmpirical},
+		{"test-report", "report.json#sha256=ab12", ClassEmpirical},
+		{"benchmark", "", ClassEmpirical},
+	}
+	for _, tt := range tests {
+		e := db.Evidence{Type: tt.typ, CarrierRef: sql.NullString{String: tt.ref, Valid: tt.ref != ""}}
+		if got := EvidenceClass(e); got != tt.want {
+			t.Errorf("EvidenceClass(%s, %s) = %s, want %s", tt.typ, tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestEvidenceWeighting_Aggregate(t *testing.T) {
+	scores := []float64{1, 0.5, 0}
+	weights := []float64{3, 1, 0}
+	tests := []struct {
+		rule string
+		want float64
+	}{
+		{AggregateMean, 3.5 / 4},
+		{AggregateMin, 0.5},
+		{AggregateBayes, 4.5 / 6},
+	}
+	for _, tt := range tests {
+		got, ok := EvidenceWeighting{Rule: tt.rule}.Aggregate(scores, weights)
+		if !ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: got %f (%v), want %f", tt.rule, got, ok, tt.want)
+		}
+	}
+	if _, ok := DefaultEvidenceWeighting.Aggregate([]float64{1}, []float64{0}); ok {
+		t.Error("Expected no score when all evidence weighs 0")
+	}
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or change project assurance policy",
+	Long: `Show or change the assurance policy stored in the project's fpf_state.
+Policy is per bounded context; the active context is used.
+
+Keys:
+  assurance-threshold   Minimum R_eff for the Operation gate (0.0-1.0, default 0.8)
+  formality-threshold   Minimum formality for the Operation gate (F0-F9, default F0)
+  cl-penalty            Congruence penalty profile Φ(CL):
+                          fpf-b13               FPF B.1.3 normative table (default)
+                          linear[:max]          Φ(CL) = max·(3−CL)/3, max defaults to 0.9
+                          custom:p0,p1,p2,p3    explicit penalties for CL0..CL3
+  decay                 Evidence decay after valid_until, as [type=]profile,...:
+                          step                  drop to 0.1 at valid_until (default)
+                          linear:<period>       fall to 0.1 over the period, e.g. linear:30d
+                          half-life:<period>    halve every period, down to 0.1
+                          none                  never decay
+                        e.g. step,benchmark=half-life:30d,external=linear:90d
+  weighting             How evidence aggregates into a self score, as a rule
+                        followed by key=weight entries:
+                          mean                  weighted mean (default)
+                          min                   lowest score
+                          bayes                 Bayesian update from a Beta(1,1) prior
+                        keys are type:<evidence type>, level:<L0-L2> and
+                        class:<formal|empirical|research|audit|other>;
+                        matching weights multiply, 0 leaves evidence out
+                        e.g. mean,class:empirical=2,class:other=0.5
+  strict-mode           Require role, session_id and a valid FSM transition
+                        on every mutating tool call (on|off, default off)
+
+Policy lives in the database rather than a file so the agent cannot
+change it. Every reliability report records the Φ(CL) and decay profiles
+and the evidence weighting it used.`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:          "show",
+	Short:        "Print the current assurance policy",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runConfigShow,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:          "set <key> <value>",
+	Short:        "Change an assurance policy value",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runConfigSet,
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd, configSetCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func loadProjectFSM() (*fpf.FSM, func(), error) {
+	tools, closeStore, err := openProjectTools()
+	if err != nil {
+		return nil, nil, err
+	}
+	return tools.FSM, closeStore, nil
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	fsm, closeStore, err := loadProjectFSM()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	phi := fsm.GetCLPenaltyProfile()
+	fmt.Printf("context:             %s\n", fsm.ActiveContext())
+	fmt.Printf("assurance-threshold: %.2f\n", fsm.GetAssuranceThreshold())
+	fmt.Printf("formality-threshold: %s\n", assurance.FormatFormality(fsm.GetFormalityThreshold()))
+	fmt.Printf("cl-penalty:          %s\n", phi.Spec())
+	fmt.Printf("                     %s\n", phi)
+	fmt.Printf("decay:               %s\n", fsm.GetDecayProfiles().Spec())
+	fmt.Printf("weighting:           %s\n", fsm.GetEvidenceWeighting().Spec())
+	fmt.Printf("strict-mode:         %s\n", onOff(fsm.State.StrictMode))
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+
+	fsm, closeStore, err := loadProjectFSM()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	switch key {
+	case "assurance-threshold":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil || v <= 0 || v > 1 {
+			return fmt.Errorf("assurance-threshold must be in (0,1], got %q", value)
+		}
+		fsm.State.AssuranceThreshold = v
+	case "formality-threshold":
+		f, err := assurance.ParseFormality(value)
+		if err != nil {
+			return err
+		}
+		fsm.State.FormalityThreshold = f
+	case "cl-penalty":
+		phi, err := assurance.ParseCLPenaltyProfile(value)
+		if err != nil {
+			return err
+		}
+		fsm.State.CLPenaltyProfile = phi.Spec()
+	case "decay":
+		decay, err := assurance.ParseDecayProfiles(value)
+		if err != nil {
+			return err
+		}
+		fsm.State.DecayProfiles = decay.Spec()
+	case "weighting":
+		weighting, err := assurance.ParseEvidenceWeighting(value)
+		if err != nil {
+			return err
+		}
+		fsm.State.EvidenceWeighting = weighting.Spec()
+	case "strict-mode":
+		switch value {
+		case "on", "true", "1":
+			fsm.State.StrictMode = true
+		case "off", "false", "0":
+			fsm.State.StrictMode = false
+		default:
+			return fmt.Errorf("strict-mode must be on or off, got %q", value)
+		}
+	default:
+		return fmt.Errorf("unknown config key %q (expected assurance-threshold, formality-threshold, cl-penalty, decay, weighting or strict-mode)", key)
+	}
+
+	if err := fsm.SaveState(fsm.ActiveContext()); err != nil {
+		return err
+	}
+	fmt.Printf("%s updated\n", key)
+	return nil
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var evidenceCmd = &cobra.Command{
+	Use:   "evidence",
+	Short: "Manage validation evidence",
+}
+
+var evidenceIngestCommand = toolCommand{
+	use:   "ingest <path>",
+	short: "Record evidence from a go test -json or JUnit XML report",
+	tool:  "quint_ingest_results",
+	args:  []string{"path"},
+	flags: []toolFlag{
+		{name: "format", arg: "format", def: "auto", usage: "auto, go-json or junit"},
+		{name: "hypothesis", arg: "hypothesis_id", usage: "Attribute every test in the report to this hypothesis"},
+		{name: "map", arg: "mapping", usage: "Hypothesis → test regex, e.g. 'use-redis=^TestRedis; use-lru=TestLRU'"},
+	},
+}
+
+func init() {
+	evidenceCmd.AddCommand(newToolCommand(evidenceIngestCommand))
+	rootCmd.AddCommand(evidenceCmd)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export quint records to other formats",
+}
+
+var exportADRCmd = &cobra.Command{
+	Use:   "adr",
+	Short: "Export DRRs as MADR or Nygard ADR files",
+	Long: `Write every DRR as a numbered Architecture Decision Record, e.g.
+docs/adr/0003-use-redis.md. The considered options come from the DRR's
+selected and rejected alternatives, and the status from its lifecycle,
+with links between superseded and superseding ADRs.
+
+The export is idempotent: each ADR remembers its DRR, so running it again
+rewrites the same files under the same numbers. New DRRs are numbered after
+the highest ADR already in the directory, so hand-written ADRs are kept.
+
+  quint-code export adr --format madr --out docs/adr`,
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE:         runExportADR,
+}
+
+var (
+	exportADRFormat string
+	exportADROut    string
+)
+
+func init() {
+	exportADRCmd.Flags().StringVar(&exportADRFormat, "format", fpf.ADRFormatMADR, "ADR format: madr or nygard")
+	exportADRCmd.Flags().StringVar(&exportADROut, "out", "docs/adr", "Directory to write the ADRs to")
+	exportCmd.AddCommand(exportADRCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExportADR(cmd *cobra.Command, args []string) error {
+	tools, closeStore, err := openProjectTools()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	report, err := tools.ExportADR(exportADRFormat, exportADROut)
+	if err != nil {
+		return err
+	}
+	fmt.Print(report.String())
+	return nil
+}
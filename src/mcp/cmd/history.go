@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the phase transition history of a bounded context",
+	Long: `Show every recorded phase transition of a bounded context, oldest first:
+who moved it (role and session), which tool and evidence anchor were used,
+and why. Loopbacks are marked with ↺, and each decision cycle is summarized
+as the path it took through ABDUCTION → DEDUCTION → INDUCTION → DECISION.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runHistory,
+}
+
+var (
+	historyContext string
+	historyLimit   int
+)
+
+func init() {
+	historyCmd.Flags().StringVar(&historyContext, "context", "", "Bounded context (default: active context)")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 0, "Only show the most recent N transitions")
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	tools, closeStore, err := openProjectTools()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	out, err := tools.History(historyContext, historyLimit)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Query and export the audit log",
+	Long: `Show the audit log entries matching the given filters, oldest first: which
+tool ran, in which role and session, on which holon, and whether it succeeded.
+
+--since and --until take a date (2006-01-02), an RFC 3339 time or a duration
+back from now (90m, 24h, 7d). --until is exclusive, but a date includes that
+whole day. --actor matches the exact actor or, given a role, all its sessions.
+
+Use --format jsonl or csv to hand the log to reviewers:
+
+  quint-code log --target redis-cache --format csv > redis-cache-audit.csv`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runLog,
+}
+
+var logQuery fpf.AuditLogQuery
+
+func init() {
+	f := logCmd.Flags()
+	f.StringVar(&logQuery.Tool, "tool", "", "Tool name, e.g. quint_verify")
+	f.StringVar(&logQuery.Operation, "operation", "", "Operation, e.g. verify_hypothesis")
+	f.StringVar(&logQuery.Actor, "actor", "", "Actor (Deductor@session-1), or a role to match all its sessions")
+	f.StringVar(&logQuery.Target, "target", "", "Holon the call acted on, e.g. a hypothesis ID")
+	f.StringVar(&logQuery.Result, "result", "", "SUCCESS or ERROR")
+	f.StringVar(&logQuery.Context, "context", "", "Bounded context (default: all contexts)")
+	f.StringVar(&logQuery.Since, "since", "", "Only entries at or after this time")
+	f.StringVar(&logQuery.Until, "until", "", "Only entries before this time")
+	f.IntVar(&logQuery.Limit, "limit", 0, "Only show the most recent N matches")
+	f.StringVar(&logQuery.Format, "format", fpf.AuditFormatTable, "Output format: table, jsonl or csv")
+	rootCmd.AddCommand(logCmd)
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	tools, closeStore, err := openProjectTools()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	out, err := tools.QueryAuditLog(logQuery)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
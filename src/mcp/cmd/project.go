@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/m0n0x41d/quint-code/db"
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+)
+
+// projectRoot returns QUINT_PROJECT_ROOT if set, otherwise the working directory.
+func projectRoot() (string, error) {
+	if root := os.Getenv("QUINT_PROJECT_ROOT"); root != "" {
+		return root, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return cwd, nil
+}
+
+// openProjectStore opens .quint/quint.db under root. Unlike serve, CLI
+// commands need an initialized project.
+func openProjectStore(root string) (*db.Store, error) {
+	dbPath := filepath.Join(root, ".quint", "quint.db")
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("no FPF database at %s (run 'quint-code init' first)", dbPath)
+	}
+	return db.NewStore(dbPath)
+}
+
+// openProjectTools opens the project database and loads the FSM state of the
+// active bounded context. The returned func closes the database.
+func openProjectTools() (*fpf.Tools, func(), error) {
+	root, err := projectRoot()
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := openProjectStore(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	closeStore := func() { _ = store.Close() }
+
+	contextID, err := store.GetActiveContextID(context.Background())
+	if err != nil {
+		closeStore()
+		return nil, nil, fmt.Errorf("failed to resolve active context: %w", err)
+	}
+	fsm, err := fpf.LoadState(contextID, store)
+	if err != nil {
+		closeStore()
+		return nil, nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	return fpf.NewTools(fsm, root, store), closeStore, nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/m0n0x41d/quint-code/db"
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+var rebuildDBCmd = &cobra.Command{
+	Use:   "rebuild-db",
+	Short: "Rebuild quint.db from the .quint markdown files",
+	Long: `Rebuild the database from the markdown projection in .quint/.
+
+Use this after a fresh clone, where the markdown files are committed but
+quint.db is not. Holons, evidence, relations and DRRs are restored from the
+knowledge/, evidence/ and decisions/ files of every bounded context. Files
+whose content hash no longer matches their frontmatter are reported.
+
+The rebuild only adds rows. If a holon, evidence record or relation exists
+in both the database and the files but they disagree, nothing is written and
+the command exits with code 3. Waivers, the audit log and phase history
+exist only in the database and are left untouched.`,
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE:         runRebuildDB,
+}
+
+var rebuildDryRun bool
+
+func init() {
+	rebuildDBCmd.Flags().BoolVar(&rebuildDryRun, "dry-run", false, "Report what would be restored without writing")
+	rootCmd.AddCommand(rebuildDBCmd)
+}
+
+func runRebuildDB(cmd *cobra.Command, args []string) error {
+	root, err := projectRoot()
+	if err != nil {
+		return err
+	}
+	quintDir := filepath.Join(root, ".quint")
+	if _, err := os.Stat(quintDir); err != nil {
+		return fmt.Errorf("no .quint directory at %s", quintDir)
+	}
+
+	// Unlike other commands, a missing quint.db is the expected case here.
+	store, err := db.NewStore(filepath.Join(quintDir, "quint.db"))
+	if err != nil {
+		return err
+	}
+	defer store.Close() //nolint:errcheck
+
+	contextID, err := store.GetActiveContextID(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to resolve active context: %w", err)
+	}
+	fsm, err := fpf.LoadState(contextID, store)
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+
+	report, err := fpf.NewTools(fsm, root, store).RebuildDB(rebuildDryRun)
+	if err != nil {
+		return err
+	}
+	fmt.Print(report.String())
+
+	if len(report.Conflicts) > 0 {
+		return &exitError{code: ExitBlocked, err: errors.New("rebuild refused: database and markdown files disagree")}
+	}
+	return nil
+}
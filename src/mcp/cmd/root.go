@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -21,7 +22,8 @@ First Principles Framework (FPF) for AI-assisted engineering.
 
 It provides tools for hypothesis generation, verification, validation,
 and decision-making with full audit trails.`,
-	Version: Version,
+	Version:       Version,
+	SilenceErrors: true, // Execute prints the error once
 }
 
 var versionCmd = &cobra.Command{
@@ -34,10 +36,43 @@ var versionCmd = &cobra.Command{
 	},
 }
 
+// Exit codes, stable for scripting.
+const (
+	ExitOK      = 0
+	ExitFailure = 1 // the command or tool failed
+	ExitUsage   = 2 // invalid arguments or flags
+	ExitBlocked = 3 // blocked by a precondition or strict mode
+)
+
+// exitError makes Execute exit with a specific code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// usageArgs wraps a positional argument validator so violations exit with
+// ExitUsage.
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validate(cmd, args); err != nil {
+			return &exitError{code: ExitUsage, err: err}
+		}
+		return nil
+	}
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		code := ExitFailure
+		var ee *exitError
+		if errors.As(err, &ee) {
+			code = ee.code
+		}
+		os.Exit(code)
 	}
 }
 
@@ -45,4 +80,7 @@ func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.SetVersionTemplate("quint-code {{.Version}}\n")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &exitError{code: ExitUsage, err: err}
+	})
 }
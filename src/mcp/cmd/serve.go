@@ -1,10 +1,13 @@
 package cmd
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
+	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/m0n0x41d/quint-code/db"
 	"github.com/m0n0x41d/quint-code/internal/fpf"
@@ -18,7 +21,13 @@ var serveCmd = &cobra.Command{
 	Long: `Start the Model Context Protocol (MCP) server for AI tool integration.
 
 The server communicates via stdio and provides FPF tools to AI assistants
-like Claude Code, Cursor, Gemini CLI, and Codex CLI.
+like Claude Code, Cursor, Gemini CLI, and Codex CLI. The FPF slash
+commands (q0–q5, q-*) are also served as MCP prompts, so any MCP client
+gets the workflow without installing command files.
+
+With --http, the same tools are served over the MCP Streamable HTTP
+transport at /mcp, so several agents and dashboards can share one
+project's .quint/quint.db through a single process.
 
 The project root is determined by:
   1. QUINT_PROJECT_ROOT environment variable (if set)
@@ -26,43 +35,77 @@ The project root is determined by:
 	RunE: runServe,
 }
 
+var (
+	serveHTTPAddr       string
+	serveSessionTimeout time.Duration
+)
+
 func init() {
+	serveCmd.Flags().StringVar(&serveHTTPAddr, "http", "", "Serve MCP over Streamable HTTP on this address (e.g. :7777) instead of stdio")
+	serveCmd.Flags().DurationVar(&serveSessionTimeout, "session-timeout", fpf.DefaultSessionIdleTimeout, "With --http, end sessions idle for this long")
 	rootCmd.AddCommand(serveCmd)
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
-	cwd := os.Getenv("QUINT_PROJECT_ROOT")
-	if cwd == "" {
-		var err error
-		cwd, err = os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get working directory: %w", err)
-		}
+	cwd, err := projectRoot()
+	if err != nil {
+		return err
 	}
 
 	quintDir := filepath.Join(cwd, ".quint")
 	dbPath := filepath.Join(quintDir, "quint.db")
 
-	var database *db.Store
+	// Left nil (not a nil *db.Store) when there is no database, so the
+	// tools' nil checks see it.
+	var database db.Repository
 	if _, err := os.Stat(dbPath); err == nil {
-		database, err = db.NewStore(dbPath)
+		store, err := db.NewStore(dbPath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to open database: %v\n", err)
+		} else {
+			database = store
 		}
 	}
 
-	var rawDB *sql.DB
+	contextID := db.DefaultContextID
 	if database != nil {
-		rawDB = database.GetRawDB()
+		if active, err := database.GetActiveContextID(context.Background()); err == nil {
+			contextID = active
+		}
 	}
 
-	fsm, err := fpf.LoadState("default", rawDB)
+	fsm, err := fpf.LoadState(contextID, database)
 	if err != nil {
 		return fmt.Errorf("failed to load state: %w", err)
 	}
 
 	tools := fpf.NewTools(fsm, cwd, database)
 	server := fpf.NewServer(tools)
+
+	commandsFS, err := fs.Sub(embeddedCommands, "commands")
+	if err != nil {
+		return fmt.Errorf("failed to open embedded commands: %w", err)
+	}
+	prompts, err := fpf.LoadPrompts(commandsFS)
+	if err != nil {
+		return fmt.Errorf("failed to load prompts: %w", err)
+	}
+	server.SetPrompts(prompts)
+
+	if serveHTTPAddr != "" {
+		transport := fpf.NewHTTPTransport(server)
+		transport.IdleTimeout = serveSessionTimeout
+		mux := http.NewServeMux()
+		mux.Handle("/mcp", transport)
+		httpServer := &http.Server{
+			Addr:              serveHTTPAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		fmt.Fprintf(os.Stderr, "quint-code MCP server listening on http://%s/mcp\n", serveHTTPAddr)
+		return httpServer.ListenAndServe()
+	}
+
 	server.Start()
 
 	return nil
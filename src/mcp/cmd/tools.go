@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+type flagKind int
+
+const (
+	stringFlag flagKind = iota
+	intFlag
+	listFlag
+)
+
+// toolFlag maps a command-line flag onto a tool argument.
+type toolFlag struct {
+	name  string
+	arg   string
+	kind  flagKind
+	def   string
+	usage string
+}
+
+// toolCommand describes the CLI front end of one MCP tool.
+type toolCommand struct {
+	use   string
+	short string
+	tool  string
+	args  []string // tool arguments taken positionally, in order
+	flags []toolFlag
+}
+
+// roleFlags are added to commands whose tool is subject to strict mode.
+var roleFlags = []toolFlag{
+	{name: "role", arg: "role", usage: "FPF role this call acts in (Abductor, Deductor, Inductor, Auditor, Decider)"},
+	{name: "session", arg: "session_id", usage: "Session holding the role"},
+	{name: "evidence-uri", arg: "uri", usage: "Evidence anchor for a phase transition (path relative to the project root)"},
+	{name: "evidence-type", arg: "type", usage: "Evidence anchor type"},
+	{name: "evidence-holon", arg: "holon_id", usage: "Holon the evidence anchor refers to"},
+}
+
+// toolCommands are dispatched through fpf.Server.CallTool, like tools/call.
+var toolCommands = []toolCommand{
+	{
+		use:   "status",
+		short: "Print the current FPF phase",
+		tool:  "quint_status",
+	},
+	{
+		use:   "propose <title>",
+		short: "Propose a hypothesis (L0)",
+		tool:  "quint_propose",
+		args:  []string{"title"},
+		flags: []toolFlag{
+			{name: "content", arg: "content", usage: "Hypothesis description (required)"},
+			{name: "kind", arg: "kind", def: "system", usage: "system or episteme"},
+			{name: "scope", arg: "scope", def: "global", usage: "Scope of applicability"},
+			{name: "rationale", arg: "rationale", def: "{}", usage: "JSON: {anomaly, approach, alternatives_rejected}"},
+			{name: "decision-context", arg: "decision_context", usage: "Parent decision holon ID"},
+			{name: "depends-on", arg: "depends_on", kind: listFlag, usage: "Holon IDs this hypothesis depends on"},
+			{name: "dependency-cl", arg: "dependency_cl", kind: intFlag, def: "3", usage: "Congruence level of the dependencies (1-3)"},
+			{name: "formality", arg: "formality", kind: intFlag, def: "0", usage: "Formality level F0-F9"},
+			{name: "claim-scope", arg: "claim_scope", usage: "Claim scope G, e.g. 'env=prod; db=postgres'"},
+			{name: "context", arg: "context", usage: "Bounded context (default: active context)"},
+		},
+	},
+	{
+		use:   "verify <hypothesis-id>",
+		short: "Record a deductive verification (L0 → L1)",
+		tool:  "quint_verify",
+		args:  []string{"hypothesis_id"},
+		flags: []toolFlag{
+			{name: "checks", arg: "checks_json", def: "{}", usage: "JSON describing the logical checks"},
+			{name: "verdict", arg: "verdict", usage: "PASS, FAIL or REFINE (required)"},
+		},
+	},
+	{
+		use:   "test <hypothesis-id>",
+		short: "Record an empirical test (L1 → L2)",
+		tool:  "quint_test",
+		args:  []string{"hypothesis_id"},
+		flags: []toolFlag{
+			{name: "type", arg: "test_type", def: "internal", usage: "internal or research"},
+			{name: "result", arg: "result", usage: "Test result summary (required)"},
+			{name: "verdict", arg: "verdict", usage: "PASS, FAIL or REFINE (required)"},
+		},
+	},
+	{
+		use:   "audit <hypothesis-id>",
+		short: "Record an audit of a hypothesis",
+		tool:  "quint_audit",
+		args:  []string{"hypothesis_id"},
+		flags: []toolFlag{
+			{name: "risks", arg: "risks", usage: "Identified risks (required)"},
+		},
+	},
+	{
+		use:   "decide <title>",
+		short: "Finalize a decision (DRR)",
+		tool:  "quint_decide",
+		args:  []string{"title"},
+		flags: []toolFlag{
+			{name: "winner", arg: "winner_id", usage: "Selected hypothesis ID (required)"},
+			{name: "rejected", arg: "rejected_ids", kind: listFlag, usage: "Rejected hypothesis IDs"},
+			{name: "context", arg: "context", usage: "Problem context (required)"},
+			{name: "decision", arg: "decision", usage: "Decision statement (required)"},
+			{name: "rationale", arg: "rationale", usage: "Why the winner was selected (required)"},
+			{name: "consequences", arg: "consequences", usage: "Consequences of the decision (required)"},
+			{name: "characteristics", arg: "characteristics", usage: "Characteristic space (C.16)"},
+			{name: "status", arg: "status", def: "accepted", usage: "proposed or accepted"},
+			{name: "supersedes", arg: "supersedes", kind: listFlag, usage: "IDs of the DRRs this decision replaces"},
+		},
+	},
+	{
+		use:   "drr-status <drr-id> <status>",
+		short: "Accept or deprecate a DRR",
+		tool:  "quint_drr_status",
+		args:  []string{"drr_id", "status"},
+		flags: []toolFlag{
+			{name: "reason", arg: "reason", usage: "Why the status changed"},
+		},
+	},
+	{
+		use:   "characterize <hypothesis-id> <name> <value>",
+		short: "Record a measurable characteristic of a hypothesis",
+		tool:  "quint_characterize",
+		args:  []string{"hypothesis_id", "name", "value"},
+		flags: []toolFlag{
+			{name: "scale", arg: "scale", def: "ratio", usage: "Measurement scale: ordinal, interval or ratio"},
+			{name: "unit", arg: "unit", usage: "Unit, e.g. ms"},
+			{name: "direction", arg: "direction", def: "higher", usage: "Whether higher or lower values are better"},
+		},
+	},
+	{
+		use:   "compare",
+		short: "Compare options on their characteristics",
+		tool:  "quint_compare",
+		flags: []toolFlag{
+			{name: "decision-context", arg: "decision_context", usage: "Decision context whose members are compared"},
+			{name: "hypotheses", arg: "hypothesis_ids", kind: listFlag, usage: "Hypotheses to compare, in addition to the context's members"},
+			{name: "weight", arg: "weights", kind: listFlag, usage: "Weight as name=weight, e.g. latency=2 (repeatable)"},
+		},
+	},
+	{
+		use:   "simulate <holon-id>",
+		short: "Show how R_eff would change under hypothetical changes, without saving",
+		tool:  "quint_simulate",
+		args:  []string{"holon_id"},
+		flags: []toolFlag{
+			{name: "add-evidence", arg: "add_evidence", kind: listFlag, usage: "Hypothetical evidence as holon:verdict[:valid_until]"},
+			{name: "set-cl", arg: "set_cl", kind: listFlag, usage: "Changed CL as holon:dependency:cl"},
+			{name: "expire", arg: "expire", kind: listFlag, usage: "Evidence to expire as evidence-id[:date]"},
+			{name: "remove-dependency", arg: "remove_dependency", kind: listFlag, usage: "Dependency to drop as holon:dependency"},
+			{name: "at", arg: "at", usage: "Evaluate both sides as of this date, no earlier than any expiry (default: the latest expiry, else now)"},
+		},
+	},
+	{
+		use:   "calculate-r <holon-id>",
+		short: "Print the assurance report of a holon",
+		tool:  "quint_calculate_r",
+		args:  []string{"holon_id"},
+	},
+	{
+		use:   "audit-tree <holon-id>",
+		short: "Print the assurance tree of a holon",
+		tool:  "quint_audit_tree",
+		args:  []string{"holon_id"},
+	},
+	{
+		use:   "decay",
+		short: "Check evidence freshness, deprecate or waive",
+		tool:  "quint_check_decay",
+		flags: []toolFlag{
+			{name: "deprecate", arg: "deprecate", usage: "Holon ID to downgrade one level"},
+			{name: "waive", arg: "waive_id", usage: "Evidence ID to waive"},
+			{name: "until", arg: "waive_until", usage: "ISO date until which the waiver is valid"},
+			{name: "rationale", arg: "waive_rationale", usage: "Reason for accepting stale evidence"},
+		},
+	},
+	{
+		use:   "actualize",
+		short: "Reconcile the knowledge base with repository changes",
+		tool:  "quint_actualize",
+	},
+}
+
+// toolSubcommands are attached under the command of the named tool.
+var toolSubcommands = map[string][]*cobra.Command{}
+
+func init() {
+	for _, tc := range toolCommands {
+		c := newToolCommand(tc)
+		c.AddCommand(toolSubcommands[tc.tool]...)
+		rootCmd.AddCommand(c)
+	}
+}
+
+func newToolCommand(tc toolCommand) *cobra.Command {
+	var asJSON bool
+	strs := map[string]*string{}
+	ints := map[string]*int{}
+	lists := map[string]*[]string{}
+
+	flags := tc.flags
+	if fpf.IsRoleAwareTool(tc.tool) {
+		flags = append(append([]toolFlag{}, flags...), roleFlags...)
+	}
+
+	cmd := &cobra.Command{
+		Use:          tc.use,
+		Short:        tc.short,
+		Long:         fmt.Sprintf("%s.\n\nRuns the %s MCP tool. Exit codes: %d ok, %d failed, %d usage error, %d blocked by a precondition or strict mode.", tc.short, tc.tool, ExitOK, ExitFailure, ExitUsage, ExitBlocked),
+		Args:         usageArgs(cobra.ExactArgs(len(tc.args))),
+		SilenceUsage: true,
+	}
+
+	for _, f := range flags {
+		switch f.kind {
+		case intFlag:
+			def, _ := strconv.Atoi(f.def)
+			ints[f.name] = cmd.Flags().Int(f.name, def, f.usage)
+		case listFlag:
+			lists[f.name] = cmd.Flags().StringSlice(f.name, nil, f.usage)
+		default:
+			strs[f.name] = cmd.Flags().String(f.name, f.def, f.usage)
+		}
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the result as JSON")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		arguments := map[string]interface{}{}
+		for i, name := range tc.args {
+			arguments[name] = args[i]
+		}
+
+		evidence := map[string]interface{}{}
+		for _, f := range flags {
+			if !cmd.Flags().Changed(f.name) && f.def == "" {
+				continue
+			}
+			var v interface{}
+			switch f.kind {
+			case intFlag:
+				v = float64(*ints[f.name])
+			case listFlag:
+				items := make([]interface{}, 0, len(*lists[f.name]))
+				for _, item := range *lists[f.name] {
+					items = append(items, item)
+				}
+				v = items
+			default:
+				v = *strs[f.name]
+			}
+			if strings.HasPrefix(f.name, "evidence-") {
+				evidence[f.arg] = v
+			} else {
+				arguments[f.arg] = v
+			}
+		}
+		if len(evidence) > 0 {
+			arguments["evidence"] = evidence
+		}
+
+		return runTool(tc.tool, arguments, asJSON)
+	}
+	return cmd
+}
+
+// toolResultJSON is the --json output of a tool command.
+type toolResultJSON struct {
+	Tool    string      `json:"tool"`
+	OK      bool        `json:"ok"`
+	Output  string      `json:"output,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+func runTool(tool string, arguments map[string]interface{}, asJSON bool) error {
+	tools, closeStore, err := openProjectTools()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	result := fpf.NewServer(tools).CallTool(tool, arguments)
+	var text string
+	if len(result.Content) > 0 {
+		text = result.Content[0].Text
+	}
+
+	if asJSON {
+		out := toolResultJSON{Tool: tool, OK: !result.IsError, Details: result.StructuredContent}
+		if result.IsError {
+			out.Error = text
+		} else {
+			out.Output = text
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else if !result.IsError {
+		fmt.Println(strings.TrimRight(text, "\n"))
+	}
+
+	if !result.IsError {
+		return nil
+	}
+	code := ExitFailure
+	switch result.StructuredContent.(type) {
+	case *fpf.PreconditionError, *fpf.TransitionError:
+		code = ExitBlocked
+	}
+	return &exitError{code: code, err: errors.New(text)}
+}
@@ -0,0 +1,187 @@
+package db
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditTimeLayout is how entry timestamps are written into the chain hash.
+const AuditTimeLayout = time.RFC3339Nano
+
+// AuditEntryHash returns the hex SHA-256 over the entry's chain position, the
+// previous entry's hash and every recorded field except the signature.
+func AuditEntryHash(e AuditLog) string {
+	h := sha256.New()
+	for _, field := range []string{
+		strconv.FormatInt(e.Seq.Int64, 10),
+		e.PrevHash.String,
+		e.ID,
+		e.Timestamp.Time.UTC().Format(AuditTimeLayout),
+		e.ToolName,
+		e.Operation,
+		e.Actor,
+		e.TargetID.String,
+		e.InputHash.String,
+		e.Result,
+		e.Details.String,
+		e.ContextID,
+	} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// chainAuditEntry links e after prev (the zero AuditLog for the first entry),
+// stamps it, hashes it and signs the hash if key is set.
+func chainAuditEntry(e *AuditLog, prev AuditLog, key ed25519.PrivateKey) {
+	// Microseconds survive the round trip through SQLite unchanged.
+	e.Timestamp = sql.NullTime{Time: time.Now().UTC().Truncate(time.Microsecond), Valid: true}
+	e.Seq = sql.NullInt64{Int64: prev.Seq.Int64 + 1, Valid: true}
+	e.PrevHash = prev.EntryHash
+	e.EntryHash = toNullString(AuditEntryHash(*e))
+	if key != nil {
+		e.Signature = toNullString(hex.EncodeToString(ed25519.Sign(key, []byte(e.EntryHash.String))))
+	}
+}
+
+// AuditChainReport is the result of walking the audit chain.
+type AuditChainReport struct {
+	Checked  int    // chained entries checked
+	Legacy   int    // entries written before chaining, which carry no hash
+	Signed   int    // chained entries with a signature
+	HeadSeq  int64  // seq of the last entry checked
+	HeadHash string // entry_hash of the last entry checked
+	Break    *AuditChainBreak
+}
+
+// AuditChainBreak is the first entry at which the chain does not hold.
+type AuditChainBreak struct {
+	Seq    int64 // 0 for an entry without a chain position
+	ID     string
+	Reason string
+}
+
+// VerifyAuditChain walks entries in ListAuditLog order and stops at the first
+// break. Signatures are checked when pub is set; once a signed entry has been
+// seen, every later entry must be signed.
+//
+// Truncating the newest entries leaves a valid shorter chain. Recording
+// HeadSeq and HeadHash elsewhere (a commit message, a CI log) closes that gap.
+func VerifyAuditChain(entries []AuditLog, pub ed25519.PublicKey) AuditChainReport {
+	var r AuditChainReport
+	var prev AuditLog
+	var chainStart time.Time
+	for _, e := range entries {
+		if e.Seq.Valid && e.Seq.Int64 == 1 {
+			chainStart = e.Timestamp.Time
+		}
+	}
+
+	signedSeen := false
+	for _, e := range entries {
+		if !e.Seq.Valid {
+			if !chainStart.IsZero() && e.Timestamp.Time.After(chainStart) {
+				r.Break = &AuditChainBreak{ID: e.ID, Reason: "entry without a chain position was written after chaining began"}
+				return r
+			}
+			r.Legacy++
+			continue
+		}
+
+		brk := func(reason string) AuditChainReport {
+			r.Break = &AuditChainBreak{Seq: e.Seq.Int64, ID: e.ID, Reason: reason}
+			return r
+		}
+		switch {
+		case e.Seq.Int64 != prev.Seq.Int64+1:
+			return brk(fmt.Sprintf("expected entry #%d, found #%d: entries were deleted or reordered", prev.Seq.Int64+1, e.Seq.Int64))
+		case e.PrevHash.String != prev.EntryHash.String:
+			return brk(fmt.Sprintf("prev_hash does not match the hash of entry #%d", prev.Seq.Int64))
+		case AuditEntryHash(e) != e.EntryHash.String:
+			return brk("entry was altered: its contents no longer match entry_hash")
+		}
+
+		if e.Signature.Valid {
+			r.Signed++
+			signedSeen = true
+			if pub != nil {
+				sig, err := hex.DecodeString(e.Signature.String)
+				if err != nil || !ed25519.Verify(pub, []byte(e.EntryHash.String), sig) {
+					return brk("signature does not verify against the audit public key")
+				}
+			}
+		} else if signedSeen {
+			return brk("entry is unsigned but earlier entries are signed")
+		}
+
+		r.Checked++
+		r.HeadSeq, r.HeadHash = e.Seq.Int64, e.EntryHash.String
+		prev = e
+	}
+	return r
+}
+
+// AuditLogFilter selects audit entries. Empty fields match everything. Actor
+// matches either the exact actor or a role recorded as role@session. Since is
+// inclusive, Until exclusive. A Limit of zero or less returns every match.
+type AuditLogFilter struct {
+	ToolName  string
+	Operation string
+	Actor     string
+	TargetID  string
+	Result    string
+	ContextID string
+	Since     time.Time
+	Until     time.Time
+	Limit     int64
+}
+
+// auditFilterTimeLayout renders filter bounds so they compare correctly, as
+// text, with both timestamp forms SQLite holds: "2006-01-02 15:04:05" and
+// Go's time.String().
+const auditFilterTimeLayout = "2006-01-02 15:04:05.999999999"
+
+func (f AuditLogFilter) params() FilterAuditLogParams {
+	bound := func(t time.Time) sql.NullString {
+		if t.IsZero() {
+			return sql.NullString{}
+		}
+		return toNullString(t.UTC().Format(auditFilterTimeLayout))
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	return FilterAuditLogParams{
+		ToolName:  toNullString(f.ToolName),
+		Operation: toNullString(f.Operation),
+		Actor:     toNullString(f.Actor),
+		TargetID:  toNullString(f.TargetID),
+		Result:    toNullString(f.Result),
+		ContextID: toNullString(f.ContextID),
+		Since:     bound(f.Since),
+		Until:     bound(f.Until),
+		MaxRows:   limit,
+	}
+}
+
+// Match reports whether e passes the filter.
+func (f AuditLogFilter) Match(e AuditLog) bool {
+	eq := func(want, got string) bool { return want == "" || want == got }
+	ts := e.Timestamp.Time
+	return eq(f.ToolName, e.ToolName) &&
+		eq(f.Operation, e.Operation) &&
+		(eq(f.Actor, e.Actor) || strings.HasPrefix(e.Actor, f.Actor+"@")) &&
+		(f.TargetID == "" || e.TargetID.String == f.TargetID) &&
+		eq(f.Result, e.Result) &&
+		eq(f.ContextID, e.ContextID) &&
+		(f.Since.IsZero() || !ts.Before(f.Since)) &&
+		(f.Until.IsZero() || ts.Before(f.Until))
+}
@@ -0,0 +1,1084 @@
+package db
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// MemoryStore is a Repository kept entirely in process. It mirrors the
+// ordering and not-found behaviour of the SQLite queries so the calculator,
+// FSM and tools can be unit-tested without a database file. Nothing is
+// persisted; Close is a no-op.
+type MemoryStore struct {
+	mu sync.Mutex
+
+	// tick orders rows written within the same clock reading, standing in
+	// for SQLite's rowid where the queries sort by timestamp.
+	tick int64
+
+	holons      []Holon
+	holonSeq    map[string]int64 // holon ID → tick of the last update
+	evidence    []Evidence
+	suspects    map[string]SuspectEvidence
+	relations   []Relation
+	waivers     []Waiver
+	auditLog    []AuditLog
+	workRecords []WorkRecord
+	states      map[string]FpfState
+	contexts    []Context
+	transitions []PhaseTransition
+	snapshots   []DrrSnapshot
+	chars       []Characteristic
+
+	auditKey ed25519.PrivateKey
+}
+
+// NewMemoryStore returns an empty in-memory Repository.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		holonSeq: make(map[string]int64),
+		suspects: make(map[string]SuspectEvidence),
+		states:   make(map[string]FpfState),
+	}
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// WithTx snapshots the store, runs fn against it and restores the snapshot if
+// fn fails. Writes made by other goroutines while fn runs are not isolated
+// and are lost on rollback.
+func (m *MemoryStore) WithTx(ctx context.Context, fn func(Repository) error) error {
+	m.mu.Lock()
+	saved := m.snapshot()
+	m.mu.Unlock()
+
+	if err := fn(m); err != nil {
+		m.mu.Lock()
+		m.restore(saved)
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// snapshot copies the store's contents; the caller holds m.mu.
+func (m *MemoryStore) snapshot() *MemoryStore {
+	return &MemoryStore{
+		tick:        m.tick,
+		holons:      append([]Holon(nil), m.holons...),
+		holonSeq:    copyMap(m.holonSeq),
+		evidence:    append([]Evidence(nil), m.evidence...),
+		suspects:    copyMap(m.suspects),
+		relations:   append([]Relation(nil), m.relations...),
+		waivers:     append([]Waiver(nil), m.waivers...),
+		auditLog:    append([]AuditLog(nil), m.auditLog...),
+		workRecords: append([]WorkRecord(nil), m.workRecords...),
+		states:      copyMap(m.states),
+		contexts:    append([]Context(nil), m.contexts...),
+		transitions: append([]PhaseTransition(nil), m.transitions...),
+		snapshots:   append([]DrrSnapshot(nil), m.snapshots...),
+		chars:       append([]Characteristic(nil), m.chars...),
+	}
+}
+
+// restore replaces the store's contents with a snapshot; the caller holds m.mu.
+func (m *MemoryStore) restore(s *MemoryStore) {
+	m.tick = s.tick
+	m.holons, m.holonSeq = s.holons, s.holonSeq
+	m.evidence, m.suspects = s.evidence, s.suspects
+	m.relations = s.relations
+	m.waivers = s.waivers
+	m.auditLog, m.workRecords = s.auditLog, s.workRecords
+	m.states, m.contexts, m.transitions = s.states, s.contexts, s.transitions
+	m.snapshots, m.chars = s.snapshots, s.chars
+}
+
+func copyMap[K comparable, V any](src map[K]V) map[K]V {
+	dst := make(map[K]V, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+func (m *MemoryStore) nextTick() int64 {
+	m.tick++
+	return m.tick
+}
+
+func (m *MemoryStore) findHolon(id string) int {
+	for i := range m.holons {
+		if m.holons[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *MemoryStore) CreateHolon(ctx context.Context, id, typ, kind, layer, title, content, contextID, scope, parentID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.findHolon(id) >= 0 {
+		return fmt.Errorf("holon %s already exists", id)
+	}
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+	m.holons = append(m.holons, Holon{
+		ID:           id,
+		Type:         typ,
+		Kind:         toNullString(kind),
+		Layer:        layer,
+		Title:        title,
+		Content:      content,
+		ContextID:    contextID,
+		Scope:        toNullString(scope),
+		ParentID:     toNullString(parentID),
+		CachedRScore: sql.NullFloat64{Valid: true},
+		Formality:    sql.NullInt64{Valid: true},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	m.holonSeq[id] = m.nextTick()
+	return nil
+}
+
+func (m *MemoryStore) GetHolon(ctx context.Context, id string) (Holon, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i := m.findHolon(id); i >= 0 {
+		return m.holons[i], nil
+	}
+	return Holon{}, sql.ErrNoRows
+}
+
+func (m *MemoryStore) GetHolonTitle(ctx context.Context, id string) (string, error) {
+	h, err := m.GetHolon(ctx, id)
+	return h.Title, err
+}
+
+func (m *MemoryStore) ListAllHolonIDs(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ids []string
+	for _, h := range m.holons {
+		ids = append(ids, h.ID)
+	}
+	return ids, nil
+}
+
+func (m *MemoryStore) ListHolons(ctx context.Context) ([]Holon, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	items := append([]Holon(nil), m.holons...)
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Layer != items[j].Layer {
+			return items[i].Layer < items[j].Layer
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, nil
+}
+
+// updateHolon applies fn to a holon and marks it as the latest change. Like
+// an UPDATE, a missing ID is not an error.
+func (m *MemoryStore) updateHolon(id string, fn func(*Holon)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i := m.findHolon(id); i >= 0 {
+		fn(&m.holons[i])
+		m.holons[i].UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		m.holonSeq[id] = m.nextTick()
+	}
+}
+
+func (m *MemoryStore) UpdateHolonLayer(ctx context.Context, id, layer string) error {
+	m.updateHolon(id, func(h *Holon) { h.Layer = layer })
+	return nil
+}
+
+func (m *MemoryStore) UpdateHolonStatus(ctx context.Context, id, status string) error {
+	m.updateHolon(id, func(h *Holon) { h.Status = toNullString(status) })
+	return nil
+}
+
+func (m *MemoryStore) UpdateHolonClaim(ctx context.Context, id string, formality int, claimScope string) error {
+	m.updateHolon(id, func(h *Holon) {
+		h.Formality = sql.NullInt64{Int64: int64(formality), Valid: true}
+		h.ClaimScope = toNullString(claimScope)
+	})
+	return nil
+}
+
+func (m *MemoryStore) InsertDRRSnapshot(ctx context.Context, drrID, holonID, role string, rEff float64, auditTree, evidence string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range m.snapshots {
+		if s.DrrID == drrID && s.HolonID == holonID {
+			return fmt.Errorf("snapshot of %s in %s already exists", holonID, drrID)
+		}
+	}
+	m.snapshots = append(m.snapshots, DrrSnapshot{
+		DrrID:     drrID,
+		HolonID:   holonID,
+		Role:      role,
+		REff:      rEff,
+		AuditTree: toNullString(auditTree),
+		Evidence:  toNullString(evidence),
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	return nil
+}
+
+func (m *MemoryStore) GetDRRSnapshots(ctx context.Context, drrID string) ([]DrrSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []DrrSnapshot
+	for _, s := range m.snapshots {
+		if s.DrrID == drrID {
+			items = append(items, s)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if (items[i].Role == "winner") != (items[j].Role == "winner") {
+			return items[i].Role == "winner"
+		}
+		return items[i].HolonID < items[j].HolonID
+	})
+	return items, nil
+}
+
+func (m *MemoryStore) GetDRRSnapshotsByHolon(ctx context.Context, holonID string) ([]DrrSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []DrrSnapshot
+	for _, s := range m.snapshots {
+		if s.HolonID == holonID {
+			items = append(items, s)
+		}
+	}
+	return items, nil
+}
+
+func (m *MemoryStore) AddCharacteristic(ctx context.Context, holonID, name, scale, value, unit, direction string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := Characteristic{
+		ID:        characteristicID(holonID, name),
+		HolonID:   holonID,
+		Name:      name,
+		Scale:     scale,
+		Value:     value,
+		Unit:      toNullString(unit),
+		Direction: toNullString(direction),
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	for i := range m.chars {
+		if m.chars[i].ID == c.ID {
+			m.chars[i] = c
+			return nil
+		}
+	}
+	m.chars = append(m.chars, c)
+	return nil
+}
+
+func (m *MemoryStore) GetCharacteristics(ctx context.Context, holonID string) ([]Characteristic, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []Characteristic
+	for _, c := range m.chars {
+		if c.HolonID == holonID {
+			items = append(items, c)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items, nil
+}
+
+func (m *MemoryStore) CacheHolonRScore(ctx context.Context, id string, score float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i := m.findHolon(id); i >= 0 {
+		m.holons[i].CachedRScore = sql.NullFloat64{Float64: score, Valid: true}
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetHolonsByParent(ctx context.Context, parentID string) ([]Holon, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []Holon
+	for i := len(m.holons) - 1; i >= 0; i-- {
+		if parentID != "" && m.holons[i].ParentID.String == parentID {
+			items = append(items, m.holons[i])
+		}
+	}
+	return items, nil
+}
+
+func (m *MemoryStore) GetHolonLineage(ctx context.Context, id string) ([]GetHolonLineageRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []GetHolonLineageRow
+	seen := make(map[string]bool)
+	for depth := int64(0); !seen[id]; depth++ {
+		i := m.findHolon(id)
+		if i < 0 {
+			break
+		}
+		seen[id] = true
+		h := m.holons[i]
+		items = append([]GetHolonLineageRow{{
+			ID:           h.ID,
+			Type:         h.Type,
+			Kind:         h.Kind,
+			Layer:        h.Layer,
+			Title:        h.Title,
+			Content:      h.Content,
+			ContextID:    h.ContextID,
+			Scope:        h.Scope,
+			ParentID:     h.ParentID,
+			CachedRScore: h.CachedRScore,
+			CreatedAt:    h.CreatedAt,
+			UpdatedAt:    h.UpdatedAt,
+			Depth:        depth,
+		}}, items...)
+		if !h.ParentID.Valid {
+			break
+		}
+		id = h.ParentID.String
+	}
+	return items, nil
+}
+
+func (m *MemoryStore) CountHolonsByLayer(ctx context.Context, contextID string) ([]CountHolonsByLayerRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make(map[string]int64)
+	for _, h := range m.holons {
+		if h.ContextID == contextID {
+			counts[h.Layer]++
+		}
+	}
+	var items []CountHolonsByLayerRow
+	for layer, n := range counts {
+		items = append(items, CountHolonsByLayerRow{Layer: layer, Count: n})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Layer < items[j].Layer })
+	return items, nil
+}
+
+func (m *MemoryStore) GetLatestHolonByContext(ctx context.Context, contextID string) (Holon, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	latest, found := Holon{}, false
+	for _, h := range m.holons {
+		if h.ContextID == contextID && (!found || m.holonSeq[h.ID] > m.holonSeq[latest.ID]) {
+			latest, found = h, true
+		}
+	}
+	if !found {
+		return Holon{}, sql.ErrNoRows
+	}
+	return latest, nil
+}
+
+func (m *MemoryStore) AddEvidence(ctx context.Context, id, holonID, typ, content, verdict, assuranceLevel, carrierRef, validUntil string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range m.evidence {
+		if e.ID == id {
+			return fmt.Errorf("evidence %s already exists", id)
+		}
+	}
+	m.evidence = append(m.evidence, Evidence{
+		ID:             id,
+		HolonID:        holonID,
+		Type:           typ,
+		Content:        content,
+		Verdict:        verdict,
+		AssuranceLevel: toNullString(assuranceLevel),
+		CarrierRef:     toNullString(carrierRef),
+		ValidUntil:     parseValidUntil(validUntil),
+		CreatedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	return nil
+}
+
+// filterEvidence returns matching evidence newest first, like the
+// created_at DESC queries.
+func (m *MemoryStore) filterEvidence(keep func(Evidence) bool) []Evidence {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []Evidence
+	for i := len(m.evidence) - 1; i >= 0; i-- {
+		if keep(m.evidence[i]) {
+			items = append(items, m.evidence[i])
+		}
+	}
+	return items
+}
+
+func (m *MemoryStore) GetEvidence(ctx context.Context, holonID string) ([]Evidence, error) {
+	return m.filterEvidence(func(e Evidence) bool { return e.HolonID == holonID }), nil
+}
+
+func (m *MemoryStore) GetEvidenceByID(ctx context.Context, id string) (Evidence, error) {
+	items := m.filterEvidence(func(e Evidence) bool { return e.ID == id })
+	if len(items) == 0 {
+		return Evidence{}, sql.ErrNoRows
+	}
+	return items[0], nil
+}
+
+func (m *MemoryStore) GetEvidenceWithCarrier(ctx context.Context) ([]Evidence, error) {
+	return m.filterEvidence(func(e Evidence) bool { return e.CarrierRef.String != "" }), nil
+}
+
+func (m *MemoryStore) ListAllEvidence(ctx context.Context) ([]Evidence, error) {
+	return m.filterEvidence(func(Evidence) bool { return true }), nil
+}
+
+func (m *MemoryStore) MarkEvidenceSuspect(ctx context.Context, evidenceID, holonID, changedPaths, fromCommit, toCommit string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.suspects[evidenceID]; ok {
+		s.ChangedPaths = changedPaths
+		s.ToCommit = toNullString(toCommit)
+		m.suspects[evidenceID] = s
+		return nil
+	}
+	m.suspects[evidenceID] = SuspectEvidence{
+		EvidenceID:   evidenceID,
+		HolonID:      holonID,
+		ChangedPaths: changedPaths,
+		FromCommit:   toNullString(fromCommit),
+		ToCommit:     toNullString(toCommit),
+		DetectedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	return nil
+}
+
+func (m *MemoryStore) filterSuspects(keep func(SuspectEvidence) bool) []SuspectEvidence {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []SuspectEvidence
+	for _, s := range m.suspects {
+		if keep(s) {
+			items = append(items, s)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].HolonID != items[j].HolonID {
+			return items[i].HolonID < items[j].HolonID
+		}
+		return items[i].EvidenceID < items[j].EvidenceID
+	})
+	return items
+}
+
+func (m *MemoryStore) GetSuspectEvidence(ctx context.Context) ([]SuspectEvidence, error) {
+	return m.filterSuspects(func(SuspectEvidence) bool { return true }), nil
+}
+
+func (m *MemoryStore) GetSuspectEvidenceByHolon(ctx context.Context, holonID string) ([]SuspectEvidence, error) {
+	return m.filterSuspects(func(s SuspectEvidence) bool { return s.HolonID == holonID }), nil
+}
+
+func (m *MemoryStore) ClearSuspectEvidence(ctx context.Context, holonID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, s := range m.suspects {
+		if s.HolonID == holonID {
+			delete(m.suspects, id)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) UnmarkEvidenceSuspect(ctx context.Context, evidenceID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.suspects, evidenceID)
+	return nil
+}
+
+func (m *MemoryStore) findRelation(source, target, relType string) int {
+	for i, r := range m.relations {
+		if r.SourceID == source && r.TargetID == target && r.RelationType == relType {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *MemoryStore) Link(ctx context.Context, source, target, relType string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.findRelation(source, target, relType) >= 0 {
+		return fmt.Errorf("relation %s %s %s already exists", source, relType, target)
+	}
+	m.relations = append(m.relations, Relation{
+		SourceID:        source,
+		TargetID:        target,
+		RelationType:    relType,
+		CongruenceLevel: sql.NullInt64{Int64: 3, Valid: true},
+		CreatedAt:       sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	return nil
+}
+
+func (m *MemoryStore) CreateRelation(ctx context.Context, sourceID, relationType, targetID string, cl int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	level := sql.NullInt64{Int64: int64(cl), Valid: true}
+	if i := m.findRelation(sourceID, targetID, relationType); i >= 0 {
+		m.relations[i].CongruenceLevel = level
+		return nil
+	}
+	m.relations = append(m.relations, Relation{
+		SourceID:        sourceID,
+		TargetID:        targetID,
+		RelationType:    relationType,
+		CongruenceLevel: level,
+		CreatedAt:       sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	return nil
+}
+
+func (m *MemoryStore) filterRelations(keep func(Relation) bool) []Relation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []Relation
+	for _, r := range m.relations {
+		if keep(r) {
+			items = append(items, r)
+		}
+	}
+	return items
+}
+
+func (m *MemoryStore) GetRelationsByTarget(ctx context.Context, targetID, relationType string) ([]Relation, error) {
+	return m.filterRelations(func(r Relation) bool {
+		return r.TargetID == targetID && r.RelationType == relationType
+	}), nil
+}
+
+func (m *MemoryStore) GetRelationsBySource(ctx context.Context, sourceID, relationType string) ([]Relation, error) {
+	return m.filterRelations(func(r Relation) bool {
+		return r.SourceID == sourceID && r.RelationType == relationType
+	}), nil
+}
+
+func (m *MemoryStore) GetComponentsOf(ctx context.Context, targetID string) ([]GetComponentsOfRow, error) {
+	var items []GetComponentsOfRow
+	for _, r := range m.filterRelations(func(r Relation) bool {
+		return r.TargetID == targetID && r.RelationType == "componentOf"
+	}) {
+		items = append(items, GetComponentsOfRow{SourceID: r.SourceID, CongruenceLevel: r.CongruenceLevel})
+	}
+	return items, nil
+}
+
+func (m *MemoryStore) GetCollectionMembers(ctx context.Context, targetID string) ([]GetCollectionMembersRow, error) {
+	var items []GetCollectionMembersRow
+	for _, r := range m.filterRelations(func(r Relation) bool {
+		return r.TargetID == targetID && r.RelationType == "memberOf"
+	}) {
+		items = append(items, GetCollectionMembersRow{SourceID: r.SourceID, CongruenceLevel: r.CongruenceLevel})
+	}
+	return items, nil
+}
+
+func (m *MemoryStore) GetDependencies(ctx context.Context, sourceID string) ([]GetDependenciesRow, error) {
+	var items []GetDependenciesRow
+	for _, r := range m.filterRelations(func(r Relation) bool {
+		return r.SourceID == sourceID && (r.RelationType == "componentOf" || r.RelationType == "constituentOf")
+	}) {
+		items = append(items, GetDependenciesRow{TargetID: r.TargetID, RelationType: r.RelationType, CongruenceLevel: r.CongruenceLevel})
+	}
+	return items, nil
+}
+
+func (m *MemoryStore) GetImpactedHolons(ctx context.Context, id string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, r := range m.filterRelations(func(Relation) bool { return true }) {
+		switch {
+		case r.TargetID == id && (r.RelationType == "dependsOn" || r.RelationType == "selects"):
+			seen[r.SourceID] = true
+		case r.SourceID == id && (r.RelationType == "componentOf" || r.RelationType == "constituentOf" || r.RelationType == "memberOf"):
+			seen[r.TargetID] = true
+		}
+	}
+	var ids []string
+	for holonID := range seen {
+		ids = append(ids, holonID)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (m *MemoryStore) CreateWaiver(ctx context.Context, id, evidenceID, waivedBy string, waivedUntil time.Time, rationale string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, w := range m.waivers {
+		if w.ID == id {
+			return fmt.Errorf("waiver %s already exists", id)
+		}
+	}
+	m.waivers = append(m.waivers, Waiver{
+		ID:          id,
+		EvidenceID:  evidenceID,
+		WaivedBy:    waivedBy,
+		WaivedUntil: waivedUntil,
+		Rationale:   rationale,
+		CreatedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	return nil
+}
+
+// activeWaivers returns unexpired waivers, soonest expiry first.
+func (m *MemoryStore) activeWaivers(evidenceID string) []Waiver {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var items []Waiver
+	for _, w := range m.waivers {
+		if w.WaivedUntil.After(now) && (evidenceID == "" || w.EvidenceID == evidenceID) {
+			items = append(items, w)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].WaivedUntil.Before(items[j].WaivedUntil) })
+	return items
+}
+
+func (m *MemoryStore) GetActiveWaiverForEvidence(ctx context.Context, evidenceID string) (Waiver, error) {
+	items := m.activeWaivers(evidenceID)
+	if evidenceID == "" || len(items) == 0 {
+		return Waiver{}, sql.ErrNoRows
+	}
+	return items[len(items)-1], nil
+}
+
+func (m *MemoryStore) GetWaiversByEvidence(ctx context.Context, evidenceID string) ([]Waiver, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []Waiver
+	for _, w := range m.waivers {
+		if w.EvidenceID == evidenceID {
+			items = append(items, w)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].CreatedAt.Time.After(items[j].CreatedAt.Time) })
+	return items, nil
+}
+
+func (m *MemoryStore) GetAllActiveWaivers(ctx context.Context) ([]Waiver, error) {
+	return m.activeWaivers(""), nil
+}
+
+func (m *MemoryStore) InsertAuditLog(ctx context.Context, id, toolName, operation, actor, targetID, inputHash, result, details, contextID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var prev AuditLog
+	for _, a := range m.auditLog {
+		if a.ID == id {
+			return fmt.Errorf("audit entry %s already exists", id)
+		}
+		if a.Seq.Valid && a.Seq.Int64 > prev.Seq.Int64 {
+			prev = a
+		}
+	}
+	e := AuditLog{
+		ID:        id,
+		ToolName:  toolName,
+		Operation: operation,
+		Actor:     actor,
+		TargetID:  toNullString(targetID),
+		InputHash: toNullString(inputHash),
+		Result:    result,
+		Details:   toNullString(details),
+		ContextID: contextID,
+	}
+	chainAuditEntry(&e, prev, m.auditKey)
+	m.auditLog = append(m.auditLog, e)
+	return nil
+}
+
+func (m *MemoryStore) SetAuditKey(key ed25519.PrivateKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auditKey = key
+}
+
+func (m *MemoryStore) ListAuditLog(ctx context.Context) ([]AuditLog, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	items := append([]AuditLog(nil), m.auditLog...)
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Seq.Valid != items[j].Seq.Valid {
+			return !items[i].Seq.Valid
+		}
+		return items[i].Seq.Int64 < items[j].Seq.Int64
+	})
+	return items, nil
+}
+
+// filterAuditLog returns matching entries newest first.
+func (m *MemoryStore) filterAuditLog(keep func(AuditLog) bool, limit int64) []AuditLog {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []AuditLog
+	for i := len(m.auditLog) - 1; i >= 0 && (limit < 0 || int64(len(items)) < limit); i-- {
+		if keep(m.auditLog[i]) {
+			items = append(items, m.auditLog[i])
+		}
+	}
+	return items
+}
+
+func (m *MemoryStore) GetAuditLogByContext(ctx context.Context, contextID string) ([]AuditLog, error) {
+	return m.filterAuditLog(func(a AuditLog) bool { return a.ContextID == contextID }, -1), nil
+}
+
+func (m *MemoryStore) GetAuditLogByTarget(ctx context.Context, targetID string) ([]AuditLog, error) {
+	return m.filterAuditLog(func(a AuditLog) bool { return targetID != "" && a.TargetID.String == targetID }, -1), nil
+}
+
+func (m *MemoryStore) GetRecentAuditLog(ctx context.Context, limit int64) ([]AuditLog, error) {
+	return m.filterAuditLog(func(AuditLog) bool { return true }, limit), nil
+}
+
+func (m *MemoryStore) FilterAuditLog(ctx context.Context, f AuditLogFilter) ([]AuditLog, error) {
+	items := m.filterAuditLog(f.Match, -1)
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Seq.Valid != items[j].Seq.Valid {
+			return items[i].Seq.Valid
+		}
+		return items[i].Seq.Int64 > items[j].Seq.Int64
+	})
+	if f.Limit > 0 && int64(len(items)) > f.Limit {
+		items = items[:f.Limit]
+	}
+	return items, nil
+}
+
+func (m *MemoryStore) RecordWork(ctx context.Context, id, methodRef, performerRef string, startedAt, endedAt time.Time, ledger string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, w := range m.workRecords {
+		if w.ID == id {
+			return fmt.Errorf("work record %s already exists", id)
+		}
+	}
+	m.workRecords = append(m.workRecords, WorkRecord{
+		ID:             id,
+		MethodRef:      methodRef,
+		PerformerRef:   performerRef,
+		StartedAt:      startedAt,
+		EndedAt:        sql.NullTime{Time: endedAt, Valid: true},
+		ResourceLedger: toNullString(ledger),
+		CreatedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	return nil
+}
+
+func (m *MemoryStore) GetState(ctx context.Context, contextID string) (FpfState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st, ok := m.states[contextID]
+	if !ok {
+		return FpfState{}, sql.ErrNoRows
+	}
+	return st, nil
+}
+
+func (m *MemoryStore) SaveState(ctx context.Context, st FpfState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st.UpdatedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.states[st.ContextID] = st
+	return nil
+}
+
+func (m *MemoryStore) CreateContext(ctx context.Context, id, description string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.contexts {
+		if c.ID == id {
+			return fmt.Errorf("context %s already exists", id)
+		}
+	}
+	m.contexts = append(m.contexts, Context{
+		ID:          id,
+		Description: toNullString(description),
+		CreatedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	return nil
+}
+
+func (m *MemoryStore) GetContext(ctx context.Context, id string) (Context, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.contexts {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return Context{}, sql.ErrNoRows
+}
+
+func (m *MemoryStore) GetActiveContextID(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.contexts {
+		if c.IsActive == 1 {
+			return c.ID, nil
+		}
+	}
+	return DefaultContextID, nil
+}
+
+func (m *MemoryStore) ListContexts(ctx context.Context) ([]Context, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	items := append([]Context(nil), m.contexts...)
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+func (m *MemoryStore) SetActiveContext(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.contexts {
+		m.contexts[i].IsActive = 0
+		if m.contexts[i].ID == id {
+			m.contexts[i].IsActive = 1
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) InsertPhaseTransition(ctx context.Context, contextID, fromPhase, toPhase, role, sessionID, toolName, evidence, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.transitions = append(m.transitions, PhaseTransition{
+		ID:        int64(len(m.transitions) + 1),
+		ContextID: contextID,
+		FromPhase: fromPhase,
+		ToPhase:   toPhase,
+		Role:      toNullString(role),
+		SessionID: toNullString(sessionID),
+		ToolName:  toNullString(toolName),
+		Evidence:  toNullString(evidence),
+		Reason:    toNullString(reason),
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	return nil
+}
+
+func (m *MemoryStore) GetPhaseTransitions(ctx context.Context, contextID string) ([]PhaseTransition, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []PhaseTransition
+	for _, pt := range m.transitions {
+		if pt.ContextID == contextID {
+			items = append(items, pt)
+		}
+	}
+	return items, nil
+}
+
+func (m *MemoryStore) GetLastPhaseTransition(ctx context.Context, contextID string) (PhaseTransition, error) {
+	items, _ := m.GetPhaseTransitions(ctx, contextID)
+	if len(items) == 0 {
+		return PhaseTransition{}, sql.ErrNoRows
+	}
+	return items[len(items)-1], nil
+}
+
+// Search approximates the FTS5 query: every term must prefix-match a word in
+// the title or content, and title hits rank higher, matching the bm25 column
+// weights. Snippets are a window of content around the first hit.
+func (m *MemoryStore) Search(ctx context.Context, p SearchParams) ([]SearchResult, error) {
+	var terms []string
+	for _, term := range strings.Fields(strings.ToLower(p.Query)) {
+		if term = strings.ReplaceAll(term, `"`, ""); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search query is empty")
+	}
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	type document struct {
+		id, docType, holonID, title, content string
+	}
+	m.mu.Lock()
+	var docs []document
+	for _, h := range m.holons {
+		docs = append(docs, document{h.ID, holonDocType(h.Type), h.ID, h.Title, h.Content})
+	}
+	for _, e := range m.evidence {
+		docs = append(docs, document{e.ID, DocTypeEvidence, e.HolonID, e.Type, e.Content})
+	}
+	m.mu.Unlock()
+
+	var results []SearchResult
+	for _, d := range docs {
+		titleHits, contentHits := countTermHits(d.title, terms), countTermHits(d.content, terms)
+		matched := true
+		for _, term := range terms {
+			if titleHits[term]+contentHits[term] == 0 {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		h, err := m.GetHolon(ctx, d.holonID)
+		if err != nil {
+			continue
+		}
+		if (p.Layer != "" && h.Layer != p.Layer) ||
+			(p.Kind != "" && h.Kind.String != p.Kind) ||
+			(p.Scope != "" && !strings.Contains(h.Scope.String, p.Scope)) {
+			continue
+		}
+		if p.DecisionContext != "" {
+			members, _ := m.GetRelationsBySource(ctx, h.ID, "memberOf")
+			inContext := false
+			for _, r := range members {
+				inContext = inContext || r.TargetID == p.DecisionContext
+			}
+			if !inContext {
+				continue
+			}
+		}
+
+		var score float64
+		for _, term := range terms {
+			score += 5*float64(titleHits[term]) + float64(contentHits[term])
+		}
+		results = append(results, SearchResult{
+			DocID:        d.id,
+			DocType:      d.docType,
+			HolonID:      h.ID,
+			Title:        h.Title,
+			Layer:        h.Layer,
+			Kind:         h.Kind.String,
+			Scope:        h.Scope.String,
+			CachedRScore: h.CachedRScore.Float64,
+			Snippet:      snippetOf(d.content, terms),
+			Rank:         -score,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Rank < results[j].Rank })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func searchTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func countTermHits(text string, terms []string) map[string]int {
+	hits := make(map[string]int)
+	for _, token := range searchTokens(text) {
+		for _, term := range terms {
+			if strings.HasPrefix(token, term) {
+				hits[term]++
+			}
+		}
+	}
+	return hits
+}
+
+func snippetOf(content string, terms []string) string {
+	words := strings.Fields(content)
+	first := -1
+	for i, w := range words {
+		if len(countTermHits(w, terms)) == 0 {
+			continue
+		}
+		words[i] = "**" + w + "**"
+		if first < 0 {
+			first = i
+		}
+	}
+	start := 0
+	if first > 4 {
+		start = first - 4
+	}
+	end := start + 16
+	if end > len(words) {
+		end = len(words)
+	}
+	snippet := strings.Join(words[start:end], " ")
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(words) {
+		snippet += "…"
+	}
+	return snippet
+}
@@ -37,6 +37,156 @@ var migrations = []struct {
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
 	},
+	{
+		version:     4,
+		description: "Add knowledge_fts full-text index for quint_query",
+		sql: `CREATE VIRTUAL TABLE IF NOT EXISTS knowledge_fts USING fts5(
+			doc_id UNINDEXED,
+			doc_type UNINDEXED,
+			holon_id UNINDEXED,
+			title,
+			content,
+			tokenize = 'porter unicode61'
+		);
+		INSERT INTO knowledge_fts (doc_id, doc_type, holon_id, title, content)
+			SELECT id, CASE WHEN type = 'DRR' THEN 'drr' ELSE 'holon' END, id, title, content FROM holons;
+		INSERT INTO knowledge_fts (doc_id, doc_type, holon_id, title, content)
+			SELECT id, 'evidence', holon_id, type, content FROM evidence`,
+	},
+	{
+		version:     5,
+		description: "Add formality (F) to holons for the F-G-R tuple",
+		sql:         `ALTER TABLE holons ADD COLUMN formality INTEGER DEFAULT 0 CHECK(formality BETWEEN 0 AND 9)`,
+	},
+	{
+		version:     6,
+		description: "Add claim_scope (G) to holons for the F-G-R tuple",
+		sql:         `ALTER TABLE holons ADD COLUMN claim_scope TEXT`,
+	},
+	{
+		version:     7,
+		description: "Add formality_threshold to fpf_state for the Operation gate",
+		sql:         `ALTER TABLE fpf_state ADD COLUMN formality_threshold INTEGER DEFAULT 0 CHECK(formality_threshold BETWEEN 0 AND 9)`,
+	},
+	{
+		version:     8,
+		description: "Add cl_penalty_profile to fpf_state for configurable Φ(CL)",
+		sql:         `ALTER TABLE fpf_state ADD COLUMN cl_penalty_profile TEXT`,
+	},
+	{
+		version:     9,
+		description: "Add contexts table for multiple bounded contexts",
+		sql: `CREATE TABLE IF NOT EXISTS contexts (
+			id TEXT PRIMARY KEY,
+			description TEXT,
+			is_active INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		INSERT OR IGNORE INTO contexts (id, description, is_active) VALUES ('default', 'Default bounded context', 1);
+		INSERT OR IGNORE INTO contexts (id, is_active) SELECT DISTINCT context_id, 0 FROM holons`,
+	},
+	{
+		version:     10,
+		description: "Add strict_mode to fpf_state for role and session enforcement",
+		sql:         `ALTER TABLE fpf_state ADD COLUMN strict_mode INTEGER DEFAULT 0`,
+	},
+	{
+		version:     11,
+		description: "Add phase_transitions table for FSM history",
+		sql: `CREATE TABLE IF NOT EXISTS phase_transitions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			context_id TEXT NOT NULL DEFAULT 'default',
+			from_phase TEXT NOT NULL,
+			to_phase TEXT NOT NULL,
+			role TEXT,
+			session_id TEXT,
+			tool_name TEXT,
+			evidence TEXT,
+			reason TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_phase_transitions_context ON phase_transitions(context_id, id)`,
+	},
+	{
+		version:     12,
+		description: "Add suspect_evidence table for carrier change detection",
+		sql: `CREATE TABLE IF NOT EXISTS suspect_evidence (
+			evidence_id TEXT PRIMARY KEY,
+			holon_id TEXT NOT NULL,
+			changed_paths TEXT NOT NULL,
+			from_commit TEXT,
+			to_commit TEXT,
+			detected_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY(evidence_id) REFERENCES evidence(id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_suspect_evidence_holon ON suspect_evidence(holon_id)`,
+	},
+	{
+		version:     13,
+		description: "Add seq to audit_log for the hash chain",
+		sql:         `ALTER TABLE audit_log ADD COLUMN seq INTEGER`,
+	},
+	{
+		version:     14,
+		description: "Add prev_hash to audit_log for the hash chain",
+		sql:         `ALTER TABLE audit_log ADD COLUMN prev_hash TEXT`,
+	},
+	{
+		version:     15,
+		description: "Add entry_hash to audit_log for the hash chain",
+		sql:         `ALTER TABLE audit_log ADD COLUMN entry_hash TEXT`,
+	},
+	{
+		version:     16,
+		description: "Add signature to audit_log for signed audit entries",
+		sql:         `ALTER TABLE audit_log ADD COLUMN signature TEXT`,
+	},
+	{
+		version:     17,
+		description: "Add unique index on audit_log.seq so the chain cannot fork",
+		sql:         `CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_log_seq ON audit_log(seq)`,
+	},
+	{
+		version:     18,
+		description: "Add status to holons for the DRR lifecycle",
+		sql:         `ALTER TABLE holons ADD COLUMN status TEXT`,
+	},
+	{
+		version:     19,
+		description: "Mark DRRs recorded before the lifecycle as accepted",
+		sql:         `UPDATE holons SET status = 'accepted' WHERE type = 'DRR' AND status IS NULL`,
+	},
+	{
+		version:     20,
+		description: "Add drr_snapshots table for assurance frozen at decision time",
+		sql: `CREATE TABLE IF NOT EXISTS drr_snapshots (
+			drr_id TEXT NOT NULL,
+			holon_id TEXT NOT NULL,
+			role TEXT NOT NULL,
+			r_eff REAL NOT NULL,
+			audit_tree TEXT,
+			evidence TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (drr_id, holon_id),
+			FOREIGN KEY(drr_id) REFERENCES holons(id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_drr_snapshots_holon ON drr_snapshots(holon_id)`,
+	},
+	{
+		version:     21,
+		description: "Add direction to characteristics for multi-criteria comparison",
+		sql:         `ALTER TABLE characteristics ADD COLUMN direction TEXT`,
+	},
+	{
+		version:     22,
+		description: "Add decay_profiles to fpf_state for per-type evidence decay",
+		sql:         `ALTER TABLE fpf_state ADD COLUMN decay_profiles TEXT`,
+	},
+	{
+		version:     23,
+		description: "Add evidence_weighting to fpf_state for weighted self scores",
+		sql:         `ALTER TABLE fpf_state ADD COLUMN evidence_weighting TEXT`,
+	},
 }
 
 // RunMigrations applies all pending migrations to the database.
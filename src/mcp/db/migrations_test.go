@@ -20,7 +20,7 @@ func TestRunMigrations_FreshDatabase(t *testing.T) {
 
 	// Check schema_version table exists and has entries
 	var count int
-	err = store.conn.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&count)
+	err = store.db.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&count)
 	if err != nil {
 		t.Fatalf("Failed to query schema_version: %v", err)
 	}
@@ -66,7 +66,7 @@ func TestRunMigrations_ExistingDatabase(t *testing.T) {
 	// Verify new columns exist by querying them
 	var parentID sql.NullString
 	var cachedRScore sql.NullFloat64
-	err = store.conn.QueryRow("SELECT parent_id, cached_r_score FROM holons LIMIT 1").Scan(&parentID, &cachedRScore)
+	err = store.db.QueryRow("SELECT parent_id, cached_r_score FROM holons LIMIT 1").Scan(&parentID, &cachedRScore)
 	// Will get sql.ErrNoRows since table is empty, but query should not fail due to missing columns
 	if err != nil && err != sql.ErrNoRows {
 		t.Errorf("New columns should exist: %v", err)
@@ -74,7 +74,7 @@ func TestRunMigrations_ExistingDatabase(t *testing.T) {
 
 	// Verify migrations are recorded
 	var count int
-	store.conn.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&count)
+	store.db.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&count)
 	if count != len(migrations) {
 		t.Errorf("Expected %d migrations recorded, got %d", len(migrations), count)
 	}
@@ -99,7 +99,7 @@ func TestRunMigrations_Idempotent(t *testing.T) {
 
 	// Should still have same number of migration records
 	var count int
-	store2.conn.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&count)
+	store2.db.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&count)
 	if count != len(migrations) {
 		t.Errorf("Expected %d migrations, got %d (not idempotent)", len(migrations), count)
 	}
@@ -20,6 +20,10 @@ type AuditLog struct {
 	Result    string
 	Details   sql.NullString
 	ContextID string
+	Seq       sql.NullInt64
+	PrevHash  sql.NullString
+	EntryHash sql.NullString
+	Signature sql.NullString
 }
 
 type Characteristic struct {
@@ -29,6 +33,24 @@ type Characteristic struct {
 	Scale     string
 	Value     string
 	Unit      sql.NullString
+	Direction sql.NullString
+	CreatedAt sql.NullTime
+}
+
+type Context struct {
+	ID          string
+	Description sql.NullString
+	IsActive    int64
+	CreatedAt   sql.NullTime
+}
+
+type DrrSnapshot struct {
+	DrrID     string
+	HolonID   string
+	Role      string
+	REff      float64
+	AuditTree sql.NullString
+	Evidence  sql.NullString
 	CreatedAt sql.NullTime
 }
 
@@ -44,6 +66,21 @@ type Evidence struct {
 	CreatedAt      sql.NullTime
 }
 
+type FpfState struct {
+	ContextID          string
+	ActiveRole         sql.NullString
+	ActiveSessionID    sql.NullString
+	ActiveRoleContext  sql.NullString
+	LastCommit         sql.NullString
+	AssuranceThreshold sql.NullFloat64
+	UpdatedAt          sql.NullTime
+	FormalityThreshold sql.NullInt64
+	ClPenaltyProfile   sql.NullString
+	StrictMode         sql.NullInt64
+	DecayProfiles      sql.NullString
+	EvidenceWeighting  sql.NullString
+}
+
 type Holon struct {
 	ID           string
 	Type         string
@@ -55,10 +92,26 @@ type Holon struct {
 	Scope        sql.NullString
 	ParentID     sql.NullString
 	CachedRScore sql.NullFloat64
+	Formality    sql.NullInt64
+	ClaimScope   sql.NullString
+	Status       sql.NullString
 	CreatedAt    sql.NullTime
 	UpdatedAt    sql.NullTime
 }
 
+type PhaseTransition struct {
+	ID        int64
+	ContextID string
+	FromPhase string
+	ToPhase   string
+	Role      sql.NullString
+	SessionID sql.NullString
+	ToolName  sql.NullString
+	Evidence  sql.NullString
+	Reason    sql.NullString
+	CreatedAt sql.NullTime
+}
+
 type Relation struct {
 	SourceID        string
 	TargetID        string
@@ -67,6 +120,15 @@ type Relation struct {
 	CreatedAt       sql.NullTime
 }
 
+type SuspectEvidence struct {
+	EvidenceID   string
+	HolonID      string
+	ChangedPaths string
+	FromCommit   sql.NullString
+	ToCommit     sql.NullString
+	DetectedAt   sql.NullTime
+}
+
 type Waiver struct {
 	ID          string
 	EvidenceID  string
@@ -13,8 +13,14 @@ import (
 
 const addCharacteristic = `-- name: AddCharacteristic :exec
 
-INSERT INTO characteristics (id, holon_id, name, scale, value, unit, created_at)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO characteristics (id, holon_id, name, scale, value, unit, direction, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    scale = excluded.scale,
+    value = excluded.value,
+    unit = excluded.unit,
+    direction = excluded.direction,
+    created_at = excluded.created_at
 `
 
 type AddCharacteristicParams struct {
@@ -24,10 +30,12 @@ type AddCharacteristicParams struct {
 	Scale     string
 	Value     string
 	Unit      sql.NullString
+	Direction sql.NullString
 	CreatedAt sql.NullTime
 }
 
 // Characteristic queries
+// Recording a characteristic again replaces its value.
 func (q *Queries) AddCharacteristic(ctx context.Context, db DBTX, arg AddCharacteristicParams) error {
 	_, err := db.ExecContext(ctx, addCharacteristic,
 		arg.ID,
@@ -36,6 +44,7 @@ func (q *Queries) AddCharacteristic(ctx context.Context, db DBTX, arg AddCharact
 		arg.Scale,
 		arg.Value,
 		arg.Unit,
+		arg.Direction,
 		arg.CreatedAt,
 	)
 	return err
@@ -99,6 +108,29 @@ func (q *Queries) AddRelation(ctx context.Context, db DBTX, arg AddRelationParam
 	return err
 }
 
+const cacheHolonRScore = `-- name: CacheHolonRScore :exec
+UPDATE holons SET cached_r_score = ? WHERE id = ?
+`
+
+type CacheHolonRScoreParams struct {
+	CachedRScore sql.NullFloat64
+	ID           string
+}
+
+func (q *Queries) CacheHolonRScore(ctx context.Context, db DBTX, arg CacheHolonRScoreParams) error {
+	_, err := db.ExecContext(ctx, cacheHolonRScore, arg.CachedRScore, arg.ID)
+	return err
+}
+
+const clearSuspectEvidence = `-- name: ClearSuspectEvidence :exec
+DELETE FROM suspect_evidence WHERE holon_id = ?
+`
+
+func (q *Queries) ClearSuspectEvidence(ctx context.Context, db DBTX, holonID string) error {
+	_, err := db.ExecContext(ctx, clearSuspectEvidence, holonID)
+	return err
+}
+
 const countHolonsByLayer = `-- name: CountHolonsByLayer :many
 SELECT layer, COUNT(*) as count FROM holons WHERE context_id = ? GROUP BY layer
 `
@@ -131,6 +163,22 @@ func (q *Queries) CountHolonsByLayer(ctx context.Context, db DBTX, contextID str
 	return items, nil
 }
 
+const createContext = `-- name: CreateContext :exec
+INSERT INTO contexts (id, description, created_at) VALUES (?, ?, ?)
+`
+
+type CreateContextParams struct {
+	ID          string
+	Description sql.NullString
+	CreatedAt   sql.NullTime
+}
+
+// Context queries
+func (q *Queries) CreateContext(ctx context.Context, db DBTX, arg CreateContextParams) error {
+	_, err := db.ExecContext(ctx, createContext, arg.ID, arg.Description, arg.CreatedAt)
+	return err
+}
+
 const createHolon = `-- name: CreateHolon :exec
 
 
@@ -224,6 +272,98 @@ func (q *Queries) CreateWaiver(ctx context.Context, db DBTX, arg CreateWaiverPar
 	return err
 }
 
+const filterAuditLog = `-- name: FilterAuditLog :many
+SELECT id, timestamp, tool_name, operation, actor, target_id, input_hash, result, details, context_id, seq, prev_hash, entry_hash, signature FROM audit_log
+WHERE (?1 IS NULL OR tool_name = ?1)
+  AND (?2 IS NULL OR operation = ?2)
+  AND (?3 IS NULL OR actor = ?3 OR actor LIKE ?3 || '@%')
+  AND (?4 IS NULL OR target_id = ?4)
+  AND (?5 IS NULL OR result = ?5)
+  AND (?6 IS NULL OR context_id = ?6)
+  AND (?7 IS NULL OR timestamp >= ?7)
+  AND (?8 IS NULL OR timestamp < ?8)
+ORDER BY seq DESC, timestamp DESC
+LIMIT ?9
+`
+
+type FilterAuditLogParams struct {
+	ToolName  sql.NullString
+	Operation sql.NullString
+	Actor     sql.NullString
+	TargetID  sql.NullString
+	Result    sql.NullString
+	ContextID sql.NullString
+	Since     sql.NullString
+	Until     sql.NullString
+	MaxRows   int64
+}
+
+// Newest first. NULL filters match everything; actor also matches its
+// role@session form; since/until compare against the stored timestamp text.
+func (q *Queries) FilterAuditLog(ctx context.Context, db DBTX, arg FilterAuditLogParams) ([]AuditLog, error) {
+	rows, err := db.QueryContext(ctx, filterAuditLog,
+		arg.ToolName,
+		arg.Operation,
+		arg.Actor,
+		arg.TargetID,
+		arg.Result,
+		arg.ContextID,
+		arg.Since,
+		arg.Until,
+		arg.MaxRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Timestamp,
+			&i.ToolName,
+			&i.Operation,
+			&i.Actor,
+			&i.TargetID,
+			&i.InputHash,
+			&i.Result,
+			&i.Details,
+			&i.ContextID,
+			&i.Seq,
+			&i.PrevHash,
+			&i.EntryHash,
+			&i.Signature,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveContext = `-- name: GetActiveContext :one
+SELECT id, description, is_active, created_at FROM contexts WHERE is_active = 1 LIMIT 1
+`
+
+func (q *Queries) GetActiveContext(ctx context.Context, db DBTX) (Context, error) {
+	row := db.QueryRowContext(ctx, getActiveContext)
+	var i Context
+	err := row.Scan(
+		&i.ID,
+		&i.Description,
+		&i.IsActive,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getActiveWaiverForEvidence = `-- name: GetActiveWaiverForEvidence :one
 SELECT id, evidence_id, waived_by, waived_until, rationale, created_at FROM waivers
 WHERE evidence_id = ? AND waived_until > datetime('now')
@@ -279,7 +419,7 @@ func (q *Queries) GetAllActiveWaivers(ctx context.Context, db DBTX) ([]Waiver, e
 }
 
 const getAuditLogByContext = `-- name: GetAuditLogByContext :many
-SELECT id, timestamp, tool_name, operation, actor, target_id, input_hash, result, details, context_id FROM audit_log WHERE context_id = ? ORDER BY timestamp DESC
+SELECT id, timestamp, tool_name, operation, actor, target_id, input_hash, result, details, context_id, seq, prev_hash, entry_hash, signature FROM audit_log WHERE context_id = ? ORDER BY timestamp DESC
 `
 
 func (q *Queries) GetAuditLogByContext(ctx context.Context, db DBTX, contextID string) ([]AuditLog, error) {
@@ -302,6 +442,10 @@ func (q *Queries) GetAuditLogByContext(ctx context.Context, db DBTX, contextID s
 			&i.Result,
 			&i.Details,
 			&i.ContextID,
+			&i.Seq,
+			&i.PrevHash,
+			&i.EntryHash,
+			&i.Signature,
 		); err != nil {
 			return nil, err
 		}
@@ -317,7 +461,7 @@ func (q *Queries) GetAuditLogByContext(ctx context.Context, db DBTX, contextID s
 }
 
 const getAuditLogByTarget = `-- name: GetAuditLogByTarget :many
-SELECT id, timestamp, tool_name, operation, actor, target_id, input_hash, result, details, context_id FROM audit_log WHERE target_id = ? ORDER BY timestamp DESC
+SELECT id, timestamp, tool_name, operation, actor, target_id, input_hash, result, details, context_id, seq, prev_hash, entry_hash, signature FROM audit_log WHERE target_id = ? ORDER BY timestamp DESC
 `
 
 func (q *Queries) GetAuditLogByTarget(ctx context.Context, db DBTX, targetID sql.NullString) ([]AuditLog, error) {
@@ -340,6 +484,10 @@ func (q *Queries) GetAuditLogByTarget(ctx context.Context, db DBTX, targetID sql
 			&i.Result,
 			&i.Details,
 			&i.ContextID,
+			&i.Seq,
+			&i.PrevHash,
+			&i.EntryHash,
+			&i.Signature,
 		); err != nil {
 			return nil, err
 		}
@@ -355,7 +503,7 @@ func (q *Queries) GetAuditLogByTarget(ctx context.Context, db DBTX, targetID sql
 }
 
 const getCharacteristics = `-- name: GetCharacteristics :many
-SELECT id, holon_id, name, scale, value, unit, created_at FROM characteristics WHERE holon_id = ?
+SELECT id, holon_id, name, scale, value, unit, direction, created_at FROM characteristics WHERE holon_id = ? ORDER BY name
 `
 
 func (q *Queries) GetCharacteristics(ctx context.Context, db DBTX, holonID string) ([]Characteristic, error) {
@@ -374,6 +522,7 @@ func (q *Queries) GetCharacteristics(ctx context.Context, db DBTX, holonID strin
 			&i.Scale,
 			&i.Value,
 			&i.Unit,
+			&i.Direction,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -456,6 +605,77 @@ func (q *Queries) GetComponentsOf(ctx context.Context, db DBTX, targetID string)
 	return items, nil
 }
 
+const getDRRSnapshots = `-- name: GetDRRSnapshots :many
+SELECT drr_id, holon_id, role, r_eff, audit_tree, evidence, created_at FROM drr_snapshots WHERE drr_id = ? ORDER BY role = 'winner' DESC, holon_id
+`
+
+// Winner first, then the rejected alternatives.
+func (q *Queries) GetDRRSnapshots(ctx context.Context, db DBTX, drrID string) ([]DrrSnapshot, error) {
+	rows, err := db.QueryContext(ctx, getDRRSnapshots, drrID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DrrSnapshot
+	for rows.Next() {
+		var i DrrSnapshot
+		if err := rows.Scan(
+			&i.DrrID,
+			&i.HolonID,
+			&i.Role,
+			&i.REff,
+			&i.AuditTree,
+			&i.Evidence,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDRRSnapshotsByHolon = `-- name: GetDRRSnapshotsByHolon :many
+SELECT drr_id, holon_id, role, r_eff, audit_tree, evidence, created_at FROM drr_snapshots WHERE holon_id = ? ORDER BY created_at, drr_id
+`
+
+func (q *Queries) GetDRRSnapshotsByHolon(ctx context.Context, db DBTX, holonID string) ([]DrrSnapshot, error) {
+	rows, err := db.QueryContext(ctx, getDRRSnapshotsByHolon, holonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DrrSnapshot
+	for rows.Next() {
+		var i DrrSnapshot
+		if err := rows.Scan(
+			&i.DrrID,
+			&i.HolonID,
+			&i.Role,
+			&i.REff,
+			&i.AuditTree,
+			&i.Evidence,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getDependencies = `-- name: GetDependencies :many
 SELECT target_id, relation_type, congruence_level
 FROM relations
@@ -526,6 +746,22 @@ func (q *Queries) GetDependents(ctx context.Context, db DBTX, targetID string) (
 	return items, nil
 }
 
+const getContext = `-- name: GetContext :one
+SELECT id, description, is_active, created_at FROM contexts WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetContext(ctx context.Context, db DBTX, id string) (Context, error) {
+	row := db.QueryRowContext(ctx, getContext, id)
+	var i Context
+	err := row.Scan(
+		&i.ID,
+		&i.Description,
+		&i.IsActive,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getEvidenceByHolon = `-- name: GetEvidenceByHolon :many
 SELECT id, holon_id, type, content, verdict, assurance_level, carrier_ref, valid_until, created_at FROM evidence WHERE holon_id = ? ORDER BY created_at DESC
 `
@@ -621,8 +857,34 @@ func (q *Queries) GetEvidenceWithCarrier(ctx context.Context, db DBTX) ([]Eviden
 	return items, nil
 }
 
+const getFpfState = `-- name: GetFpfState :one
+
+SELECT context_id, active_role, active_session_id, active_role_context, last_commit, assurance_threshold, updated_at, formality_threshold, cl_penalty_profile, strict_mode, decay_profiles, evidence_weighting FROM fpf_state WHERE context_id = ? LIMIT 1
+`
+
+// FSM state queries
+func (q *Queries) GetFpfState(ctx context.Context, db DBTX, contextID string) (FpfState, error) {
+	row := db.QueryRowContext(ctx, getFpfState, contextID)
+	var i FpfState
+	err := row.Scan(
+		&i.ContextID,
+		&i.ActiveRole,
+		&i.ActiveSessionID,
+		&i.ActiveRoleContext,
+		&i.LastCommit,
+		&i.AssuranceThreshold,
+		&i.UpdatedAt,
+		&i.FormalityThreshold,
+		&i.ClPenaltyProfile,
+		&i.StrictMode,
+		&i.DecayProfiles,
+		&i.EvidenceWeighting,
+	)
+	return i, err
+}
+
 const getHolon = `-- name: GetHolon :one
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE id = ? LIMIT 1
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, formality, claim_scope, status, created_at, updated_at FROM holons WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) GetHolon(ctx context.Context, db DBTX, id string) (Holon, error) {
@@ -639,6 +901,9 @@ func (q *Queries) GetHolon(ctx context.Context, db DBTX, id string) (Holon, erro
 		&i.Scope,
 		&i.ParentID,
 		&i.CachedRScore,
+		&i.Formality,
+		&i.ClaimScope,
+		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -722,7 +987,7 @@ func (q *Queries) GetHolonTitle(ctx context.Context, db DBTX, id string) (string
 }
 
 const getHolonsByParent = `-- name: GetHolonsByParent :many
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE parent_id = ? ORDER BY created_at DESC
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, formality, claim_scope, status, created_at, updated_at FROM holons WHERE parent_id = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) GetHolonsByParent(ctx context.Context, db DBTX, parentID sql.NullString) ([]Holon, error) {
@@ -745,6 +1010,9 @@ func (q *Queries) GetHolonsByParent(ctx context.Context, db DBTX, parentID sql.N
 			&i.Scope,
 			&i.ParentID,
 			&i.CachedRScore,
+			&i.Formality,
+			&i.ClaimScope,
+			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -761,8 +1029,92 @@ func (q *Queries) GetHolonsByParent(ctx context.Context, db DBTX, parentID sql.N
 	return items, nil
 }
 
+const getImpactedHolons = `-- name: GetImpactedHolons :many
+SELECT source_id AS holon_id FROM relations
+WHERE target_id = ? AND relation_type IN ('dependsOn', 'selects')
+UNION
+SELECT target_id AS holon_id FROM relations
+WHERE source_id = ? AND relation_type IN ('componentOf', 'constituentOf', 'memberOf')
+`
+
+type GetImpactedHolonsParams struct {
+	TargetID string
+	SourceID string
+}
+
+func (q *Queries) GetImpactedHolons(ctx context.Context, db DBTX, arg GetImpactedHolonsParams) ([]string, error) {
+	rows, err := db.QueryContext(ctx, getImpactedHolons, arg.TargetID, arg.SourceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var holon_id string
+		if err := rows.Scan(&holon_id); err != nil {
+			return nil, err
+		}
+		items = append(items, holon_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLastAuditLog = `-- name: GetLastAuditLog :one
+SELECT id, timestamp, tool_name, operation, actor, target_id, input_hash, result, details, context_id, seq, prev_hash, entry_hash, signature FROM audit_log WHERE seq IS NOT NULL ORDER BY seq DESC LIMIT 1
+`
+
+func (q *Queries) GetLastAuditLog(ctx context.Context, db DBTX) (AuditLog, error) {
+	row := db.QueryRowContext(ctx, getLastAuditLog)
+	var i AuditLog
+	err := row.Scan(
+		&i.ID,
+		&i.Timestamp,
+		&i.ToolName,
+		&i.Operation,
+		&i.Actor,
+		&i.TargetID,
+		&i.InputHash,
+		&i.Result,
+		&i.Details,
+		&i.ContextID,
+		&i.Seq,
+		&i.PrevHash,
+		&i.EntryHash,
+		&i.Signature,
+	)
+	return i, err
+}
+
+const getLastPhaseTransition = `-- name: GetLastPhaseTransition :one
+SELECT id, context_id, from_phase, to_phase, role, session_id, tool_name, evidence, reason, created_at FROM phase_transitions WHERE context_id = ? ORDER BY id DESC LIMIT 1
+`
+
+func (q *Queries) GetLastPhaseTransition(ctx context.Context, db DBTX, contextID string) (PhaseTransition, error) {
+	row := db.QueryRowContext(ctx, getLastPhaseTransition, contextID)
+	var i PhaseTransition
+	err := row.Scan(
+		&i.ID,
+		&i.ContextID,
+		&i.FromPhase,
+		&i.ToPhase,
+		&i.Role,
+		&i.SessionID,
+		&i.ToolName,
+		&i.Evidence,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getLatestHolonByContext = `-- name: GetLatestHolonByContext :one
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE context_id = ? ORDER BY updated_at DESC LIMIT 1
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, formality, claim_scope, status, created_at, updated_at FROM holons WHERE context_id = ? ORDER BY updated_at DESC LIMIT 1
 `
 
 func (q *Queries) GetLatestHolonByContext(ctx context.Context, db DBTX, contextID string) (Holon, error) {
@@ -779,14 +1131,55 @@ func (q *Queries) GetLatestHolonByContext(ctx context.Context, db DBTX, contextI
 		&i.Scope,
 		&i.ParentID,
 		&i.CachedRScore,
+		&i.Formality,
+		&i.ClaimScope,
+		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const getPhaseTransitions = `-- name: GetPhaseTransitions :many
+SELECT id, context_id, from_phase, to_phase, role, session_id, tool_name, evidence, reason, created_at FROM phase_transitions WHERE context_id = ? ORDER BY id
+`
+
+func (q *Queries) GetPhaseTransitions(ctx context.Context, db DBTX, contextID string) ([]PhaseTransition, error) {
+	rows, err := db.QueryContext(ctx, getPhaseTransitions, contextID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PhaseTransition
+	for rows.Next() {
+		var i PhaseTransition
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContextID,
+			&i.FromPhase,
+			&i.ToPhase,
+			&i.Role,
+			&i.SessionID,
+			&i.ToolName,
+			&i.Evidence,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRecentAuditLog = `-- name: GetRecentAuditLog :many
-SELECT id, timestamp, tool_name, operation, actor, target_id, input_hash, result, details, context_id FROM audit_log ORDER BY timestamp DESC LIMIT ?
+SELECT id, timestamp, tool_name, operation, actor, target_id, input_hash, result, details, context_id, seq, prev_hash, entry_hash, signature FROM audit_log ORDER BY timestamp DESC LIMIT ?
 `
 
 func (q *Queries) GetRecentAuditLog(ctx context.Context, db DBTX, limit int64) ([]AuditLog, error) {
@@ -809,6 +1202,48 @@ func (q *Queries) GetRecentAuditLog(ctx context.Context, db DBTX, limit int64) (
 			&i.Result,
 			&i.Details,
 			&i.ContextID,
+			&i.Seq,
+			&i.PrevHash,
+			&i.EntryHash,
+			&i.Signature,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRelationsBySource = `-- name: GetRelationsBySource :many
+SELECT source_id, target_id, relation_type, congruence_level, created_at FROM relations WHERE source_id = ? AND relation_type = ?
+`
+
+type GetRelationsBySourceParams struct {
+	SourceID     string
+	RelationType string
+}
+
+func (q *Queries) GetRelationsBySource(ctx context.Context, db DBTX, arg GetRelationsBySourceParams) ([]Relation, error) {
+	rows, err := db.QueryContext(ctx, getRelationsBySource, arg.SourceID, arg.RelationType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Relation
+	for rows.Next() {
+		var i Relation
+		if err := rows.Scan(
+			&i.SourceID,
+			&i.TargetID,
+			&i.RelationType,
+			&i.CongruenceLevel,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -861,6 +1296,74 @@ func (q *Queries) GetRelationsByTarget(ctx context.Context, db DBTX, arg GetRela
 	return items, nil
 }
 
+const getSuspectEvidence = `-- name: GetSuspectEvidence :many
+SELECT evidence_id, holon_id, changed_paths, from_commit, to_commit, detected_at FROM suspect_evidence ORDER BY holon_id, evidence_id
+`
+
+func (q *Queries) GetSuspectEvidence(ctx context.Context, db DBTX) ([]SuspectEvidence, error) {
+	rows, err := db.QueryContext(ctx, getSuspectEvidence)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SuspectEvidence
+	for rows.Next() {
+		var i SuspectEvidence
+		if err := rows.Scan(
+			&i.EvidenceID,
+			&i.HolonID,
+			&i.ChangedPaths,
+			&i.FromCommit,
+			&i.ToCommit,
+			&i.DetectedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSuspectEvidenceByHolon = `-- name: GetSuspectEvidenceByHolon :many
+SELECT evidence_id, holon_id, changed_paths, from_commit, to_commit, detected_at FROM suspect_evidence WHERE holon_id = ? ORDER BY evidence_id
+`
+
+func (q *Queries) GetSuspectEvidenceByHolon(ctx context.Context, db DBTX, holonID string) ([]SuspectEvidence, error) {
+	rows, err := db.QueryContext(ctx, getSuspectEvidenceByHolon, holonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SuspectEvidence
+	for rows.Next() {
+		var i SuspectEvidence
+		if err := rows.Scan(
+			&i.EvidenceID,
+			&i.HolonID,
+			&i.ChangedPaths,
+			&i.FromCommit,
+			&i.ToCommit,
+			&i.DetectedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWaiversByEvidence = `-- name: GetWaiversByEvidence :many
 SELECT id, evidence_id, waived_by, waived_until, rationale, created_at FROM waivers WHERE evidence_id = ? ORDER BY created_at DESC
 `
@@ -897,12 +1400,13 @@ func (q *Queries) GetWaiversByEvidence(ctx context.Context, db DBTX, evidenceID
 
 const insertAuditLog = `-- name: InsertAuditLog :exec
 
-INSERT INTO audit_log (id, tool_name, operation, actor, target_id, input_hash, result, details, context_id)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO audit_log (id, timestamp, tool_name, operation, actor, target_id, input_hash, result, details, context_id, seq, prev_hash, entry_hash, signature)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type InsertAuditLogParams struct {
 	ID        string
+	Timestamp sql.NullTime
 	ToolName  string
 	Operation string
 	Actor     string
@@ -911,12 +1415,17 @@ type InsertAuditLogParams struct {
 	Result    string
 	Details   sql.NullString
 	ContextID string
+	Seq       sql.NullInt64
+	PrevHash  sql.NullString
+	EntryHash sql.NullString
+	Signature sql.NullString
 }
 
 // Audit log queries
 func (q *Queries) InsertAuditLog(ctx context.Context, db DBTX, arg InsertAuditLogParams) error {
 	_, err := db.ExecContext(ctx, insertAuditLog,
 		arg.ID,
+		arg.Timestamp,
 		arg.ToolName,
 		arg.Operation,
 		arg.Actor,
@@ -925,10 +1434,113 @@ func (q *Queries) InsertAuditLog(ctx context.Context, db DBTX, arg InsertAuditLo
 		arg.Result,
 		arg.Details,
 		arg.ContextID,
+		arg.Seq,
+		arg.PrevHash,
+		arg.EntryHash,
+		arg.Signature,
 	)
 	return err
 }
 
+const insertDRRSnapshot = `-- name: InsertDRRSnapshot :exec
+INSERT INTO drr_snapshots (drr_id, holon_id, role, r_eff, audit_tree, evidence, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type InsertDRRSnapshotParams struct {
+	DrrID     string
+	HolonID   string
+	Role      string
+	REff      float64
+	AuditTree sql.NullString
+	Evidence  sql.NullString
+	CreatedAt sql.NullTime
+}
+
+func (q *Queries) InsertDRRSnapshot(ctx context.Context, db DBTX, arg InsertDRRSnapshotParams) error {
+	_, err := db.ExecContext(ctx, insertDRRSnapshot,
+		arg.DrrID,
+		arg.HolonID,
+		arg.Role,
+		arg.REff,
+		arg.AuditTree,
+		arg.Evidence,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const insertPhaseTransition = `-- name: InsertPhaseTransition :exec
+
+INSERT INTO phase_transitions (context_id, from_phase, to_phase, role, session_id, tool_name, evidence, reason, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type InsertPhaseTransitionParams struct {
+	ContextID string
+	FromPhase string
+	ToPhase   string
+	Role      sql.NullString
+	SessionID sql.NullString
+	ToolName  sql.NullString
+	Evidence  sql.NullString
+	Reason    sql.NullString
+	CreatedAt sql.NullTime
+}
+
+// Phase transition queries
+func (q *Queries) InsertPhaseTransition(ctx context.Context, db DBTX, arg InsertPhaseTransitionParams) error {
+	_, err := db.ExecContext(ctx, insertPhaseTransition,
+		arg.ContextID,
+		arg.FromPhase,
+		arg.ToPhase,
+		arg.Role,
+		arg.SessionID,
+		arg.ToolName,
+		arg.Evidence,
+		arg.Reason,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const listAllEvidence = `-- name: ListAllEvidence :many
+SELECT id, holon_id, type, content, verdict, assurance_level, carrier_ref, valid_until, created_at FROM evidence ORDER BY created_at DESC
+`
+
+func (q *Queries) ListAllEvidence(ctx context.Context, db DBTX) ([]Evidence, error) {
+	rows, err := db.QueryContext(ctx, listAllEvidence)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Evidence
+	for rows.Next() {
+		var i Evidence
+		if err := rows.Scan(
+			&i.ID,
+			&i.HolonID,
+			&i.Type,
+			&i.Content,
+			&i.Verdict,
+			&i.AssuranceLevel,
+			&i.CarrierRef,
+			&i.ValidUntil,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAllHolonIDs = `-- name: ListAllHolonIDs :many
 SELECT id FROM holons
 `
@@ -956,8 +1568,126 @@ func (q *Queries) ListAllHolonIDs(ctx context.Context, db DBTX) ([]string, error
 	return items, nil
 }
 
+const listAuditLog = `-- name: ListAuditLog :many
+SELECT id, timestamp, tool_name, operation, actor, target_id, input_hash, result, details, context_id, seq, prev_hash, entry_hash, signature FROM audit_log ORDER BY seq, timestamp
+`
+
+// Chain order: entries written before chaining (seq NULL) come first.
+func (q *Queries) ListAuditLog(ctx context.Context, db DBTX) ([]AuditLog, error) {
+	rows, err := db.QueryContext(ctx, listAuditLog)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Timestamp,
+			&i.ToolName,
+			&i.Operation,
+			&i.Actor,
+			&i.TargetID,
+			&i.InputHash,
+			&i.Result,
+			&i.Details,
+			&i.ContextID,
+			&i.Seq,
+			&i.PrevHash,
+			&i.EntryHash,
+			&i.Signature,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listContexts = `-- name: ListContexts :many
+SELECT id, description, is_active, created_at FROM contexts ORDER BY id
+`
+
+func (q *Queries) ListContexts(ctx context.Context, db DBTX) ([]Context, error) {
+	rows, err := db.QueryContext(ctx, listContexts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Context
+	for rows.Next() {
+		var i Context
+		if err := rows.Scan(
+			&i.ID,
+			&i.Description,
+			&i.IsActive,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHolons = `-- name: ListHolons :many
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, formality, claim_scope, status, created_at, updated_at FROM holons ORDER BY layer, id
+`
+
+func (q *Queries) ListHolons(ctx context.Context, db DBTX) ([]Holon, error) {
+	rows, err := db.QueryContext(ctx, listHolons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Holon
+	for rows.Next() {
+		var i Holon
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Kind,
+			&i.Layer,
+			&i.Title,
+			&i.Content,
+			&i.ContextID,
+			&i.Scope,
+			&i.ParentID,
+			&i.CachedRScore,
+			&i.Formality,
+			&i.ClaimScope,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listHolonsByLayer = `-- name: ListHolonsByLayer :many
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE layer = ? ORDER BY created_at DESC
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, formality, claim_scope, status, created_at, updated_at FROM holons WHERE layer = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) ListHolonsByLayer(ctx context.Context, db DBTX, layer string) ([]Holon, error) {
@@ -980,6 +1710,9 @@ func (q *Queries) ListHolonsByLayer(ctx context.Context, db DBTX, layer string)
 			&i.Scope,
 			&i.ParentID,
 			&i.CachedRScore,
+			&i.Formality,
+			&i.ClaimScope,
+			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -996,6 +1729,36 @@ func (q *Queries) ListHolonsByLayer(ctx context.Context, db DBTX, layer string)
 	return items, nil
 }
 
+const markEvidenceSuspect = `-- name: MarkEvidenceSuspect :exec
+
+INSERT INTO suspect_evidence (evidence_id, holon_id, changed_paths, from_commit, to_commit, detected_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT(evidence_id)
+DO UPDATE SET changed_paths = excluded.changed_paths, to_commit = excluded.to_commit
+`
+
+type MarkEvidenceSuspectParams struct {
+	EvidenceID   string
+	HolonID      string
+	ChangedPaths string
+	FromCommit   sql.NullString
+	ToCommit     sql.NullString
+	DetectedAt   sql.NullTime
+}
+
+// Suspect evidence queries
+func (q *Queries) MarkEvidenceSuspect(ctx context.Context, db DBTX, arg MarkEvidenceSuspectParams) error {
+	_, err := db.ExecContext(ctx, markEvidenceSuspect,
+		arg.EvidenceID,
+		arg.HolonID,
+		arg.ChangedPaths,
+		arg.FromCommit,
+		arg.ToCommit,
+		arg.DetectedAt,
+	)
+	return err
+}
+
 const recordWork = `-- name: RecordWork :exec
 
 INSERT INTO work_records (id, method_ref, performer_ref, started_at, ended_at, resource_ledger, created_at)
@@ -1026,6 +1789,45 @@ func (q *Queries) RecordWork(ctx context.Context, db DBTX, arg RecordWorkParams)
 	return err
 }
 
+const setActiveContext = `-- name: SetActiveContext :exec
+UPDATE contexts SET is_active = CASE WHEN id = ? THEN 1 ELSE 0 END
+`
+
+func (q *Queries) SetActiveContext(ctx context.Context, db DBTX, id string) error {
+	_, err := db.ExecContext(ctx, setActiveContext, id)
+	return err
+}
+
+const unmarkEvidenceSuspect = `-- name: UnmarkEvidenceSuspect :exec
+DELETE FROM suspect_evidence WHERE evidence_id = ?
+`
+
+func (q *Queries) UnmarkEvidenceSuspect(ctx context.Context, db DBTX, evidenceID string) error {
+	_, err := db.ExecContext(ctx, unmarkEvidenceSuspect, evidenceID)
+	return err
+}
+
+const updateHolonClaim = `-- name: UpdateHolonClaim :exec
+UPDATE holons SET formality = ?, claim_scope = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateHolonClaimParams struct {
+	Formality  sql.NullInt64
+	ClaimScope sql.NullString
+	UpdatedAt  sql.NullTime
+	ID         string
+}
+
+func (q *Queries) UpdateHolonClaim(ctx context.Context, db DBTX, arg UpdateHolonClaimParams) error {
+	_, err := db.ExecContext(ctx, updateHolonClaim,
+		arg.Formality,
+		arg.ClaimScope,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
 const updateHolonLayer = `-- name: UpdateHolonLayer :exec
 UPDATE holons SET layer = ?, updated_at = ? WHERE id = ?
 `
@@ -1041,6 +1843,21 @@ func (q *Queries) UpdateHolonLayer(ctx context.Context, db DBTX, arg UpdateHolon
 	return err
 }
 
+const updateHolonStatus = `-- name: UpdateHolonStatus :exec
+UPDATE holons SET status = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateHolonStatusParams struct {
+	Status    sql.NullString
+	UpdatedAt sql.NullTime
+	ID        string
+}
+
+func (q *Queries) UpdateHolonStatus(ctx context.Context, db DBTX, arg UpdateHolonStatusParams) error {
+	_, err := db.ExecContext(ctx, updateHolonStatus, arg.Status, arg.UpdatedAt, arg.ID)
+	return err
+}
+
 const updateHolonRScore = `-- name: UpdateHolonRScore :exec
 UPDATE holons SET cached_r_score = ?, updated_at = ? WHERE id = ?
 `
@@ -1055,3 +1872,53 @@ func (q *Queries) UpdateHolonRScore(ctx context.Context, db DBTX, arg UpdateHolo
 	_, err := db.ExecContext(ctx, updateHolonRScore, arg.CachedRScore, arg.UpdatedAt, arg.ID)
 	return err
 }
+
+const upsertFpfState = `-- name: UpsertFpfState :exec
+INSERT INTO fpf_state (context_id, active_role, active_session_id, active_role_context, last_commit, assurance_threshold, formality_threshold, cl_penalty_profile, strict_mode, decay_profiles, evidence_weighting, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(context_id) DO UPDATE SET
+    active_role = excluded.active_role,
+    active_session_id = excluded.active_session_id,
+    active_role_context = excluded.active_role_context,
+    last_commit = excluded.last_commit,
+    assurance_threshold = excluded.assurance_threshold,
+    formality_threshold = excluded.formality_threshold,
+    cl_penalty_profile = excluded.cl_penalty_profile,
+    strict_mode = excluded.strict_mode,
+    decay_profiles = excluded.decay_profiles,
+    evidence_weighting = excluded.evidence_weighting,
+    updated_at = excluded.updated_at
+`
+
+type UpsertFpfStateParams struct {
+	ContextID          string
+	ActiveRole         sql.NullString
+	ActiveSessionID    sql.NullString
+	ActiveRoleContext  sql.NullString
+	LastCommit         sql.NullString
+	AssuranceThreshold sql.NullFloat64
+	FormalityThreshold sql.NullInt64
+	ClPenaltyProfile   sql.NullString
+	StrictMode         sql.NullInt64
+	DecayProfiles      sql.NullString
+	EvidenceWeighting  sql.NullString
+	UpdatedAt          sql.NullTime
+}
+
+func (q *Queries) UpsertFpfState(ctx context.Context, db DBTX, arg UpsertFpfStateParams) error {
+	_, err := db.ExecContext(ctx, upsertFpfState,
+		arg.ContextID,
+		arg.ActiveRole,
+		arg.ActiveSessionID,
+		arg.ActiveRoleContext,
+		arg.LastCommit,
+		arg.AssuranceThreshold,
+		arg.FormalityThreshold,
+		arg.ClPenaltyProfile,
+		arg.StrictMode,
+		arg.DecayProfiles,
+		arg.EvidenceWeighting,
+		arg.UpdatedAt,
+	)
+	return err
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"crypto/ed25519"
+	"time"
+)
+
+// Repository is the storage the FPF tools, FSM and assurance calculator run
+// against. Store is the SQLite implementation; MemoryStore keeps everything
+// in process for tests.
+//
+// Single-row getters return sql.ErrNoRows when nothing matches, whichever
+// backend is in use, so callers can keep checking errors the same way.
+type Repository interface {
+	HolonRepository
+	EvidenceRepository
+	RelationRepository
+	WaiverRepository
+	AuditRepository
+	StateRepository
+
+	Search(ctx context.Context, p SearchParams) ([]SearchResult, error)
+
+	// WithTx runs fn as one unit of work: every write fn makes through the
+	// Repository it is given is kept if fn returns nil and discarded if it
+	// returns an error. Nested calls join the outer unit.
+	WithTx(ctx context.Context, fn func(Repository) error) error
+
+	Close() error
+}
+
+// HolonRepository stores hypotheses, decision contexts and DRRs.
+type HolonRepository interface {
+	CreateHolon(ctx context.Context, id, typ, kind, layer, title, content, contextID, scope, parentID string) error
+	GetHolon(ctx context.Context, id string) (Holon, error)
+	GetHolonTitle(ctx context.Context, id string) (string, error)
+	ListAllHolonIDs(ctx context.Context) ([]string, error)
+	ListHolons(ctx context.Context) ([]Holon, error)
+	UpdateHolonLayer(ctx context.Context, id, layer string) error
+	UpdateHolonClaim(ctx context.Context, id string, formality int, claimScope string) error
+	UpdateHolonStatus(ctx context.Context, id, status string) error
+	CacheHolonRScore(ctx context.Context, id string, score float64) error
+	GetHolonsByParent(ctx context.Context, parentID string) ([]Holon, error)
+	GetHolonLineage(ctx context.Context, id string) ([]GetHolonLineageRow, error)
+	CountHolonsByLayer(ctx context.Context, contextID string) ([]CountHolonsByLayerRow, error)
+	GetLatestHolonByContext(ctx context.Context, contextID string) (Holon, error)
+	InsertDRRSnapshot(ctx context.Context, drrID, holonID, role string, rEff float64, auditTree, evidence string) error
+	GetDRRSnapshots(ctx context.Context, drrID string) ([]DrrSnapshot, error)
+	GetDRRSnapshotsByHolon(ctx context.Context, holonID string) ([]DrrSnapshot, error)
+	AddCharacteristic(ctx context.Context, holonID, name, scale, value, unit, direction string) error
+	GetCharacteristics(ctx context.Context, holonID string) ([]Characteristic, error)
+}
+
+// EvidenceRepository stores evidence and its suspect markers.
+type EvidenceRepository interface {
+	AddEvidence(ctx context.Context, id, holonID, typ, content, verdict, assuranceLevel, carrierRef, validUntil string) error
+	GetEvidence(ctx context.Context, holonID string) ([]Evidence, error)
+	GetEvidenceByID(ctx context.Context, id string) (Evidence, error)
+	GetEvidenceWithCarrier(ctx context.Context) ([]Evidence, error)
+	ListAllEvidence(ctx context.Context) ([]Evidence, error)
+	MarkEvidenceSuspect(ctx context.Context, evidenceID, holonID, changedPaths, fromCommit, toCommit string) error
+	GetSuspectEvidence(ctx context.Context) ([]SuspectEvidence, error)
+	GetSuspectEvidenceByHolon(ctx context.Context, holonID string) ([]SuspectEvidence, error)
+	ClearSuspectEvidence(ctx context.Context, holonID string) error
+	UnmarkEvidenceSuspect(ctx context.Context, evidenceID string) error
+}
+
+// RelationRepository stores the holon graph.
+type RelationRepository interface {
+	Link(ctx context.Context, source, target, relType string) error
+	CreateRelation(ctx context.Context, sourceID, relationType, targetID string, cl int) error
+	GetRelationsByTarget(ctx context.Context, targetID, relationType string) ([]Relation, error)
+	GetRelationsBySource(ctx context.Context, sourceID, relationType string) ([]Relation, error)
+	GetComponentsOf(ctx context.Context, targetID string) ([]GetComponentsOfRow, error)
+	GetCollectionMembers(ctx context.Context, targetID string) ([]GetCollectionMembersRow, error)
+	GetDependencies(ctx context.Context, sourceID string) ([]GetDependenciesRow, error)
+	GetImpactedHolons(ctx context.Context, id string) ([]string, error)
+}
+
+// WaiverRepository stores temporary acceptances of stale evidence.
+type WaiverRepository interface {
+	CreateWaiver(ctx context.Context, id, evidenceID, waivedBy string, waivedUntil time.Time, rationale string) error
+	GetActiveWaiverForEvidence(ctx context.Context, evidenceID string) (Waiver, error)
+	GetWaiversByEvidence(ctx context.Context, evidenceID string) ([]Waiver, error)
+	GetAllActiveWaivers(ctx context.Context) ([]Waiver, error)
+}
+
+// AuditRepository stores the audit log and work records. InsertAuditLog
+// appends to the hash chain, signing the entry if an audit key is set.
+type AuditRepository interface {
+	InsertAuditLog(ctx context.Context, id, toolName, operation, actor, targetID, inputHash, result, details, contextID string) error
+	SetAuditKey(key ed25519.PrivateKey)
+	ListAuditLog(ctx context.Context) ([]AuditLog, error)
+	GetAuditLogByContext(ctx context.Context, contextID string) ([]AuditLog, error)
+	GetAuditLogByTarget(ctx context.Context, targetID string) ([]AuditLog, error)
+	GetRecentAuditLog(ctx context.Context, limit int64) ([]AuditLog, error)
+	FilterAuditLog(ctx context.Context, f AuditLogFilter) ([]AuditLog, error)
+	RecordWork(ctx context.Context, id, methodRef, performerRef string, startedAt, endedAt time.Time, ledger string) error
+}
+
+// StateRepository stores FSM state, bounded contexts and phase history.
+type StateRepository interface {
+	GetState(ctx context.Context, contextID string) (FpfState, error)
+	SaveState(ctx context.Context, st FpfState) error
+	CreateContext(ctx context.Context, id, description string) error
+	GetContext(ctx context.Context, id string) (Context, error)
+	GetActiveContextID(ctx context.Context) (string, error)
+	ListContexts(ctx context.Context) ([]Context, error)
+	SetActiveContext(ctx context.Context, id string) error
+	InsertPhaseTransition(ctx context.Context, contextID, fromPhase, toPhase, role, sessionID, toolName, evidence, reason string) error
+	GetPhaseTransitions(ctx context.Context, contextID string) ([]PhaseTransition, error)
+	GetLastPhaseTransition(ctx context.Context, contextID string) (PhaseTransition, error)
+}
+
+var (
+	_ Repository = (*Store)(nil)
+	_ Repository = (*MemoryStore)(nil)
+)
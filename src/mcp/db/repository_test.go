@@ -0,0 +1,480 @@
+package db
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// repositories returns a fresh instance of every Repository implementation.
+func repositories(t *testing.T) map[string]Repository {
+	t.Helper()
+	store, err := NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return map[string]Repository{"sqlite": store, "memory": NewMemoryStore()}
+}
+
+func TestRepository_Holons(t *testing.T) {
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if _, err := repo.GetHolon(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("Expected sql.ErrNoRows for a missing holon, got %v", err)
+			}
+
+			_ = repo.CreateHolon(ctx, "ctx", "decision_context", "", "L0", "Context", "c", "default", "", "")
+			_ = repo.CreateHolon(ctx, "b", "hypothesis", "system", "L0", "B", "b", "default", "", "ctx")
+			_ = repo.CreateHolon(ctx, "a", "hypothesis", "system", "L0", "A", "a", "default", "", "ctx")
+			_ = repo.CreateHolon(ctx, "other", "hypothesis", "system", "L0", "Other", "o", "payments", "", "")
+			if err := repo.CreateHolon(ctx, "a", "hypothesis", "system", "L0", "A", "a", "default", "", ""); err == nil {
+				t.Error("Expected duplicate holon to fail")
+			}
+
+			time.Sleep(5 * time.Millisecond)
+			_ = repo.UpdateHolonLayer(ctx, "b", "L1")
+			_ = repo.UpdateHolonClaim(ctx, "a", 4, `{"env":["prod"]}`)
+			_ = repo.CacheHolonRScore(ctx, "a", 0.5)
+
+			latest, err := repo.GetLatestHolonByContext(ctx, "default")
+			if err != nil || latest.ID != "a" {
+				t.Errorf("Expected latest holon a, got %q (%v)", latest.ID, err)
+			}
+
+			a, _ := repo.GetHolon(ctx, "a")
+			if a.Formality.Int64 != 4 || a.ClaimScope.String != `{"env":["prod"]}` || a.CachedRScore.Float64 != 0.5 {
+				t.Errorf("Unexpected holon a: %+v", a)
+			}
+
+			counts, _ := repo.CountHolonsByLayer(ctx, "default")
+			want := []CountHolonsByLayerRow{{Layer: "L0", Count: 2}, {Layer: "L1", Count: 1}}
+			if !reflect.DeepEqual(counts, want) {
+				t.Errorf("CountHolonsByLayer = %+v, want %+v", counts, want)
+			}
+
+			var ids []string
+			holons, _ := repo.ListHolons(ctx)
+			for _, h := range holons {
+				ids = append(ids, h.ID)
+			}
+			if !reflect.DeepEqual(ids, []string{"a", "ctx", "other", "b"}) {
+				t.Errorf("ListHolons order = %v", ids)
+			}
+
+			children, _ := repo.GetHolonsByParent(ctx, "ctx")
+			if len(children) != 2 {
+				t.Errorf("Expected 2 children of ctx, got %d", len(children))
+			}
+
+			lineage, _ := repo.GetHolonLineage(ctx, "a")
+			if len(lineage) != 2 || lineage[0].ID != "ctx" || lineage[0].Depth != 1 || lineage[1].ID != "a" {
+				t.Errorf("Unexpected lineage: %+v", lineage)
+			}
+		})
+	}
+}
+
+func TestRepository_EvidenceAndRelations(t *testing.T) {
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			_ = repo.AddEvidence(ctx, "e1", "h1", "test", "first", "pass", "L2", "cache/redis.go", "2030-01-02")
+			_ = repo.AddEvidence(ctx, "e2", "h1", "test", "second", "fail", "L2", "", "")
+
+			ev, _ := repo.GetEvidence(ctx, "h1")
+			if len(ev) != 2 {
+				t.Fatalf("Expected 2 evidence, got %d", len(ev))
+			}
+			e1, err := repo.GetEvidenceByID(ctx, "e1")
+			if err != nil || !e1.ValidUntil.Valid || e1.ValidUntil.Time.Year() != 2030 {
+				t.Errorf("Unexpected e1: %+v (%v)", e1, err)
+			}
+			if _, err := repo.GetEvidenceByID(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("Expected sql.ErrNoRows for missing evidence, got %v", err)
+			}
+			if carriers, _ := repo.GetEvidenceWithCarrier(ctx); len(carriers) != 1 || carriers[0].ID != "e1" {
+				t.Errorf("Expected only e1 to have a carrier, got %+v", carriers)
+			}
+
+			_ = repo.MarkEvidenceSuspect(ctx, "e1", "h1", "cache/redis.go", "aaa", "bbb")
+			_ = repo.MarkEvidenceSuspect(ctx, "e1", "h1", "cache/lru.go", "ccc", "ddd")
+			suspects, _ := repo.GetSuspectEvidenceByHolon(ctx, "h1")
+			if len(suspects) != 1 || suspects[0].ChangedPaths != "cache/lru.go" ||
+				suspects[0].FromCommit.String != "aaa" || suspects[0].ToCommit.String != "ddd" {
+				t.Errorf("Unexpected suspect rows: %+v", suspects)
+			}
+			_ = repo.ClearSuspectEvidence(ctx, "h1")
+			if all, _ := repo.GetSuspectEvidence(ctx); len(all) != 0 {
+				t.Errorf("Expected suspects cleared, got %+v", all)
+			}
+			_ = repo.MarkEvidenceSuspect(ctx, "e1", "h1", "cache/redis.go", "aaa", "bbb")
+			_ = repo.UnmarkEvidenceSuspect(ctx, "e1")
+			if all, _ := repo.GetSuspectEvidence(ctx); len(all) != 0 {
+				t.Errorf("Expected e1 unmarked, got %+v", all)
+			}
+
+			_ = repo.CreateRelation(ctx, "part", "componentOf", "whole", 2)
+			_ = repo.CreateRelation(ctx, "part", "componentOf", "whole", 1)
+			_ = repo.CreateRelation(ctx, "app", "dependsOn", "part", 3)
+			_ = repo.CreateRelation(ctx, "part", "memberOf", "decision", 3)
+			if err := repo.Link(ctx, "drr", "part", "selects"); err != nil {
+				t.Fatalf("Link failed: %v", err)
+			}
+			if err := repo.Link(ctx, "drr", "part", "selects"); err == nil {
+				t.Error("Expected duplicate Link to fail")
+			}
+
+			components, _ := repo.GetComponentsOf(ctx, "whole")
+			if len(components) != 1 || components[0].CongruenceLevel.Int64 != 1 {
+				t.Errorf("Expected upserted CL1 component, got %+v", components)
+			}
+			if deps, _ := repo.GetRelationsBySource(ctx, "app", "dependsOn"); len(deps) != 1 || deps[0].TargetID != "part" {
+				t.Errorf("Unexpected dependsOn relations: %+v", deps)
+			}
+			if members, _ := repo.GetCollectionMembers(ctx, "decision"); len(members) != 1 {
+				t.Errorf("Expected one member, got %+v", members)
+			}
+			impacted, _ := repo.GetImpactedHolons(ctx, "part")
+			if !reflect.DeepEqual(impacted, []string{"app", "decision", "drr", "whole"}) {
+				t.Errorf("GetImpactedHolons = %v", impacted)
+			}
+		})
+	}
+}
+
+func TestRepository_StateAuditAndWaivers(t *testing.T) {
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if _, err := repo.GetState(ctx, "default"); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("Expected sql.ErrNoRows before SaveState, got %v", err)
+			}
+			saved := FpfState{
+				ContextID:          "default",
+				ActiveRole:         sql.NullString{String: "Abductor", Valid: true},
+				AssuranceThreshold: sql.NullFloat64{Float64: 0.9, Valid: true},
+				StrictMode:         sql.NullInt64{Int64: 1, Valid: true},
+				DecayProfiles:      sql.NullString{String: "step,benchmark=half-life:30d", Valid: true},
+				EvidenceWeighting:  sql.NullString{String: "bayes,class:empirical=2", Valid: true},
+			}
+			if err := repo.SaveState(ctx, saved); err != nil {
+				t.Fatalf("SaveState failed: %v", err)
+			}
+			got, err := repo.GetState(ctx, "default")
+			if err != nil || got.ActiveRole.String != "Abductor" || got.AssuranceThreshold.Float64 != 0.9 || got.StrictMode.Int64 != 1 || got.DecayProfiles != saved.DecayProfiles || got.EvidenceWeighting != saved.EvidenceWeighting {
+				t.Errorf("Unexpected state: %+v (%v)", got, err)
+			}
+
+			if active, _ := repo.GetActiveContextID(ctx); active != DefaultContextID {
+				t.Errorf("Expected default context, got %s", active)
+			}
+			_ = repo.CreateContext(ctx, "payments", "Payments")
+			_ = repo.SetActiveContext(ctx, "payments")
+			if active, _ := repo.GetActiveContextID(ctx); active != "payments" {
+				t.Errorf("Expected payments to be active, got %s", active)
+			}
+
+			_ = repo.InsertPhaseTransition(ctx, "default", "IDLE", "ABDUCTION", "Abductor", "", "quint_propose", "", "")
+			_ = repo.InsertPhaseTransition(ctx, "default", "ABDUCTION", "DEDUCTION", "Deductor", "", "quint_verify", "", "")
+			last, err := repo.GetLastPhaseTransition(ctx, "default")
+			if err != nil || last.ToPhase != "DEDUCTION" {
+				t.Errorf("Unexpected last transition: %+v (%v)", last, err)
+			}
+
+			_ = repo.InsertAuditLog(ctx, "a1", "quint_propose", "propose", "Abductor", "h1", "", "SUCCESS", "", "default")
+			if entries, _ := repo.GetAuditLogByTarget(ctx, "h1"); len(entries) != 1 || entries[0].ToolName != "quint_propose" {
+				t.Errorf("Unexpected audit entries: %+v", entries)
+			}
+
+			_ = repo.CreateWaiver(ctx, "w1", "e1", "user", time.Now().Add(48*time.Hour), "later")
+			_ = repo.CreateWaiver(ctx, "w2", "e1", "user", time.Now().Add(24*time.Hour), "sooner")
+			_ = repo.CreateWaiver(ctx, "w3", "e2", "user", time.Now().Add(-time.Hour), "expired")
+			if w, err := repo.GetActiveWaiverForEvidence(ctx, "e1"); err != nil || w.ID != "w1" {
+				t.Errorf("Expected latest waiver w1, got %+v (%v)", w, err)
+			}
+			if _, err := repo.GetActiveWaiverForEvidence(ctx, "e2"); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("Expected no active waiver for e2, got %v", err)
+			}
+			if all, _ := repo.GetAllActiveWaivers(ctx); len(all) != 2 || all[0].ID != "w2" {
+				t.Errorf("Expected w2 then w1, got %+v", all)
+			}
+			if w, _ := repo.GetWaiversByEvidence(ctx, "e2"); len(w) != 1 || w[0].ID != "w3" {
+				t.Errorf("Expected the expired waiver w3 for e2, got %+v", w)
+			}
+		})
+	}
+}
+
+func TestRepository_Search(t *testing.T) {
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			_ = repo.CreateHolon(ctx, "redis", "hypothesis", "system", "L1", "Redis caching", "Use Redis for the session cache", "default", "", "")
+			_ = repo.CreateHolon(ctx, "lru", "hypothesis", "system", "L0", "LRU", "In-process cache", "default", "", "")
+			_ = repo.AddEvidence(ctx, "e1", "lru", "benchmark", "LRU cache hit ratio 92%", "pass", "L1", "", "")
+
+			results, err := repo.Search(ctx, SearchParams{Query: "cach"})
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if len(results) != 3 || results[0].DocID != "redis" {
+				t.Errorf("Expected 3 results with the title match first, got %+v", results)
+			}
+
+			results, _ = repo.Search(ctx, SearchParams{Query: "cache", Layer: "L0"})
+			for _, r := range results {
+				if r.HolonID != "lru" {
+					t.Errorf("Layer filter leaked %s", r.HolonID)
+				}
+			}
+
+			if _, err := repo.Search(ctx, SearchParams{Query: `""`}); err == nil {
+				t.Error("Expected an empty query to fail")
+			}
+		})
+	}
+}
+
+func TestRepository_WithTx(t *testing.T) {
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			err := repo.WithTx(ctx, func(tx Repository) error {
+				if err := tx.CreateHolon(ctx, "kept", "hypothesis", "system", "L0", "Kept", "k", "default", "", ""); err != nil {
+					return err
+				}
+				// A nested unit joins the outer one instead of committing early.
+				return tx.WithTx(ctx, func(inner Repository) error {
+					return inner.AddEvidence(ctx, "e1", "kept", "test", "ok", "pass", "L1", "", "")
+				})
+			})
+			if err != nil {
+				t.Fatalf("WithTx failed: %v", err)
+			}
+
+			failure := errors.New("boom")
+			err = repo.WithTx(ctx, func(tx Repository) error {
+				_ = tx.CreateHolon(ctx, "dropped", "hypothesis", "system", "L0", "Dropped", "d", "default", "", "")
+				_ = tx.UpdateHolonLayer(ctx, "kept", "L1")
+				_ = tx.CreateRelation(ctx, "dropped", "componentOf", "kept", 3)
+				if _, err := tx.GetHolon(ctx, "dropped"); err != nil {
+					t.Errorf("Expected uncommitted holon to be visible inside the unit: %v", err)
+				}
+				return failure
+			})
+			if !errors.Is(err, failure) {
+				t.Fatalf("Expected WithTx to return fn's error, got %v", err)
+			}
+
+			if _, err := repo.GetHolon(ctx, "dropped"); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("Expected rolled-back holon to be gone, got %v", err)
+			}
+			if kept, _ := repo.GetHolon(ctx, "kept"); kept.Layer != "L0" {
+				t.Errorf("Expected layer change to be rolled back, got %s", kept.Layer)
+			}
+			if components, _ := repo.GetComponentsOf(ctx, "kept"); len(components) != 0 {
+				t.Errorf("Expected rolled-back relation to be gone, got %+v", components)
+			}
+			if ev, _ := repo.GetEvidence(ctx, "kept"); len(ev) != 1 {
+				t.Errorf("Expected committed evidence to remain, got %d", len(ev))
+			}
+		})
+	}
+}
+
+func TestRepository_AuditChain(t *testing.T) {
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for _, id := range []string{"a1", "a2", "a3"} {
+				if err := repo.InsertAuditLog(ctx, id, "quint_propose", "create", "Abductor", "h1", "", "SUCCESS", "", "default"); err != nil {
+					t.Fatalf("InsertAuditLog failed: %v", err)
+				}
+			}
+
+			entries, err := repo.ListAuditLog(ctx)
+			if err != nil {
+				t.Fatalf("ListAuditLog failed: %v", err)
+			}
+			r := VerifyAuditChain(entries, nil)
+			if r.Break != nil || r.Checked != 3 || r.HeadSeq != 3 || r.HeadHash != entries[2].EntryHash.String {
+				t.Fatalf("Expected an intact chain of 3, got %+v (break %+v)", r, r.Break)
+			}
+
+			tampered := func(mutate func([]AuditLog) []AuditLog) *AuditChainBreak {
+				return VerifyAuditChain(mutate(append([]AuditLog(nil), entries...)), nil).Break
+			}
+			cases := []struct {
+				name    string
+				mutate  func([]AuditLog) []AuditLog
+				wantSeq int64
+				reason  string
+			}{
+				{"altered", func(e []AuditLog) []AuditLog { e[1].Result = "ERROR"; return e }, 2, "altered"},
+				{"deleted", func(e []AuditLog) []AuditLog { return append(e[:1], e[2:]...) }, 3, "expected entry #2"},
+				{"reordered", func(e []AuditLog) []AuditLog { e[0], e[1] = e[1], e[0]; return e }, 2, "expected entry #1"},
+				{"relinked", func(e []AuditLog) []AuditLog { e[2].PrevHash = e[0].EntryHash; return e }, 3, "prev_hash"},
+			}
+			for _, tc := range cases {
+				brk := tampered(tc.mutate)
+				if brk == nil || brk.Seq != tc.wantSeq || !strings.Contains(brk.Reason, tc.reason) {
+					t.Errorf("%s: expected break at #%d (%s), got %+v", tc.name, tc.wantSeq, tc.reason, brk)
+				}
+			}
+
+			pub, key, _ := ed25519.GenerateKey(nil)
+			repo.SetAuditKey(key)
+			_ = repo.InsertAuditLog(ctx, "a4", "quint_decide", "finalize", "Decider", "h1", "", "SUCCESS", "", "default")
+			_ = repo.InsertAuditLog(ctx, "a5", "quint_decide", "finalize", "Decider", "h1", "", "SUCCESS", "", "default")
+			entries, _ = repo.ListAuditLog(ctx)
+
+			if r := VerifyAuditChain(entries, pub); r.Break != nil || r.Signed != 2 {
+				t.Errorf("Expected 2 valid signatures, got %+v (break %+v)", r, r.Break)
+			}
+			otherPub, _, _ := ed25519.GenerateKey(nil)
+			if brk := VerifyAuditChain(entries, otherPub).Break; brk == nil || brk.Seq != 4 {
+				t.Errorf("Expected the wrong key to fail at #4, got %+v", brk)
+			}
+			entries[4].Signature = sql.NullString{}
+			if brk := VerifyAuditChain(entries, pub).Break; brk == nil || brk.Seq != 5 || !strings.Contains(brk.Reason, "unsigned") {
+				t.Errorf("Expected a stripped signature to fail at #5, got %+v", brk)
+			}
+		})
+	}
+}
+
+func TestRepository_FilterAuditLog(t *testing.T) {
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			start := time.Now().UTC().Add(-time.Second)
+			for _, e := range []struct{ id, tool, actor, target, result string }{
+				{"f1", "quint_propose", "Abductor@s1", "h1", "SUCCESS"},
+				{"f2", "quint_verify", "Deductor@s1", "h1", "ERROR"},
+				{"f3", "quint_verify", "Deductor@s2", "h2", "SUCCESS"},
+				{"f4", "quint_test", "agent", "h1", "SUCCESS"},
+			} {
+				if err := repo.InsertAuditLog(ctx, e.id, e.tool, "op", e.actor, e.target, "", e.result, "", "default"); err != nil {
+					t.Fatalf("InsertAuditLog failed: %v", err)
+				}
+			}
+
+			ids := func(f AuditLogFilter) string {
+				entries, err := repo.FilterAuditLog(ctx, f)
+				if err != nil {
+					t.Fatalf("FilterAuditLog(%+v) failed: %v", f, err)
+				}
+				var out []string
+				for _, e := range entries {
+					out = append(out, e.ID)
+				}
+				return strings.Join(out, ",")
+			}
+			cases := []struct {
+				name   string
+				filter AuditLogFilter
+				want   string
+			}{
+				{"all", AuditLogFilter{}, "f4,f3,f2,f1"},
+				{"tool", AuditLogFilter{ToolName: "quint_verify"}, "f3,f2"},
+				{"role", AuditLogFilter{Actor: "Deductor"}, "f3,f2"},
+				{"actor", AuditLogFilter{Actor: "Deductor@s2"}, "f3"},
+				{"target and result", AuditLogFilter{TargetID: "h1", Result: "SUCCESS"}, "f4,f1"},
+				{"limit", AuditLogFilter{TargetID: "h1", Limit: 2}, "f4,f2"},
+				{"since", AuditLogFilter{Since: start}, "f4,f3,f2,f1"},
+				{"until", AuditLogFilter{Until: start}, ""},
+				{"future", AuditLogFilter{Since: time.Now().Add(time.Hour)}, ""},
+			}
+			for _, tc := range cases {
+				if got := ids(tc.filter); got != tc.want {
+					t.Errorf("%s: expected %q, got %q", tc.name, tc.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRepository_DRRSnapshots(t *testing.T) {
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := repo.CreateHolon(ctx, "drr-1", "DRR", "", "DRR", "Cache", "body", "default", "", "h-b"); err != nil {
+				t.Fatalf("CreateHolon failed: %v", err)
+			}
+			if err := repo.UpdateHolonStatus(ctx, "drr-1", "proposed"); err != nil {
+				t.Fatalf("UpdateHolonStatus failed: %v", err)
+			}
+			if h, _ := repo.GetHolon(ctx, "drr-1"); h.Status.String != "proposed" {
+				t.Errorf("Expected status proposed, got %q", h.Status.String)
+			}
+
+			for _, s := range []struct {
+				holon, role string
+				r           float64
+			}{{"h-a", "rejected", 0.4}, {"h-b", "winner", 0.9}, {"h-c", "rejected", 0.6}} {
+				if err := repo.InsertDRRSnapshot(ctx, "drr-1", s.holon, s.role, s.r, "", `[]`); err != nil {
+					t.Fatalf("InsertDRRSnapshot failed: %v", err)
+				}
+			}
+			if err := repo.InsertDRRSnapshot(ctx, "drr-1", "h-a", "rejected", 0.5, "", ""); err == nil {
+				t.Error("Expected a second snapshot of the same holon in one DRR to fail")
+			}
+
+			snaps, err := repo.GetDRRSnapshots(ctx, "drr-1")
+			if err != nil {
+				t.Fatalf("GetDRRSnapshots failed: %v", err)
+			}
+			var order []string
+			for _, s := range snaps {
+				order = append(order, s.HolonID)
+			}
+			if strings.Join(order, ",") != "h-b,h-a,h-c" || snaps[0].REff != 0.9 || snaps[0].Evidence.String != "[]" {
+				t.Errorf("Expected the winner first, got %+v", snaps)
+			}
+			if byHolon, _ := repo.GetDRRSnapshotsByHolon(ctx, "h-c"); len(byHolon) != 1 || byHolon[0].Role != "rejected" {
+				t.Errorf("Expected one snapshot of h-c, got %+v", byHolon)
+			}
+		})
+	}
+}
+
+func TestRepository_Characteristics(t *testing.T) {
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := repo.CreateHolon(ctx, "h-a", "hypothesis", "system", "L1", "A", "body", "default", "", ""); err != nil {
+				t.Fatalf("CreateHolon failed: %v", err)
+			}
+			if err := repo.AddCharacteristic(ctx, "h-a", "latency", "ratio", "12", "ms", "lower"); err != nil {
+				t.Fatalf("AddCharacteristic failed: %v", err)
+			}
+			if err := repo.AddCharacteristic(ctx, "h-a", "cost", "ratio", "300", "usd", "lower"); err != nil {
+				t.Fatalf("AddCharacteristic failed: %v", err)
+			}
+			if err := repo.AddCharacteristic(ctx, "h-a", "latency", "ratio", "9", "ms", "lower"); err != nil {
+				t.Fatalf("Re-recording a characteristic failed: %v", err)
+			}
+
+			chars, err := repo.GetCharacteristics(ctx, "h-a")
+			if err != nil {
+				t.Fatalf("GetCharacteristics failed: %v", err)
+			}
+			if len(chars) != 2 || chars[0].Name != "cost" || chars[1].Value != "9" || chars[1].Direction.String != "lower" || chars[1].Unit.String != "ms" {
+				t.Errorf("Expected cost and the updated latency, got %+v", chars)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Document types in knowledge_fts, the FTS5 index kept outside sqlc.
+const (
+	DocTypeHolon    = "holon"
+	DocTypeEvidence = "evidence"
+	DocTypeDRR      = "drr"
+)
+
+const indexDocument = `INSERT INTO knowledge_fts (doc_id, doc_type, holon_id, title, content) VALUES (?, ?, ?, ?, ?)`
+
+const deleteDocument = `DELETE FROM knowledge_fts WHERE doc_id = ? AND doc_type = ?`
+
+// SearchParams filters a knowledge search. Empty strings mean "any".
+type SearchParams struct {
+	Query           string
+	Layer           string
+	Kind            string
+	Scope           string
+	DecisionContext string
+	Limit           int
+}
+
+type SearchResult struct {
+	DocID        string
+	DocType      string
+	HolonID      string
+	Title        string
+	Layer        string
+	Kind         string
+	Scope        string
+	CachedRScore float64
+	Snippet      string
+	Rank         float64
+}
+
+func holonDocType(typ string) string {
+	if typ == "DRR" {
+		return DocTypeDRR
+	}
+	return DocTypeHolon
+}
+
+func (s *Store) indexDocument(ctx context.Context, docID, docType, holonID, title, content string) error {
+	if _, err := s.conn.ExecContext(ctx, deleteDocument, docID, docType); err != nil {
+		return fmt.Errorf("failed to clear search index for %s: %w", docID, err)
+	}
+	if _, err := s.conn.ExecContext(ctx, indexDocument, docID, docType, holonID, title, content); err != nil {
+		return fmt.Errorf("failed to index %s: %w", docID, err)
+	}
+	return nil
+}
+
+// Search runs a ranked full-text query. Each whitespace-separated term is
+// matched as a prefix and all terms must match; FTS5 operators in the input
+// are treated as literal text.
+func (s *Store) Search(ctx context.Context, p SearchParams) ([]SearchResult, error) {
+	match := buildMatchExpression(p.Query)
+	if match == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	rows, err := s.conn.QueryContext(ctx, `
+		SELECT f.doc_id, f.doc_type, f.holon_id, h.title, h.layer,
+		       COALESCE(h.kind, ''), COALESCE(h.scope, ''), COALESCE(h.cached_r_score, 0.0),
+		       snippet(knowledge_fts, -1, '**', '**', '…', 16),
+		       bm25(knowledge_fts, 0.0, 0.0, 0.0, 5.0, 1.0) AS rank
+		FROM knowledge_fts f
+		JOIN holons h ON h.id = f.holon_id
+		WHERE knowledge_fts MATCH ?
+		  AND (? = '' OR h.layer = ?)
+		  AND (? = '' OR h.kind = ?)
+		  AND (? = '' OR h.scope LIKE '%' || ? || '%')
+		  AND (? = '' OR EXISTS (
+		        SELECT 1 FROM relations r
+		        WHERE r.source_id = h.id AND r.relation_type = 'memberOf' AND r.target_id = ?))
+		ORDER BY rank
+		LIMIT ?`,
+		match,
+		p.Layer, p.Layer,
+		p.Kind, p.Kind,
+		p.Scope, p.Scope,
+		p.DecisionContext, p.DecisionContext,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		if err := rows.Scan(&r.DocID, &r.DocType, &r.HolonID, &r.Title, &r.Layer, &r.Kind, &r.Scope, &r.CachedRScore, &r.Snippet, &r.Rank); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// RebuildSearchIndex repopulates knowledge_fts from the holons and evidence tables.
+func (s *Store) RebuildSearchIndex(ctx context.Context) error {
+	_, err := s.conn.ExecContext(ctx, `
+		DELETE FROM knowledge_fts;
+		INSERT INTO knowledge_fts (doc_id, doc_type, holon_id, title, content)
+			SELECT id, CASE WHEN type = 'DRR' THEN 'drr' ELSE 'holon' END, id, title, content FROM holons;
+		INSERT INTO knowledge_fts (doc_id, doc_type, holon_id, title, content)
+			SELECT id, 'evidence', holon_id, type, content FROM evidence;`)
+	return err
+}
+
+func buildMatchExpression(query string) string {
+	var terms []string
+	for _, term := range strings.Fields(query) {
+		term = strings.ReplaceAll(term, `"`, "")
+		if term == "" {
+			continue
+		}
+		terms = append(terms, `"`+term+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
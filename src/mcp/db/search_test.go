@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func setupSearchStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	ctx := context.Background()
+	_ = store.CreateHolon(ctx, "redis-caching", "hypothesis", "system", "L2", "Use Redis for caching", "Cache hot reads in a Redis cluster", "default", "backend api", "")
+	_ = store.CreateHolon(ctx, "cdn-edge", "hypothesis", "system", "L1", "CDN edge caching", "Serve static assets from the edge", "default", "frontend", "")
+	_ = store.CreateHolon(ctx, "team-process", "hypothesis", "episteme", "L0", "Weekly review", "Review caching metrics weekly", "default", "", "")
+	_ = store.CreateHolon(ctx, "caching-decision", "DRR", "", "DRR", "Caching strategy", "Selected Redis after benchmarks", "default", "", "")
+	_ = store.AddEvidence(ctx, "ev-bench", "cdn-edge", "benchmark", "Latency p99 dropped to 40ms under load", "pass", "L2", "", "")
+	_ = store.CreateRelation(ctx, "redis-caching", "memberOf", "caching-decision", 3)
+	return store
+}
+
+func TestStore_Search(t *testing.T) {
+	store := setupSearchStore(t)
+	ctx := context.Background()
+
+	results, err := store.Search(ctx, SearchParams{Query: "cach"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 prefix matches for 'cach', got %d: %+v", len(results), results)
+	}
+
+	results, err = store.Search(ctx, SearchParams{Query: "latency"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].DocType != DocTypeEvidence || results[0].HolonID != "cdn-edge" {
+		t.Fatalf("Expected evidence hit on cdn-edge, got %+v", results)
+	}
+	if results[0].Snippet == "" {
+		t.Error("Expected snippet for evidence hit")
+	}
+
+	results, _ = store.Search(ctx, SearchParams{Query: "redis"})
+	for _, r := range results {
+		if r.DocType == DocTypeDRR && r.HolonID != "caching-decision" {
+			t.Errorf("Unexpected DRR hit %+v", r)
+		}
+	}
+}
+
+func TestStore_SearchFilters(t *testing.T) {
+	store := setupSearchStore(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		params SearchParams
+		want   []string
+	}{
+		{"layer", SearchParams{Query: "caching", Layer: "L2"}, []string{"redis-caching"}},
+		{"kind", SearchParams{Query: "caching", Kind: "episteme"}, []string{"team-process"}},
+		{"scope", SearchParams{Query: "caching", Scope: "front"}, []string{"cdn-edge"}},
+		{"decision_context", SearchParams{Query: "caching", DecisionContext: "caching-decision"}, []string{"redis-caching"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := store.Search(ctx, tt.params)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			var got []string
+			for _, r := range results {
+				got = append(got, r.HolonID)
+			}
+			if len(got) != len(tt.want) || got[0] != tt.want[0] {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStore_SearchRejectsEmptyQuery(t *testing.T) {
+	store := setupSearchStore(t)
+	if _, err := store.Search(context.Background(), SearchParams{Query: `  "" `}); err == nil {
+		t.Error("Expected error for empty query")
+	}
+}
+
+func TestStore_RebuildSearchIndex(t *testing.T) {
+	store := setupSearchStore(t)
+	ctx := context.Background()
+
+	if _, err := store.db.Exec("DELETE FROM knowledge_fts"); err != nil {
+		t.Fatalf("Failed to clear index: %v", err)
+	}
+	if err := store.RebuildSearchIndex(ctx); err != nil {
+		t.Fatalf("RebuildSearchIndex failed: %v", err)
+	}
+
+	results, err := store.Search(ctx, SearchParams{Query: "latency"})
+	if err != nil || len(results) != 1 {
+		t.Errorf("Expected rebuilt index to find evidence, got %v (err %v)", results, err)
+	}
+}
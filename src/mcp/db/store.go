@@ -2,7 +2,9 @@ package db
 
 import (
 	"context"
+	"crypto/ed25519"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -21,6 +23,9 @@ CREATE TABLE IF NOT EXISTS holons (
 	scope TEXT,
 	parent_id TEXT REFERENCES holons(id),
 	cached_r_score REAL DEFAULT 0.0 CHECK(cached_r_score BETWEEN 0.0 AND 1.0),
+	formality INTEGER DEFAULT 0 CHECK(formality BETWEEN 0 AND 9),
+	claim_scope TEXT,
+	status TEXT, -- DRR lifecycle: proposed, accepted, superseded, deprecated
 	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
@@ -50,6 +55,7 @@ CREATE TABLE IF NOT EXISTS characteristics (
 	scale TEXT NOT NULL,
 	value TEXT NOT NULL,
 	unit TEXT,
+	direction TEXT, -- higher or lower is better
 	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 	FOREIGN KEY(holon_id) REFERENCES holons(id)
 );
@@ -72,7 +78,11 @@ CREATE TABLE IF NOT EXISTS audit_log (
 	input_hash TEXT,
 	result TEXT NOT NULL,
 	details TEXT,
-	context_id TEXT NOT NULL DEFAULT 'default'
+	context_id TEXT NOT NULL DEFAULT 'default',
+	seq INTEGER,
+	prev_hash TEXT,
+	entry_hash TEXT,
+	signature TEXT
 );
 CREATE TABLE IF NOT EXISTS waivers (
 	id TEXT PRIMARY KEY,
@@ -89,8 +99,12 @@ CREATE INDEX IF NOT EXISTS idx_waivers_evidence ON waivers(evidence_id);
 `
 
 type Store struct {
-	conn *sql.DB
+	db   *sql.DB
+	conn DBTX // db, or the open transaction of a Store returned by WithTx
 	q    *Queries
+	inTx bool
+
+	auditKey ed25519.PrivateKey // signs new audit entries when set
 }
 
 func NewStore(dbPath string) (*Store, error) {
@@ -108,22 +122,50 @@ func NewStore(dbPath string) (*Store, error) {
 	}
 
 	return &Store{
+		db:   conn,
 		conn: conn,
 		q:    New(),
 	}, nil
 }
 
 func (s *Store) GetRawDB() *sql.DB {
-	return s.conn
+	return s.db
 }
 
 func (s *Store) Close() error {
-	return s.conn.Close()
+	if s.inTx {
+		return fmt.Errorf("cannot close the database from inside a transaction")
+	}
+	return s.db.Close()
+}
+
+// WithTx runs fn against a Store bound to a new transaction, committing if fn
+// returns nil and rolling back otherwise. Calling WithTx on the Store passed
+// to fn joins the transaction already open.
+func (s *Store) WithTx(ctx context.Context, fn func(Repository) error) error {
+	if s.inTx {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	// A no-op once committed; undoes everything if fn fails or panics.
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(&Store{db: s.db, conn: tx, q: s.q, inTx: true, auditKey: s.auditKey}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
 }
 
 func (s *Store) CreateHolon(ctx context.Context, id, typ, kind, layer, title, content, contextID, scope, parentID string) error {
 	now := sql.NullTime{Time: time.Now(), Valid: true}
-	return s.q.CreateHolon(ctx, s.conn, CreateHolonParams{
+	err := s.q.CreateHolon(ctx, s.conn, CreateHolonParams{
 		ID:        id,
 		Type:      typ,
 		Kind:      toNullString(kind),
@@ -136,6 +178,10 @@ func (s *Store) CreateHolon(ctx context.Context, id, typ, kind, layer, title, co
 		CreatedAt: now,
 		UpdatedAt: now,
 	})
+	if err != nil {
+		return err
+	}
+	return s.indexDocument(ctx, id, holonDocType(typ), id, title, content)
 }
 
 func (s *Store) GetHolon(ctx context.Context, id string) (Holon, error) {
@@ -150,6 +196,10 @@ func (s *Store) ListAllHolonIDs(ctx context.Context) ([]string, error) {
 	return s.q.ListAllHolonIDs(ctx, s.conn)
 }
 
+func (s *Store) ListHolons(ctx context.Context) ([]Holon, error) {
+	return s.q.ListHolons(ctx, s.conn)
+}
+
 func (s *Store) UpdateHolonLayer(ctx context.Context, id, layer string) error {
 	return s.q.UpdateHolonLayer(ctx, s.conn, UpdateHolonLayerParams{
 		ID:        id,
@@ -158,6 +208,33 @@ func (s *Store) UpdateHolonLayer(ctx context.Context, id, layer string) error {
 	})
 }
 
+// UpdateHolonStatus sets a DRR's lifecycle status.
+func (s *Store) UpdateHolonStatus(ctx context.Context, id, status string) error {
+	return s.q.UpdateHolonStatus(ctx, s.conn, UpdateHolonStatusParams{
+		ID:        id,
+		Status:    toNullString(status),
+		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+func (s *Store) UpdateHolonClaim(ctx context.Context, id string, formality int, claimScope string) error {
+	return s.q.UpdateHolonClaim(ctx, s.conn, UpdateHolonClaimParams{
+		ID:         id,
+		Formality:  sql.NullInt64{Int64: int64(formality), Valid: true},
+		ClaimScope: toNullString(claimScope),
+		UpdatedAt:  sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+// CacheHolonRScore stores a computed R_eff. Unlike a layer or claim change it
+// leaves updated_at alone, so caching a score never moves the derived phase.
+func (s *Store) CacheHolonRScore(ctx context.Context, id string, score float64) error {
+	return s.q.CacheHolonRScore(ctx, s.conn, CacheHolonRScoreParams{
+		ID:           id,
+		CachedRScore: sql.NullFloat64{Float64: score, Valid: true},
+	})
+}
+
 func (s *Store) RecordWork(ctx context.Context, id, methodRef, performerRef string, startedAt, endedAt time.Time, ledger string) error {
 	return s.q.RecordWork(ctx, s.conn, RecordWorkParams{
 		ID:             id,
@@ -171,18 +248,7 @@ func (s *Store) RecordWork(ctx context.Context, id, methodRef, performerRef stri
 }
 
 func (s *Store) AddEvidence(ctx context.Context, id, holonID, typ, content, verdict, assuranceLevel, carrierRef, validUntil string) error {
-	var vUntil sql.NullTime
-	if validUntil != "" {
-		t, err := time.Parse(time.RFC3339, validUntil)
-		if err != nil {
-			t, err = time.Parse("2006-01-02", validUntil)
-		}
-		if err == nil {
-			vUntil = sql.NullTime{Time: t, Valid: true}
-		}
-	}
-
-	return s.q.AddEvidence(ctx, s.conn, AddEvidenceParams{
+	err := s.q.AddEvidence(ctx, s.conn, AddEvidenceParams{
 		ID:             id,
 		HolonID:        holonID,
 		Type:           typ,
@@ -190,9 +256,13 @@ func (s *Store) AddEvidence(ctx context.Context, id, holonID, typ, content, verd
 		Verdict:        verdict,
 		AssuranceLevel: toNullString(assuranceLevel),
 		CarrierRef:     toNullString(carrierRef),
-		ValidUntil:     vUntil,
+		ValidUntil:     parseValidUntil(validUntil),
 		CreatedAt:      sql.NullTime{Time: time.Now(), Valid: true},
 	})
+	if err != nil {
+		return err
+	}
+	return s.indexDocument(ctx, id, DocTypeEvidence, holonID, typ, content)
 }
 
 func (s *Store) GetEvidence(ctx context.Context, holonID string) ([]Evidence, error) {
@@ -203,6 +273,10 @@ func (s *Store) GetEvidenceWithCarrier(ctx context.Context) ([]Evidence, error)
 	return s.q.GetEvidenceWithCarrier(ctx, s.conn)
 }
 
+func (s *Store) ListAllEvidence(ctx context.Context) ([]Evidence, error) {
+	return s.q.ListAllEvidence(ctx, s.conn)
+}
+
 func (s *Store) Link(ctx context.Context, source, target, relType string) error {
 	return s.q.AddRelation(ctx, s.conn, AddRelationParams{
 		SourceID:     source,
@@ -221,6 +295,14 @@ func (s *Store) CreateRelation(ctx context.Context, sourceID, relationType, targ
 	})
 }
 
+func (s *Store) GetRelationsByTarget(ctx context.Context, targetID, relationType string) ([]Relation, error) {
+	return s.q.GetRelationsByTarget(ctx, s.conn, GetRelationsByTargetParams{TargetID: targetID, RelationType: relationType})
+}
+
+func (s *Store) GetRelationsBySource(ctx context.Context, sourceID, relationType string) ([]Relation, error) {
+	return s.q.GetRelationsBySource(ctx, s.conn, GetRelationsBySourceParams{SourceID: sourceID, RelationType: relationType})
+}
+
 func (s *Store) GetComponentsOf(ctx context.Context, targetID string) ([]GetComponentsOfRow, error) {
 	return s.q.GetComponentsOf(ctx, s.conn, targetID)
 }
@@ -233,6 +315,53 @@ func (s *Store) GetDependencies(ctx context.Context, sourceID string) ([]GetDepe
 	return s.q.GetDependencies(ctx, s.conn, sourceID)
 }
 
+// InsertDRRSnapshot records the assurance of one holon as it stood when a DRR
+// was recorded.
+func (s *Store) InsertDRRSnapshot(ctx context.Context, drrID, holonID, role string, rEff float64, auditTree, evidence string) error {
+	return s.q.InsertDRRSnapshot(ctx, s.conn, InsertDRRSnapshotParams{
+		DrrID:     drrID,
+		HolonID:   holonID,
+		Role:      role,
+		REff:      rEff,
+		AuditTree: toNullString(auditTree),
+		Evidence:  toNullString(evidence),
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+func (s *Store) GetDRRSnapshots(ctx context.Context, drrID string) ([]DrrSnapshot, error) {
+	return s.q.GetDRRSnapshots(ctx, s.conn, drrID)
+}
+
+func (s *Store) GetDRRSnapshotsByHolon(ctx context.Context, holonID string) ([]DrrSnapshot, error) {
+	return s.q.GetDRRSnapshotsByHolon(ctx, s.conn, holonID)
+}
+
+// AddCharacteristic records a named characteristic of a holon, replacing an
+// earlier value of the same name.
+func (s *Store) AddCharacteristic(ctx context.Context, holonID, name, scale, value, unit, direction string) error {
+	return s.q.AddCharacteristic(ctx, s.conn, AddCharacteristicParams{
+		ID:        characteristicID(holonID, name),
+		HolonID:   holonID,
+		Name:      name,
+		Scale:     scale,
+		Value:     value,
+		Unit:      toNullString(unit),
+		Direction: toNullString(direction),
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+func (s *Store) GetCharacteristics(ctx context.Context, holonID string) ([]Characteristic, error) {
+	return s.q.GetCharacteristics(ctx, s.conn, holonID)
+}
+
+// characteristicID keys a characteristic by holon and name, so each holon
+// has one value per characteristic.
+func characteristicID(holonID, name string) string {
+	return holonID + "/" + name
+}
+
 func (s *Store) GetHolonsByParent(ctx context.Context, parentID string) ([]Holon, error) {
 	return s.q.GetHolonsByParent(ctx, s.conn, toNullString(parentID))
 }
@@ -249,20 +378,57 @@ func (s *Store) GetLatestHolonByContext(ctx context.Context, contextID string) (
 	return s.q.GetLatestHolonByContext(ctx, s.conn, contextID)
 }
 
+// InsertAuditLog appends an entry to the audit chain. Reading the chain head
+// and inserting after it happen in one transaction.
 func (s *Store) InsertAuditLog(ctx context.Context, id, toolName, operation, actor, targetID, inputHash, result, details, contextID string) error {
-	return s.q.InsertAuditLog(ctx, s.conn, InsertAuditLogParams{
-		ID:        id,
-		ToolName:  toolName,
-		Operation: operation,
-		Actor:     actor,
-		TargetID:  toNullString(targetID),
-		InputHash: toNullString(inputHash),
-		Result:    result,
-		Details:   toNullString(details),
-		ContextID: contextID,
+	return s.WithTx(ctx, func(r Repository) error {
+		tx := r.(*Store)
+		prev, err := tx.q.GetLastAuditLog(ctx, tx.conn)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		e := AuditLog{
+			ID:        id,
+			ToolName:  toolName,
+			Operation: operation,
+			Actor:     actor,
+			TargetID:  toNullString(targetID),
+			InputHash: toNullString(inputHash),
+			Result:    result,
+			Details:   toNullString(details),
+			ContextID: contextID,
+		}
+		chainAuditEntry(&e, prev, tx.auditKey)
+
+		return tx.q.InsertAuditLog(ctx, tx.conn, InsertAuditLogParams{
+			ID:        e.ID,
+			Timestamp: e.Timestamp,
+			ToolName:  e.ToolName,
+			Operation: e.Operation,
+			Actor:     e.Actor,
+			TargetID:  e.TargetID,
+			InputHash: e.InputHash,
+			Result:    e.Result,
+			Details:   e.Details,
+			ContextID: e.ContextID,
+			Seq:       e.Seq,
+			PrevHash:  e.PrevHash,
+			EntryHash: e.EntryHash,
+			Signature: e.Signature,
+		})
 	})
 }
 
+// SetAuditKey makes the store sign every audit entry it writes from now on.
+func (s *Store) SetAuditKey(key ed25519.PrivateKey) {
+	s.auditKey = key
+}
+
+func (s *Store) ListAuditLog(ctx context.Context) ([]AuditLog, error) {
+	return s.q.ListAuditLog(ctx, s.conn)
+}
+
 func (s *Store) GetAuditLogByContext(ctx context.Context, contextID string) ([]AuditLog, error) {
 	return s.q.GetAuditLogByContext(ctx, s.conn, contextID)
 }
@@ -275,6 +441,11 @@ func (s *Store) GetRecentAuditLog(ctx context.Context, limit int64) ([]AuditLog,
 	return s.q.GetRecentAuditLog(ctx, s.conn, limit)
 }
 
+// FilterAuditLog returns the entries matching f, newest first.
+func (s *Store) FilterAuditLog(ctx context.Context, f AuditLogFilter) ([]AuditLog, error) {
+	return s.q.FilterAuditLog(ctx, s.conn, f.params())
+}
+
 func (s *Store) CreateWaiver(ctx context.Context, id, evidenceID, waivedBy string, waivedUntil time.Time, rationale string) error {
 	return s.q.CreateWaiver(ctx, s.conn, CreateWaiverParams{
 		ID:          id,
@@ -290,6 +461,12 @@ func (s *Store) GetActiveWaiverForEvidence(ctx context.Context, evidenceID strin
 	return s.q.GetActiveWaiverForEvidence(ctx, s.conn, evidenceID)
 }
 
+// GetWaiversByEvidence returns every waiver of the evidence, expired or not,
+// newest first.
+func (s *Store) GetWaiversByEvidence(ctx context.Context, evidenceID string) ([]Waiver, error) {
+	return s.q.GetWaiversByEvidence(ctx, s.conn, evidenceID)
+}
+
 func (s *Store) GetAllActiveWaivers(ctx context.Context) ([]Waiver, error) {
 	return s.q.GetAllActiveWaivers(ctx, s.conn)
 }
@@ -298,6 +475,142 @@ func (s *Store) GetEvidenceByID(ctx context.Context, id string) (Evidence, error
 	return s.q.GetEvidenceByID(ctx, s.conn, id)
 }
 
+// DefaultContextID is the bounded context used before any other is created.
+const DefaultContextID = "default"
+
+func (s *Store) CreateContext(ctx context.Context, id, description string) error {
+	return s.q.CreateContext(ctx, s.conn, CreateContextParams{
+		ID:          id,
+		Description: toNullString(description),
+		CreatedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+func (s *Store) GetContext(ctx context.Context, id string) (Context, error) {
+	return s.q.GetContext(ctx, s.conn, id)
+}
+
+// GetActiveContextID returns the active bounded context, or DefaultContextID
+// if none is marked active.
+func (s *Store) GetActiveContextID(ctx context.Context) (string, error) {
+	c, err := s.q.GetActiveContext(ctx, s.conn)
+	if err == sql.ErrNoRows {
+		return DefaultContextID, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return c.ID, nil
+}
+
+func (s *Store) ListContexts(ctx context.Context) ([]Context, error) {
+	return s.q.ListContexts(ctx, s.conn)
+}
+
+func (s *Store) SetActiveContext(ctx context.Context, id string) error {
+	return s.q.SetActiveContext(ctx, s.conn, id)
+}
+
+// InsertPhaseTransition records an accepted FSM transition. evidence is the
+// JSON-encoded evidence stub, or empty if none was given.
+func (s *Store) InsertPhaseTransition(ctx context.Context, contextID, fromPhase, toPhase, role, sessionID, toolName, evidence, reason string) error {
+	return s.q.InsertPhaseTransition(ctx, s.conn, InsertPhaseTransitionParams{
+		ContextID: contextID,
+		FromPhase: fromPhase,
+		ToPhase:   toPhase,
+		Role:      toNullString(role),
+		SessionID: toNullString(sessionID),
+		ToolName:  toNullString(toolName),
+		Evidence:  toNullString(evidence),
+		Reason:    toNullString(reason),
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+func (s *Store) GetPhaseTransitions(ctx context.Context, contextID string) ([]PhaseTransition, error) {
+	return s.q.GetPhaseTransitions(ctx, s.conn, contextID)
+}
+
+func (s *Store) GetLastPhaseTransition(ctx context.Context, contextID string) (PhaseTransition, error) {
+	return s.q.GetLastPhaseTransition(ctx, s.conn, contextID)
+}
+
+// GetImpactedHolons returns the holons that directly rely on id: dependents,
+// wholes it is a component or member of, and DRRs that selected it.
+func (s *Store) GetImpactedHolons(ctx context.Context, id string) ([]string, error) {
+	return s.q.GetImpactedHolons(ctx, s.conn, GetImpactedHolonsParams{TargetID: id, SourceID: id})
+}
+
+// MarkEvidenceSuspect flags evidence whose carrier changed between two
+// commits. Re-marking keeps the original detection time and commit.
+func (s *Store) MarkEvidenceSuspect(ctx context.Context, evidenceID, holonID, changedPaths, fromCommit, toCommit string) error {
+	return s.q.MarkEvidenceSuspect(ctx, s.conn, MarkEvidenceSuspectParams{
+		EvidenceID:   evidenceID,
+		HolonID:      holonID,
+		ChangedPaths: changedPaths,
+		FromCommit:   toNullString(fromCommit),
+		ToCommit:     toNullString(toCommit),
+		DetectedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+func (s *Store) GetSuspectEvidence(ctx context.Context) ([]SuspectEvidence, error) {
+	return s.q.GetSuspectEvidence(ctx, s.conn)
+}
+
+func (s *Store) GetSuspectEvidenceByHolon(ctx context.Context, holonID string) ([]SuspectEvidence, error) {
+	return s.q.GetSuspectEvidenceByHolon(ctx, s.conn, holonID)
+}
+
+func (s *Store) ClearSuspectEvidence(ctx context.Context, holonID string) error {
+	return s.q.ClearSuspectEvidence(ctx, s.conn, holonID)
+}
+
+// UnmarkEvidenceSuspect clears the suspect marker of one evidence record.
+func (s *Store) UnmarkEvidenceSuspect(ctx context.Context, evidenceID string) error {
+	return s.q.UnmarkEvidenceSuspect(ctx, s.conn, evidenceID)
+}
+
+// GetState returns the persisted FSM state of a bounded context, or
+// sql.ErrNoRows if it was never saved.
+func (s *Store) GetState(ctx context.Context, contextID string) (FpfState, error) {
+	return s.q.GetFpfState(ctx, s.conn, contextID)
+}
+
+// SaveState upserts the FSM state of st.ContextID.
+func (s *Store) SaveState(ctx context.Context, st FpfState) error {
+	return s.q.UpsertFpfState(ctx, s.conn, UpsertFpfStateParams{
+		ContextID:          st.ContextID,
+		ActiveRole:         st.ActiveRole,
+		ActiveSessionID:    st.ActiveSessionID,
+		ActiveRoleContext:  st.ActiveRoleContext,
+		LastCommit:         st.LastCommit,
+		AssuranceThreshold: st.AssuranceThreshold,
+		FormalityThreshold: st.FormalityThreshold,
+		ClPenaltyProfile:   st.ClPenaltyProfile,
+		StrictMode:         st.StrictMode,
+		DecayProfiles:      st.DecayProfiles,
+		EvidenceWeighting:  st.EvidenceWeighting,
+		UpdatedAt:          sql.NullTime{Time: time.Now().UTC(), Valid: true},
+	})
+}
+
+// parseValidUntil accepts RFC 3339 or a bare date; anything else means the
+// evidence does not expire.
+func parseValidUntil(validUntil string) sql.NullTime {
+	if validUntil == "" {
+		return sql.NullTime{}
+	}
+	t, err := time.Parse(time.RFC3339, validUntil)
+	if err != nil {
+		t, err = time.Parse("2006-01-02", validUntil)
+	}
+	if err != nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
 func toNullString(s string) sql.NullString {
 	if s == "" {
 		return sql.NullString{}
@@ -104,6 +104,22 @@ func TestStore_EvidenceCRUD(t *testing.T) {
 	if len(withCarrier) != 1 {
 		t.Errorf("Expected 1 evidence with carrier, got %d", len(withCarrier))
 	}
+
+	all, err := store.ListAllEvidence(ctx)
+	if err != nil {
+		t.Fatalf("ListAllEvidence failed: %v", err)
+	}
+	if len(all) != 1 || all[0].ID != "e1" {
+		t.Errorf("Expected [e1], got %v", all)
+	}
+
+	holons, err := store.ListHolons(ctx)
+	if err != nil {
+		t.Fatalf("ListHolons failed: %v", err)
+	}
+	if len(holons) != 1 || holons[0].ID != "h1" {
+		t.Errorf("Expected [h1], got %v", holons)
+	}
 }
 
 func TestStore_RelationsCRUD(t *testing.T) {
@@ -256,3 +272,60 @@ func TestStore_FileCleanup(t *testing.T) {
 		t.Error("Database file should exist after close")
 	}
 }
+
+func TestStore_Contexts(t *testing.T) {
+	tempDir := t.TempDir()
+	store, err := NewStore(filepath.Join(tempDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+
+	active, err := store.GetActiveContextID(ctx)
+	if err != nil {
+		t.Fatalf("GetActiveContextID failed: %v", err)
+	}
+	if active != DefaultContextID {
+		t.Errorf("Expected default context active, got %s", active)
+	}
+
+	if err := store.CreateContext(ctx, "payments", "Payments service"); err != nil {
+		t.Fatalf("CreateContext failed: %v", err)
+	}
+	if err := store.CreateContext(ctx, "payments", ""); err == nil {
+		t.Error("Expected error creating duplicate context")
+	}
+
+	c, err := store.GetContext(ctx, "payments")
+	if err != nil {
+		t.Fatalf("GetContext failed: %v", err)
+	}
+	if c.Description.String != "Payments service" || c.IsActive != 0 {
+		t.Errorf("Unexpected context: %+v", c)
+	}
+
+	if err := store.SetActiveContext(ctx, "payments"); err != nil {
+		t.Fatalf("SetActiveContext failed: %v", err)
+	}
+	active, _ = store.GetActiveContextID(ctx)
+	if active != "payments" {
+		t.Errorf("Expected payments active, got %s", active)
+	}
+
+	contexts, err := store.ListContexts(ctx)
+	if err != nil {
+		t.Fatalf("ListContexts failed: %v", err)
+	}
+	if len(contexts) != 2 {
+		t.Fatalf("Expected 2 contexts, got %d", len(contexts))
+	}
+	activeCount := 0
+	for _, c := range contexts {
+		activeCount += int(c.IsActive)
+	}
+	if activeCount != 1 {
+		t.Errorf("Expected exactly one active context, got %d", activeCount)
+	}
+}
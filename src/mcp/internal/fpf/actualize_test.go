@@ -57,7 +57,7 @@ func TestActualize_GitReconciliation(t *testing.T) {
 
 	fsm := &fpf.FSM{
 		State: fpf.State{Phase: fpf.PhaseIdle},
-		DB:    database.GetRawDB(),
+		DB:    database,
 	}
 	tools := fpf.NewTools(fsm, tempDir, database)
 
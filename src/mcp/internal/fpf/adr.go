@@ -0,0 +1,427 @@
+package fpf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// ADR formats written by ExportADR.
+const (
+	ADRFormatMADR   = "madr"
+	ADRFormatNygard = "nygard"
+)
+
+var (
+	adrFileRe   = regexp.MustCompile(`^(\d{4})-.*\.md$`)
+	adrMarkerRe = regexp.MustCompile(`<!-- quint-drr: (\S+) -->`)
+)
+
+// ADRExportReport lists the files an export created, updated or left alone.
+type ADRExportReport struct {
+	Dir       string
+	Created   []string
+	Updated   []string
+	Unchanged []string
+}
+
+func (r ADRExportReport) String() string {
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("Exported %d DRRs to %s\n", len(r.Created)+len(r.Updated)+len(r.Unchanged), r.Dir))
+	out.WriteString(fmt.Sprintf("- Created: %d\n", len(r.Created)))
+	for _, f := range r.Created {
+		out.WriteString(fmt.Sprintf("  - %s\n", f))
+	}
+	out.WriteString(fmt.Sprintf("- Updated: %d\n", len(r.Updated)))
+	for _, f := range r.Updated {
+		out.WriteString(fmt.Sprintf("  - %s\n", f))
+	}
+	out.WriteString(fmt.Sprintf("- Unchanged: %d\n", len(r.Unchanged)))
+	return out.String()
+}
+
+// adrRecord is a DRR with everything an ADR needs to describe it.
+type adrRecord struct {
+	drr        db.Holon
+	number     int
+	file       string
+	status     string
+	created    time.Time
+	winner     string
+	rejected   []string
+	supersedes []string
+	superseded []string
+	titles     map[string]string // option titles by holon ID
+	sections   map[string]string
+}
+
+// ExportADR writes each DRR to outDir as an ADR in the given format. A marker
+// in each file names its DRR, so a re-export rewrites the same files.
+func (t *Tools) ExportADR(format, outDir string) (ADRExportReport, error) {
+	defer t.RecordWork("ExportADR", time.Now())
+	report := ADRExportReport{Dir: outDir}
+	if t.DB == nil {
+		return report, fmt.Errorf("DB not initialized")
+	}
+	if format != ADRFormatMADR && format != ADRFormatNygard {
+		return report, fmt.Errorf("invalid ADR format %q: use madr or nygard", format)
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return report, err
+	}
+
+	existing, next, err := scanADRDir(outDir)
+	if err != nil {
+		return report, err
+	}
+	records, err := t.adrRecords(context.Background())
+	if err != nil {
+		return report, err
+	}
+
+	// Number every record first, so supersedes links can point at files
+	// that are only written later in this run.
+	byID := map[string]*adrRecord{}
+	for _, r := range records {
+		if file, ok := existing[r.drr.ID]; ok {
+			r.file = file
+			r.number, _ = strconv.Atoi(file[:4])
+		} else {
+			r.number = next
+			r.file = fmt.Sprintf("%04d-%s.md", next, t.Slugify(r.drr.Title))
+			next++
+		}
+		byID[r.drr.ID] = r
+	}
+
+	for _, r := range records {
+		var content string
+		if format == ADRFormatMADR {
+			content = r.madr(byID)
+		} else {
+			content = r.nygard(byID)
+		}
+		content += fmt.Sprintf("\n<!-- quint-drr: %s -->\n", r.drr.ID)
+
+		path := filepath.Join(outDir, r.file)
+		prev, err := os.ReadFile(path)
+		switch {
+		case err == nil && string(prev) == content:
+			report.Unchanged = append(report.Unchanged, r.file)
+			continue
+		case err == nil:
+			report.Updated = append(report.Updated, r.file)
+		case os.IsNotExist(err):
+			report.Created = append(report.Created, r.file)
+		default:
+			return report, err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// scanADRDir maps the DRR IDs already exported to dir to their files and
+// returns the next free ADR number.
+func scanADRDir(dir string) (map[string]string, int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	existing := map[string]string{}
+	next := 1
+	for _, e := range entries {
+		m := adrFileRe.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		if n, _ := strconv.Atoi(m[1]); n >= next {
+			next = n + 1
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, 0, err
+		}
+		if marker := adrMarkerRe.FindStringSubmatch(string(data)); marker != nil {
+			existing[marker[1]] = e.Name()
+		}
+	}
+	return existing, next, nil
+}
+
+// adrRecords collects every DRR in decision order.
+func (t *Tools) adrRecords(ctx context.Context) ([]*adrRecord, error) {
+	holons, err := t.DB.ListHolons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	created, err := t.drrCreated()
+	if err != nil {
+		return nil, err
+	}
+	var records []*adrRecord
+	for _, h := range holons {
+		if h.Type != "DRR" {
+			continue
+		}
+		r := &adrRecord{drr: h, status: drrStatus(h), sections: markdownSections(h.Content)}
+		// created_at is reset by rebuild-db; the DRR file keeps the decision date.
+		if c, ok := created[h.ID]; ok {
+			r.created = c
+		} else if h.CreatedAt.Valid {
+			r.created = h.CreatedAt.Time
+		}
+		if r.winner, err = t.relationTarget(ctx, h.ID, "selects"); err != nil {
+			return nil, err
+		}
+		if r.rejected, err = t.relationTargets(ctx, h.ID, "rejects"); err != nil {
+			return nil, err
+		}
+		if r.supersedes, err = t.relationTargets(ctx, h.ID, "supersedes"); err != nil {
+			return nil, err
+		}
+		r.superseded = t.supersededBy(ctx, h.ID)
+		r.titles = map[string]string{}
+		for _, id := range append([]string{r.winner}, r.rejected...) {
+			if id != "" {
+				r.titles[id] = t.getHolonTitle(id)
+			}
+		}
+		records = append(records, r)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if !a.created.Equal(b.created) {
+			return a.created.Before(b.created)
+		}
+		return a.drr.ID < b.drr.ID
+	})
+	return records, nil
+}
+
+// drrCreated reads the created date from the frontmatter of every DRR file,
+// keyed by DRR ID.
+func (t *Tools) drrCreated() (map[string]time.Time, error) {
+	contexts, err := t.projectedContexts()
+	if err != nil {
+		return nil, err
+	}
+	created := map[string]time.Time{}
+	for _, contextID := range contexts {
+		paths, err := filepath.Glob(filepath.Join(t.contextDir(contextID), "decisions", "*.md"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			fm, body, ok := parseFrontmatter(string(data))
+			if !ok {
+				continue
+			}
+			fields := parseFrontmatterFields(fm)
+			id := fields["id"]
+			if id == "" {
+				id = t.Slugify(extractMarkdownHeading(body))
+			}
+			if c, err := time.Parse(time.RFC3339, fields["created"]); err == nil {
+				created[id] = c
+			}
+		}
+	}
+	return created, nil
+}
+
+func (t *Tools) relationTargets(ctx context.Context, sourceID, relationType string) ([]string, error) {
+	rels, err := t.DB.GetRelationsBySource(ctx, sourceID, relationType)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, r := range rels {
+		ids = append(ids, r.TargetID)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (t *Tools) relationTarget(ctx context.Context, sourceID, relationType string) (string, error) {
+	ids, err := t.relationTargets(ctx, sourceID, relationType)
+	if err != nil || len(ids) == 0 {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+// markdownSections splits a DRR body into its "## " sections by heading.
+func markdownSections(body string) map[string]string {
+	sections := map[string]string{}
+	var heading string
+	var lines []string
+	flush := func() {
+		if heading != "" {
+			sections[heading] = strings.TrimSpace(strings.Join(lines, "\n"))
+		}
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			flush()
+			heading, lines = strings.TrimSpace(strings.TrimPrefix(line, "## ")), nil
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return sections
+}
+
+// decisionText is the Decision section without the lines quint_decide adds
+// for the selected option and superseded DRRs, which the ADR shows itself.
+func (r *adrRecord) decisionText() string {
+	var kept []string
+	for _, line := range strings.Split(r.sections["Decision"], "\n") {
+		if strings.HasPrefix(line, "**Selected Option:**") || strings.HasPrefix(line, "**Supersedes:**") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+func (r *adrRecord) title() string {
+	return fmt.Sprintf("%d. %s", r.number, r.drr.Title)
+}
+
+// adrLink is a markdown link to the ADR of drrID, or its bare ID when that DRR
+// is not exported.
+func adrLink(byID map[string]*adrRecord, drrID string) string {
+	if r, ok := byID[drrID]; ok {
+		return fmt.Sprintf("[%s](%s)", r.title(), r.file)
+	}
+	return drrID
+}
+
+func adrLinks(byID map[string]*adrRecord, ids []string) string {
+	var out []string
+	for _, id := range ids {
+		out = append(out, adrLink(byID, id))
+	}
+	return strings.Join(out, ", ")
+}
+
+// optionList lists the winner first, then each rejected alternative.
+func (r *adrRecord) optionList() string {
+	var out strings.Builder
+	if r.winner != "" {
+		out.WriteString(fmt.Sprintf("* %s (chosen)\n", r.option(r.winner)))
+	}
+	for _, id := range r.rejected {
+		out.WriteString(fmt.Sprintf("* %s\n", r.option(id)))
+	}
+	return out.String()
+}
+
+// option names a considered option by title and holon ID.
+func (r *adrRecord) option(id string) string {
+	if title := r.titles[id]; title != "" && title != id {
+		return fmt.Sprintf("%s (`%s`)", title, id)
+	}
+	return fmt.Sprintf("`%s`", id)
+}
+
+func (r *adrRecord) madr(byID map[string]*adrRecord) string {
+	// MADR spells a replaced decision "superseded by ADR-0005".
+	status := r.status
+	if len(r.superseded) > 0 {
+		var by []string
+		for _, id := range r.superseded {
+			if next, ok := byID[id]; ok {
+				by = append(by, fmt.Sprintf("ADR-%04d", next.number))
+			} else {
+				by = append(by, id)
+			}
+		}
+		status = "superseded by " + strings.Join(by, ", ")
+	}
+	var out strings.Builder
+	out.WriteString("---\n")
+	out.WriteString(fmt.Sprintf("status: %s\n", status))
+	if !r.created.IsZero() {
+		out.WriteString(fmt.Sprintf("date: %s\n", r.created.Format("2006-01-02")))
+	}
+	out.WriteString("---\n\n")
+	out.WriteString(fmt.Sprintf("# %s\n\n", r.drr.Title))
+	if len(r.supersedes) > 0 {
+		out.WriteString(fmt.Sprintf("Supersedes %s\n\n", adrLinks(byID, r.supersedes)))
+	}
+	if len(r.superseded) > 0 {
+		out.WriteString(fmt.Sprintf("Superseded by %s\n\n", adrLinks(byID, r.superseded)))
+	}
+	out.WriteString(fmt.Sprintf("## Context and Problem Statement\n\n%s\n\n", orNone(r.sections["Context"])))
+	if options := r.optionList(); options != "" {
+		out.WriteString("## Considered Options\n\n" + options + "\n")
+	}
+	out.WriteString("## Decision Outcome\n\n")
+	if r.winner != "" {
+		out.WriteString(fmt.Sprintf("Chosen option: %s.\n\n", r.option(r.winner)))
+	}
+	if d := r.decisionText(); d != "" {
+		out.WriteString(d + "\n\n")
+	}
+	if rationale := r.sections["Rationale"]; rationale != "" {
+		out.WriteString(fmt.Sprintf("### Rationale\n\n%s\n\n", rationale))
+	}
+	out.WriteString(fmt.Sprintf("### Consequences\n\n%s\n", orNone(r.sections["Consequences"])))
+	return out.String()
+}
+
+func (r *adrRecord) nygard(byID map[string]*adrRecord) string {
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("# %s\n\n", r.title()))
+	if !r.created.IsZero() {
+		out.WriteString(fmt.Sprintf("Date: %s\n\n", r.created.Format("2006-01-02")))
+	}
+	out.WriteString("## Status\n\n")
+	out.WriteString(strings.ToUpper(r.status[:1]) + r.status[1:] + "\n")
+	for _, id := range r.supersedes {
+		out.WriteString(fmt.Sprintf("\nSupersedes %s\n", adrLink(byID, id)))
+	}
+	for _, id := range r.superseded {
+		out.WriteString(fmt.Sprintf("\nSuperseded by %s\n", adrLink(byID, id)))
+	}
+	out.WriteString(fmt.Sprintf("\n## Context\n\n%s\n\n", orNone(r.sections["Context"])))
+	out.WriteString("## Decision\n\n")
+	if r.winner != "" {
+		out.WriteString(fmt.Sprintf("We will go with %s.\n\n", r.option(r.winner)))
+	}
+	if d := r.decisionText(); d != "" {
+		out.WriteString(d + "\n\n")
+	}
+	if rationale := r.sections["Rationale"]; rationale != "" {
+		out.WriteString(rationale + "\n\n")
+	}
+	if options := r.optionList(); options != "" {
+		out.WriteString("Considered options:\n\n" + options + "\n")
+	}
+	out.WriteString(fmt.Sprintf("## Consequences\n\n%s\n", orNone(r.sections["Consequences"])))
+	return out.String()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "_Not recorded._"
+	}
+	return s
+}
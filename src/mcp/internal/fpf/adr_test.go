@@ -0,0 +1,117 @@
+package fpf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportADR(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	for _, title := range []string{"Use Redis", "Use Valkey", "Use LRU"} {
+		if _, err := tools.ProposeHypothesis(title, title+" cache", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+			t.Fatalf("ProposeHypothesis failed: %v", err)
+		}
+		if _, err := tools.VerifyHypothesis(tools.Slugify(title), "{}", "PASS"); err != nil {
+			t.Fatalf("VerifyHypothesis failed: %v", err)
+		}
+	}
+	if _, err := tools.FinalizeDecision("Cache v1", "use-redis", []string{"use-lru"}, "We need a cache", "Go with Redis", "Fastest", "Ops cost", "", "", nil); err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+	if _, err := tools.FinalizeDecision("Cache v2", "use-valkey", []string{"use-redis"}, "Licensing changed", "Move to Valkey", "Open license", "Migration", "", "", []string{"cache-v1"}); err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+
+	out := filepath.Join(t.TempDir(), "adr")
+	if err := os.MkdirAll(out, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(out, "0001-record-architecture-decisions.md"), []byte("# 1. Record architecture decisions\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := tools.ExportADR(ADRFormatMADR, out)
+	if err != nil {
+		t.Fatalf("ExportADR failed: %v", err)
+	}
+	if len(report.Created) != 2 || report.Created[0] != "0002-cache-v1.md" || report.Created[1] != "0003-cache-v2.md" {
+		t.Fatalf("Expected ADRs numbered after the existing one, got %+v", report)
+	}
+	v1, _ := os.ReadFile(filepath.Join(out, "0002-cache-v1.md"))
+	for _, want := range []string{"status: superseded by ADR-0003", "## Considered Options", "* Use Redis (`use-redis`) (chosen)", "* Use LRU (`use-lru`)", "We need a cache", "Go with Redis", "[3. Cache v2](0003-cache-v2.md)"} {
+		if !strings.Contains(string(v1), want) {
+			t.Errorf("Expected the MADR to contain %q, got:\n%s", want, v1)
+		}
+	}
+	if strings.Contains(string(v1), "**Selected Option:**") {
+		t.Errorf("Expected quint's own decision lines to be left out, got:\n%s", v1)
+	}
+
+	report, err = tools.ExportADR(ADRFormatMADR, out)
+	if err != nil {
+		t.Fatalf("ExportADR failed: %v", err)
+	}
+	if len(report.Created) != 0 || len(report.Updated) != 0 || len(report.Unchanged) != 2 {
+		t.Errorf("Expected re-running the export to change nothing, got %+v", report)
+	}
+
+	report, err = tools.ExportADR(ADRFormatNygard, out)
+	if err != nil {
+		t.Fatalf("ExportADR failed: %v", err)
+	}
+	if len(report.Updated) != 2 {
+		t.Errorf("Expected the Nygard export to rewrite both files in place, got %+v", report)
+	}
+	v2, _ := os.ReadFile(filepath.Join(out, "0003-cache-v2.md"))
+	for _, want := range []string{"# 3. Cache v2", "## Status\n\nAccepted", "Supersedes [2. Cache v1](0002-cache-v1.md)", "We will go with Use Valkey (`use-valkey`)."} {
+		if !strings.Contains(string(v2), want) {
+			t.Errorf("Expected the Nygard ADR to contain %q, got:\n%s", want, v2)
+		}
+	}
+	entries, _ := os.ReadDir(out)
+	if len(entries) != 3 {
+		t.Errorf("Expected no extra files, got %d", len(entries))
+	}
+
+	if _, err := tools.ExportADR("y-statement", out); err == nil {
+		t.Error("Expected an unknown format to fail")
+	}
+}
+
+func TestExportADR_DateFromDRRFile(t *testing.T) {
+	_, root := setupProjectedProject(t)
+
+	// A rebuilt database dates every DRR to the rebuild; the file keeps the
+	// decision date.
+	paths, _ := filepath.Glob(filepath.Join(root, ".quint", "decisions", "DRR-*.md"))
+	if len(paths) != 1 {
+		t.Fatalf("Expected one DRR file, got %v", paths)
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	fm, body, _ := parseFrontmatter(string(data))
+	fields := parseFrontmatterFields(fm)
+	fields["created"] = "2024-03-05T10:00:00Z"
+	if err := WriteWithHash(paths[0], fields, body); err != nil {
+		t.Fatal(err)
+	}
+
+	rebuilt := freshTools(t, root)
+	if report, err := rebuilt.RebuildDB(false); err != nil || !report.Applied {
+		t.Fatalf("RebuildDB failed: %v\n%s", err, report)
+	}
+
+	out := t.TempDir()
+	if _, err := rebuilt.ExportADR(ADRFormatMADR, out); err != nil {
+		t.Fatalf("ExportADR failed: %v", err)
+	}
+	adr, _ := os.ReadFile(filepath.Join(out, "0001-cache-choice.md"))
+	if !strings.Contains(string(adr), "date: 2024-03-05\n") {
+		t.Errorf("Expected the ADR to carry the DRR's created date, got:\n%s", adr)
+	}
+}
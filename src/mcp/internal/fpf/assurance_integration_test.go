@@ -34,7 +34,7 @@ func setupAssuranceTestEnv(t *testing.T) (*fpf.FSM, *db.Store, string) {
 
 	fsm := &fpf.FSM{
 		State: fpf.State{Phase: fpf.PhaseDecision},
-		DB:    rawDB,
+		DB:    database,
 	}
 
 	return fsm, database, tempDir
@@ -147,6 +147,46 @@ func TestAssuranceGuard_RespectsConfigurableThreshold(t *testing.T) {
 	}
 }
 
+func TestAssuranceGuard_EnforcesFormalityAndScope(t *testing.T) {
+	fsm, database, tempDir := setupAssuranceTestEnv(t)
+	rawDB := database.GetRawDB()
+
+	l2Dir := filepath.Join(tempDir, ".quint", "knowledge", "L2")
+	os.MkdirAll(l2Dir, 0755)
+	l2File := filepath.Join(l2Dir, "fgr-holon.md")
+	os.WriteFile(l2File, []byte("F-G-R hypothesis"), 0644)
+
+	// High R, F2, but its only part holds in a different environment.
+	_, _ = rawDB.Exec(`INSERT INTO holons (id, type, layer, title, content, context_id, formality, claim_scope) VALUES
+		('fgr-holon', 'hypothesis', 'L2', 'FGR', 'Content', 'ctx', 2, '{"env":["prod"]}'),
+		('fgr-part', 'hypothesis', 'L2', 'Part', 'Content', 'ctx', 5, '{"env":["dev"]}')`)
+	future := time.Now().Add(24 * time.Hour)
+	_, _ = rawDB.Exec("INSERT INTO evidence (id, holon_id, type, content, verdict, valid_until) VALUES ('e1', 'fgr-holon', 'test', 'Pass', 'pass', ?), ('e2', 'fgr-part', 'test', 'Pass', 'pass', ?)", future, future)
+	_, _ = rawDB.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('fgr-part', 'fgr-holon', 'componentOf', 3)")
+
+	ra := fpf.RoleAssignment{Role: fpf.RoleDecider, SessionID: "test", Context: "test"}
+	ev := &fpf.EvidenceStub{URI: l2File, Type: "hypothesis", HolonID: "fgr-holon"}
+
+	ok, msg := fsm.CanTransition(fpf.PhaseOperation, ra, ev)
+	if ok || !strings.Contains(msg, "Claim scope is empty") {
+		t.Errorf("Expected denial for empty claim scope, got ok=%v msg=%s", ok, msg)
+	}
+
+	// Align the part's scope; now only formality can block.
+	_, _ = rawDB.Exec(`UPDATE holons SET claim_scope = '{"env":["prod","dev"]}' WHERE id = 'fgr-part'`)
+	fsm.State.FormalityThreshold = 3
+
+	ok, msg = fsm.CanTransition(fpf.PhaseOperation, ra, ev)
+	if ok || !strings.Contains(msg, "Formality (F2)") {
+		t.Errorf("Expected denial for formality below F3, got ok=%v msg=%s", ok, msg)
+	}
+
+	fsm.State.FormalityThreshold = 2
+	if ok, msg = fsm.CanTransition(fpf.PhaseOperation, ra, ev); !ok {
+		t.Errorf("Expected transition to be ALLOWED at F2 threshold, got: %s", msg)
+	}
+}
+
 func TestEvidenceDecay_PenalizesExpired(t *testing.T) {
 	fsm, database, _ := setupAssuranceTestEnv(t)
 	rawDB := database.GetRawDB()
@@ -178,7 +218,7 @@ func TestEvidenceDecay_PenalizesExpired(t *testing.T) {
 	// Check that decay was noted in factors
 	hasDecayFactor := false
 	for _, f := range report.Factors {
-		if f == "Evidence expired (Decay applied)" {
+		if f == "Evidence e1 expired: -0.90 (step decay)" {
 			hasDecayFactor = true
 			break
 		}
@@ -205,7 +245,7 @@ func TestAuditVisualization_ReturnsTree(t *testing.T) {
 	_, _ = rawDB.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('child', 'parent', 'componentOf', 3)")
 
 	// Create tools and call VisualizeAudit
-	fsm, _ := fpf.LoadState("default", rawDB)
+	fsm, _ := fpf.LoadState("default", database)
 	tools := fpf.NewTools(fsm, tempDir, database)
 
 	tree, err := tools.VisualizeAudit("parent")
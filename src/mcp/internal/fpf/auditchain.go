@@ -0,0 +1,181 @@
+package fpf
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// Audit signing keys live next to quint.db. audit.pub is meant to be
+// committed so anyone can verify; audit.key must stay out of version control.
+const (
+	AuditKeyFile       = "audit.key"
+	AuditPublicKeyFile = "audit.pub"
+)
+
+// AuditVerifyReport is the outcome of `quint-code audit verify`.
+type AuditVerifyReport struct {
+	db.AuditChainReport
+	// KeyFound is true when signatures were checked against audit.pub (or
+	// the public half of audit.key).
+	KeyFound bool
+}
+
+// OK reports whether the chain verified without a break.
+func (r *AuditVerifyReport) OK() bool {
+	return r.Break == nil
+}
+
+// GenerateAuditKey creates the project's ed25519 audit key pair. From then on
+// every audit entry is signed, and verification requires signatures on all
+// entries after the first signed one.
+func (t *Tools) GenerateAuditKey() (string, error) {
+	keyPath := filepath.Join(t.GetFPFDir(), AuditKeyFile)
+	pubPath := filepath.Join(t.GetFPFDir(), AuditPublicKeyFile)
+	for _, p := range []string{keyPath, pubPath} {
+		if _, err := os.Stat(p); err == nil {
+			return "", fmt.Errorf("%s already exists; remove it first to rotate the key", p)
+		}
+	}
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0644); err != nil {
+		return "", err
+	}
+
+	fingerprint := hex.EncodeToString(pub[:8])
+	if t.DB != nil {
+		t.DB.SetAuditKey(key)
+		// The first signed entry: everything after it must be signed too.
+		t.AuditLog("quint_audit_key", "generate_key", t.actor(), "", "SUCCESS", nil, "ed25519 "+fingerprint)
+	}
+	return fmt.Sprintf("Audit key %s written to %s (keep it out of version control)\nPublic key written to %s", fingerprint, keyPath, pubPath), nil
+}
+
+// loadAuditKey reads .quint/audit.key, returning nil if the project has none.
+func loadAuditKey(fpfDir string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(filepath.Join(fpfDir, AuditKeyFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", AuditKeyFile)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", AuditKeyFile, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an ed25519 key", AuditKeyFile)
+	}
+	return key, nil
+}
+
+// loadAuditPublicKey reads .quint/audit.pub, falling back to the public half
+// of audit.key. It returns nil if the project has neither.
+func loadAuditPublicKey(fpfDir string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(filepath.Join(fpfDir, AuditPublicKeyFile))
+	if os.IsNotExist(err) {
+		key, err := loadAuditKey(fpfDir)
+		if key == nil || err != nil {
+			return nil, err
+		}
+		return key.Public().(ed25519.PublicKey), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", AuditPublicKeyFile)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", AuditPublicKeyFile, err)
+	}
+	pub, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an ed25519 key", AuditPublicKeyFile)
+	}
+	return pub, nil
+}
+
+// VerifyAudit walks the whole audit log and reports the first entry at which
+// the hash chain or a signature does not hold.
+func (t *Tools) VerifyAudit() (*AuditVerifyReport, error) {
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+	pub, err := loadAuditPublicKey(t.GetFPFDir())
+	if err != nil {
+		return nil, err
+	}
+	entries, err := t.DB.ListAuditLog(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &AuditVerifyReport{AuditChainReport: db.VerifyAuditChain(entries, pub), KeyFound: pub != nil}, nil
+}
+
+func (r *AuditVerifyReport) String() string {
+	var out strings.Builder
+	out.WriteString("## Audit Log Verification\n\n")
+
+	if r.Break != nil {
+		at := fmt.Sprintf("entry #%d", r.Break.Seq)
+		if r.Break.Seq == 0 {
+			at = "an unchained entry"
+		}
+		out.WriteString(fmt.Sprintf("### BROKEN at %s (%s)\n\n%s.\n\n", at, r.Break.ID, r.Break.Reason))
+		out.WriteString(fmt.Sprintf("Entries before the break verified: %d.\n", r.Checked))
+		return out.String()
+	}
+
+	out.WriteString("Chain intact.\n\n")
+	out.WriteString(fmt.Sprintf("- Entries verified: %d\n", r.Checked))
+	if r.Checked > 0 {
+		out.WriteString(fmt.Sprintf("- Head: #%d %s\n", r.HeadSeq, r.HeadHash))
+	}
+
+	switch {
+	case r.Signed == 0:
+		out.WriteString("- Signatures: none (run `quint-code audit keygen` to sign new entries)\n")
+	case r.KeyFound:
+		out.WriteString(fmt.Sprintf("- Signatures: %d verified\n", r.Signed))
+	default:
+		out.WriteString(fmt.Sprintf("- Signatures: %d present but NOT checked (no %s)\n", r.Signed, AuditPublicKeyFile))
+	}
+	if r.Legacy > 0 {
+		out.WriteString(fmt.Sprintf("- Entries written before chaining (not verifiable): %d\n", r.Legacy))
+	}
+	out.WriteString("\nRecord the head hash outside the database (e.g. in a commit or CI log) to detect later truncation.\n")
+	return out.String()
+}
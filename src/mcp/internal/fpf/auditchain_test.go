@@ -0,0 +1,67 @@
+package fpf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyAudit(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	raw := sqlDB(t, tools)
+
+	// An entry from before chaining existed: counted, not verified.
+	if _, err := raw.Exec(`INSERT INTO audit_log (id, timestamp, tool_name, operation, actor, result, context_id)
+		VALUES ('legacy', '2020-01-01 00:00:00', 'quint_propose', 'create_hypothesis', 'agent', 'SUCCESS', 'default')`); err != nil {
+		t.Fatalf("Failed to insert legacy entry: %v", err)
+	}
+
+	if _, err := tools.ProposeHypothesis("Use Redis", "Redis cache", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	// A rolled-back call must not leave a gap in the chain.
+	if _, err := tools.FinalizeDecision("Cache Choice", "use-redis", nil, "Context", "Decision", "Rationale", "Consequences", "", "", nil); err == nil {
+		t.Fatal("Expected FinalizeDecision to fail for an unverified winner")
+	}
+
+	report, err := tools.VerifyAudit()
+	if err != nil {
+		t.Fatalf("VerifyAudit failed: %v", err)
+	}
+	if !report.OK() || report.Legacy != 1 || report.Checked < 2 || report.Signed != 0 {
+		t.Fatalf("Expected an intact unsigned chain, got %+v (break %+v)", report, report.Break)
+	}
+
+	if _, err := tools.GenerateAuditKey(); err != nil {
+		t.Fatalf("GenerateAuditKey failed: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(tempDir, ".quint", AuditKeyFile))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected audit.key with mode 0600, got %v (%v)", info, err)
+	}
+	if _, err := tools.GenerateAuditKey(); err == nil {
+		t.Error("Expected GenerateAuditKey to refuse to overwrite an existing key")
+	}
+
+	// A fresh Tools picks the key up from .quint/.
+	reopened := NewTools(tools.FSM, tempDir, tools.DB)
+	if _, err := reopened.ProposeHypothesis("Use LRU", "LRU cache", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	report, _ = tools.VerifyAudit()
+	if !report.OK() || !report.KeyFound || report.Signed < 2 {
+		t.Fatalf("Expected signed entries to verify, got %+v (break %+v)", report, report.Break)
+	}
+	if !strings.Contains(report.String(), "Chain intact.") {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+
+	if _, err := raw.Exec(`UPDATE audit_log SET actor = 'someone-else' WHERE seq = 2`); err != nil {
+		t.Fatalf("Failed to tamper with the audit log: %v", err)
+	}
+	report, _ = tools.VerifyAudit()
+	if report.OK() || report.Break.Seq != 2 || !strings.Contains(report.String(), "BROKEN at entry #2") {
+		t.Errorf("Expected the edit to break the chain at #2, got:\n%s", report)
+	}
+}
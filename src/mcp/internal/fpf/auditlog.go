@@ -0,0 +1,217 @@
+package fpf
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// Audit log formats accepted by QueryAuditLog.
+const (
+	AuditFormatTable = "table"
+	AuditFormatJSONL = "jsonl"
+	AuditFormatCSV   = "csv"
+)
+
+// AuditLogQuery filters the audit log for quint_audit_log and `quint-code log`.
+// Since and Until accept a date (2006-01-02), an RFC 3339 time or a duration
+// back from now (90m, 24h, 7d). A date given as Until includes that whole day.
+type AuditLogQuery struct {
+	Tool      string
+	Operation string
+	Actor     string
+	Target    string
+	Result    string
+	Context   string
+	Since     string
+	Until     string
+	Limit     int
+	Format    string
+}
+
+// auditRecord is one exported audit entry. Field names match the audit_log
+// columns so exports can be joined back to the database.
+type auditRecord struct {
+	Seq       int64  `json:"seq,omitempty"`
+	ID        string `json:"id"`
+	Timestamp string `json:"timestamp"`
+	Tool      string `json:"tool_name"`
+	Operation string `json:"operation"`
+	Actor     string `json:"actor"`
+	TargetID  string `json:"target_id,omitempty"`
+	InputHash string `json:"input_hash,omitempty"`
+	Result    string `json:"result"`
+	Details   string `json:"details,omitempty"`
+	ContextID string `json:"context_id"`
+	PrevHash  string `json:"prev_hash,omitempty"`
+	EntryHash string `json:"entry_hash,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+var auditCSVHeader = []string{
+	"seq", "id", "timestamp", "tool_name", "operation", "actor", "target_id", "input_hash",
+	"result", "details", "context_id", "prev_hash", "entry_hash", "signature",
+}
+
+func newAuditRecord(e db.AuditLog) auditRecord {
+	r := auditRecord{
+		Seq:       e.Seq.Int64,
+		ID:        e.ID,
+		Tool:      e.ToolName,
+		Operation: e.Operation,
+		Actor:     e.Actor,
+		TargetID:  e.TargetID.String,
+		InputHash: e.InputHash.String,
+		Result:    e.Result,
+		Details:   e.Details.String,
+		ContextID: e.ContextID,
+		PrevHash:  e.PrevHash.String,
+		EntryHash: e.EntryHash.String,
+		Signature: e.Signature.String,
+	}
+	if e.Timestamp.Valid {
+		r.Timestamp = e.Timestamp.Time.UTC().Format(time.RFC3339Nano)
+	}
+	return r
+}
+
+func (r auditRecord) csvRow() []string {
+	seq := ""
+	if r.Seq > 0 {
+		seq = strconv.FormatInt(r.Seq, 10)
+	}
+	return []string{
+		seq, r.ID, r.Timestamp, r.Tool, r.Operation, r.Actor, r.TargetID, r.InputHash,
+		r.Result, r.Details, r.ContextID, r.PrevHash, r.EntryHash, r.Signature,
+	}
+}
+
+// QueryAuditLog renders the audit entries matching q, oldest first. Without a
+// context filter every context is searched. With a limit, only the most
+// recent matches are kept.
+func (t *Tools) QueryAuditLog(q AuditLogQuery) (string, error) {
+	defer t.RecordWork("QueryAuditLog", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	format := strings.ToLower(q.Format)
+	if format == "" {
+		format = AuditFormatTable
+	}
+	if format != AuditFormatTable && format != AuditFormatJSONL && format != AuditFormatCSV {
+		return "", fmt.Errorf("unknown format %q: use table, jsonl or csv", q.Format)
+	}
+
+	now := time.Now().UTC()
+	since, err := parseAuditTime("since", q.Since, now, false)
+	if err != nil {
+		return "", err
+	}
+	until, err := parseAuditTime("until", q.Until, now, true)
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := t.DB.FilterAuditLog(context.Background(), db.AuditLogFilter{
+		ToolName:  q.Tool,
+		Operation: q.Operation,
+		Actor:     q.Actor,
+		TargetID:  q.Target,
+		Result:    strings.ToUpper(q.Result),
+		ContextID: q.Context,
+		Since:     since,
+		Until:     until,
+		Limit:     int64(q.Limit),
+	})
+	if err != nil {
+		return "", err
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	switch format {
+	case AuditFormatJSONL:
+		var out bytes.Buffer
+		enc := json.NewEncoder(&out)
+		for _, e := range entries {
+			if err := enc.Encode(newAuditRecord(e)); err != nil {
+				return "", err
+			}
+		}
+		return out.String(), nil
+	case AuditFormatCSV:
+		var out bytes.Buffer
+		w := csv.NewWriter(&out)
+		_ = w.Write(auditCSVHeader)
+		for _, e := range entries {
+			_ = w.Write(newAuditRecord(e).csvRow())
+		}
+		w.Flush()
+		return out.String(), w.Error()
+	}
+
+	var out strings.Builder
+	out.WriteString("## Audit Log\n\n")
+	if len(entries) == 0 {
+		out.WriteString("No matching audit entries.\n")
+		return out.String(), nil
+	}
+	out.WriteString("| # | When | Tool | Operation | Actor | Target | Result | Details |\n")
+	out.WriteString("|---|------|------|-----------|-------|--------|--------|---------|\n")
+	for _, e := range entries {
+		seq := "-"
+		if e.Seq.Valid {
+			seq = strconv.FormatInt(e.Seq.Int64, 10)
+		}
+		when := ""
+		if e.Timestamp.Valid {
+			when = e.Timestamp.Time.UTC().Format("2006-01-02 15:04:05")
+		}
+		out.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			seq, when, e.ToolName, e.Operation, orDash(e.Actor), orDash(e.TargetID.String), e.Result,
+			orDash(tableCell(e.Details.String))))
+	}
+	out.WriteString(fmt.Sprintf("\n%d entries.\n", len(entries)))
+	return out.String(), nil
+}
+
+// parseAuditTime reads a since/until bound. endOfDay moves a bare date to the
+// start of the next day, so an exclusive Until still covers the date given.
+func parseAuditTime(name, s string, now time.Time, endOfDay bool) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %q: use a date (2006-01-02), an RFC 3339 time or a duration such as 24h or 7d", name, s)
+}
+
+// tableCell keeps free text from breaking a markdown table row.
+func tableCell(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
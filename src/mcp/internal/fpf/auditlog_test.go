@@ -0,0 +1,78 @@
+package fpf
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryAuditLog(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if _, err := tools.ProposeHypothesis("Use Redis", "Redis cache", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis("Use LRU", "LRU cache", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.VerifyHypothesis("use-redis", "{}", "PASS"); err != nil {
+		t.Fatalf("VerifyHypothesis failed: %v", err)
+	}
+
+	table, err := tools.QueryAuditLog(AuditLogQuery{Target: "use-redis"})
+	if err != nil {
+		t.Fatalf("QueryAuditLog failed: %v", err)
+	}
+	if !strings.Contains(table, "| quint_propose |") || !strings.Contains(table, "| quint_verify |") || strings.Contains(table, "use-lru") {
+		t.Errorf("Expected only use-redis entries, got:\n%s", table)
+	}
+	if strings.Index(table, "quint_propose") > strings.Index(table, "quint_verify") {
+		t.Errorf("Expected entries oldest first, got:\n%s", table)
+	}
+
+	out, err := tools.QueryAuditLog(AuditLogQuery{Tool: "quint_propose", Result: "success", Since: "1h", Format: "jsonl"})
+	if err != nil {
+		t.Fatalf("QueryAuditLog failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 JSONL records, got %d:\n%s", len(lines), out)
+	}
+	var rec map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatalf("Invalid JSONL: %v", err)
+	}
+	if rec["target_id"] != "use-lru" || rec["result"] != "SUCCESS" || rec["entry_hash"] == "" {
+		t.Errorf("Unexpected record: %v", rec)
+	}
+
+	out, err = tools.QueryAuditLog(AuditLogQuery{Tool: "quint_propose", Limit: 1, Format: "csv"})
+	if err != nil {
+		t.Fatalf("QueryAuditLog failed: %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(rows) != 2 || rows[0][3] != "tool_name" || rows[1][6] != "use-lru" {
+		t.Errorf("Expected a header and the latest proposal, got %v", rows)
+	}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	if out, _ := tools.QueryAuditLog(AuditLogQuery{Since: tomorrow}); !strings.Contains(out, "No matching audit entries.") {
+		t.Errorf("Expected nothing after tomorrow, got:\n%s", out)
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	if out, _ := tools.QueryAuditLog(AuditLogQuery{Until: today, Format: "jsonl"}); strings.Count(out, "\n") < 3 {
+		t.Errorf("Expected until=today to include today's entries, got:\n%s", out)
+	}
+
+	if _, err := tools.QueryAuditLog(AuditLogQuery{Format: "xml"}); err == nil {
+		t.Error("Expected an unknown format to fail")
+	}
+	if _, err := tools.QueryAuditLog(AuditLogQuery{Since: "last week"}); err == nil {
+		t.Error("Expected an unparseable since to fail")
+	}
+}
@@ -0,0 +1,311 @@
+package fpf
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// CarrierPatterns splits a carrier_ref into repository paths or globs. Refs
+// may list several carriers separated by commas or semicolons; a "#..."
+// suffix (e.g. the "#sha256=" digest of ingested reports) is dropped.
+func CarrierPatterns(ref string) []string {
+	var patterns []string
+	for _, part := range strings.FieldsFunc(ref, func(r rune) bool { return r == ',' || r == ';' }) {
+		p := strings.TrimSpace(part)
+		if i := strings.Index(p, "#"); i >= 0 {
+			p = p[:i]
+		}
+		if p == "" || strings.Contains(p, "://") || !strings.ContainsAny(p, "/.*?[") {
+			continue
+		}
+		patterns = append(patterns, normalizeRepoPath(p))
+	}
+	return patterns
+}
+
+// carrierDigest returns the path and SHA-256 recorded in a single-carrier ref
+// such as "report.json#sha256=ab12...".
+func carrierDigest(ref string) (string, string, bool) {
+	p, digest, found := strings.Cut(ref, "#sha256=")
+	if !found || strings.ContainsAny(p, ",;") || digest == "" {
+		return "", "", false
+	}
+	return normalizeRepoPath(strings.TrimSpace(p)), digest, true
+}
+
+func normalizeRepoPath(p string) string {
+	p = path.Clean(filepath.ToSlash(p))
+	return strings.TrimPrefix(p, "./")
+}
+
+// MatchCarrier reports whether a changed file is covered by a carrier
+// pattern. Plain paths match the file itself or anything below a directory;
+// globs follow path.Match, with "**" matching any number of directories.
+func MatchCarrier(pattern, file string) bool {
+	pattern, file = normalizeRepoPath(pattern), normalizeRepoPath(file)
+	if !strings.ContainsAny(pattern, "*?[") {
+		return file == pattern || strings.HasPrefix(file, pattern+"/")
+	}
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(file)
+}
+
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class in %q", pattern)
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + class + "]")
+			i += end
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+	return regexp.Compile(re.String())
+}
+
+// ParseNameStatus extracts the changed paths from `git diff --name-status`
+// output. Renames and copies contribute both the old and the new path.
+func ParseNameStatus(diff string) []string {
+	var files []string
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 2 {
+			continue
+		}
+		files = append(files, fields[1:]...)
+	}
+	return files
+}
+
+// SuspectMatch is evidence whose carrier changed in a diff.
+type SuspectMatch struct {
+	EvidenceID string
+	HolonID    string
+	Paths      []string
+}
+
+// findSuspectEvidence intersects evidence carriers with changed files.
+// Carriers recorded with a digest are only suspect if the file no longer
+// has that digest, so evidence taken after the change stays trusted.
+func (t *Tools) findSuspectEvidence(changed []string) ([]SuspectMatch, error) {
+	evidence, err := t.DB.GetEvidenceWithCarrier(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []SuspectMatch
+	for _, e := range evidence {
+		var hits []string
+		for _, pattern := range CarrierPatterns(e.CarrierRef.String) {
+			for _, file := range changed {
+				if MatchCarrier(pattern, file) {
+					hits = append(hits, normalizeRepoPath(file))
+				}
+			}
+		}
+		if len(hits) == 0 {
+			continue
+		}
+		if p, digest, ok := carrierDigest(e.CarrierRef.String); ok {
+			if data, err := os.ReadFile(filepath.Join(t.RootDir, filepath.FromSlash(p))); err == nil {
+				sum := sha256.Sum256(data)
+				if hex.EncodeToString(sum[:]) == digest {
+					continue
+				}
+			}
+		}
+		matches = append(matches, SuspectMatch{EvidenceID: e.ID, HolonID: e.HolonID, Paths: dedupe(hits)})
+	}
+	return matches, nil
+}
+
+// clearRecoveredSuspects unmarks the suspect evidence of a holon whose
+// changed paths are all covered by carrierRef, the carrier of fresh evidence.
+func (t *Tools) clearRecoveredSuspects(ctx context.Context, holonID, carrierRef string) error {
+	patterns := CarrierPatterns(carrierRef)
+	if len(patterns) == 0 {
+		return nil
+	}
+	suspects, err := t.DB.GetSuspectEvidenceByHolon(ctx, holonID)
+	if err != nil {
+		return err
+	}
+	for _, s := range suspects {
+		if !coversPaths(patterns, strings.Split(s.ChangedPaths, ",")) {
+			continue
+		}
+		if err := t.DB.UnmarkEvidenceSuspect(ctx, s.EvidenceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// coversPaths reports whether every path matches one of the patterns.
+func coversPaths(patterns, paths []string) bool {
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		covered := false
+		for _, pattern := range patterns {
+			covered = covered || MatchCarrier(pattern, p)
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// ImpactOf returns every holon that relies on the given holons, directly or
+// through dependencies, split into holons and DRRs. The given holons are
+// included.
+func (t *Tools) ImpactOf(holonIDs []string) (holons []db.Holon, drrs []db.Holon, err error) {
+	ctx := context.Background()
+	seen := map[string]bool{}
+	queue := append([]string{}, holonIDs...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		next, err := t.DB.GetImpactedHolons(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		queue = append(queue, next...)
+	}
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		h, err := t.DB.GetHolon(ctx, id)
+		if err != nil {
+			continue
+		}
+		if h.Layer == "DRR" {
+			drrs = append(drrs, h)
+		} else {
+			holons = append(holons, h)
+		}
+	}
+	return holons, drrs, nil
+}
+
+// reconcileCarriers is quint_actualize's carrier check: evidence whose
+// carrier_ref paths or globs changed between two commits is marked suspect,
+// and what it affects is reported. Labels such as "test-runner" and URLs
+// never match.
+func (t *Tools) reconcileCarriers(changed []string, fromCommit, toCommit string) (string, error) {
+	if t.DB == nil || len(changed) == 0 {
+		return "", nil
+	}
+	matches, err := t.findSuspectEvidence(changed)
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "SUSPECT DECAY: No evidence carriers changed.\n", nil
+	}
+
+	ctx := context.Background()
+	var report strings.Builder
+	report.WriteString(fmt.Sprintf("SUSPECT DECAY: %d evidence record(s) rely on changed carriers\n", len(matches)))
+
+	var holonIDs []string
+	for _, m := range matches {
+		paths := strings.Join(m.Paths, ", ")
+		if err := t.DB.MarkEvidenceSuspect(ctx, m.EvidenceID, m.HolonID, paths, fromCommit, toCommit); err != nil {
+			return report.String(), err
+		}
+		t.AuditLog("quint_actualize", "mark_suspect", t.actor(), m.EvidenceID, "SUCCESS",
+			map[string]string{"holon_id": m.HolonID, "paths": paths, "from": fromCommit, "to": toCommit}, "")
+		t.notifyHolonChanged(m.HolonID)
+		report.WriteString(fmt.Sprintf("  - %s (holon %s): %s\n", m.EvidenceID, m.HolonID, paths))
+		holonIDs = append(holonIDs, m.HolonID)
+	}
+
+	holons, drrs, err := t.ImpactOf(dedupe(holonIDs))
+	if err != nil {
+		return report.String(), err
+	}
+	if len(holons) > 0 {
+		report.WriteString("IMPACT: Affected holons:\n")
+		for _, h := range holons {
+			report.WriteString(fmt.Sprintf("  - %s [%s] %s\n", h.ID, h.Layer, h.Title))
+		}
+	}
+	if len(drrs) > 0 {
+		report.WriteString("IMPACT: Affected decisions:\n")
+		for _, h := range drrs {
+			report.WriteString(fmt.Sprintf("  - %s %s\n", h.ID, h.Title))
+		}
+	}
+	report.WriteString("  → Re-run /q3-validate for the affected hypotheses to clear suspect evidence.\n")
+	return report.String(), nil
+}
+
+func dedupe(items []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func shortCommit(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,182 @@
+package fpf
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCarrierPatterns(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want []string
+	}{
+		{"cache/redis.go", []string{"cache/redis.go"}},
+		{"./reports/go.json#sha256=abc", []string{"reports/go.json"}},
+		{"cache/**/*.go; docs/cache.md", []string{"cache/**/*.go", "docs/cache.md"}},
+		{"test-runner", nil},
+		{"internal-logic", nil},
+		{"https://example.com/bench.html", nil},
+	}
+	for _, tt := range tests {
+		if got := CarrierPatterns(tt.ref); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CarrierPatterns(%q) = %v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestMatchCarrier(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"cache/redis.go", "cache/redis.go", true},
+		{"cache/redis.go", "cache/redis_test.go", false},
+		{"cache", "cache/lru/lru.go", true},
+		{"cache", "cachex/lru.go", false},
+		{"cache/*.go", "cache/redis.go", true},
+		{"cache/*.go", "cache/lru/lru.go", false},
+		{"cache/**/*.go", "cache/lru/lru.go", true},
+		{"cache/**/*.go", "cache/redis.go", true},
+		{"**/*_test.go", "a/b/c_test.go", true},
+		{"cache/[rl]*.go", "cache/lru.go", true},
+		{"cache/[!rl]*.go", "cache/lru.go", false},
+	}
+	for _, tt := range tests {
+		if got := MatchCarrier(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("MatchCarrier(%q, %q) = %t, want %t", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestParseNameStatus(t *testing.T) {
+	diff := "M\tcache/redis.go\nA\tdocs/new.md\nR087\told/name.go\tnew/name.go\n\n"
+	want := []string{"cache/redis.go", "docs/new.md", "old/name.go", "new/name.go"}
+	if got := ParseNameStatus(diff); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseNameStatus() = %v, want %v", got, want)
+	}
+}
+
+func TestActualize_MarksSuspectEvidence(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tempDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runGit("init")
+	runGit("config", "user.email", "test@example.com")
+	runGit("config", "user.name", "Test User")
+	writeFile("cache/redis.go", "package cache")
+	writeFile("cache/lru/lru.go", "package lru")
+	writeFile("report.json", "{}")
+	runGit("add", "cache", "report.json")
+	runGit("commit", "-m", "Initial commit")
+
+	if _, err := tools.Actualize(); err != nil {
+		t.Fatalf("Baseline Actualize failed: %v", err)
+	}
+
+	for _, id := range []string{"use-redis", "use-lru", "api"} {
+		if err := tools.DB.CreateHolon(ctx, id, "hypothesis", "system", "L2", id, "Content", "default", "global", ""); err != nil {
+			t.Fatalf("CreateHolon failed: %v", err)
+		}
+	}
+	if err := tools.DB.CreateHolon(ctx, "drr-api", "DRR", "", "DRR", "Adopt API", "Content", "default", "", ""); err != nil {
+		t.Fatalf("CreateHolon failed: %v", err)
+	}
+	_ = tools.DB.CreateRelation(ctx, "use-redis", "componentOf", "api", 3)
+	_ = tools.DB.CreateRelation(ctx, "drr-api", "selects", "api", 3)
+
+	writeFile("report.json", `{"fresh":true}`)
+	sum := sha256.Sum256([]byte(`{"fresh":true}`))
+	evidence := map[string]string{
+		"ev-redis":  "cache/redis.go",
+		"ev-lru":    "cache/lru/**",
+		"ev-runner": "test-runner",
+		"ev-report": FormatCarrierRef("report.json", sum[:]),
+	}
+	holonOf := map[string]string{"ev-redis": "use-redis", "ev-lru": "use-lru", "ev-runner": "use-redis", "ev-report": "use-lru"}
+	for id, carrier := range evidence {
+		if err := tools.DB.AddEvidence(ctx, id, holonOf[id], "test", "ok", "pass", "L2", carrier, ""); err != nil {
+			t.Fatalf("AddEvidence failed: %v", err)
+		}
+	}
+
+	writeFile("cache/redis.go", "package cache // v2")
+	runGit("add", "cache", "report.json")
+	runGit("commit", "-m", "Change redis and report")
+
+	report, err := tools.Actualize()
+	if err != nil {
+		t.Fatalf("Actualize failed: %v", err)
+	}
+	for _, want := range []string{
+		"SUSPECT DECAY: 1 evidence record(s)",
+		"ev-redis (holon use-redis): cache/redis.go",
+		"- api [L2] api",
+		"- drr-api Adopt API",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "use-lru") {
+		t.Errorf("Unchanged carriers should not be suspect, got:\n%s", report)
+	}
+
+	suspects, err := tools.DB.GetSuspectEvidence(ctx)
+	if err != nil || len(suspects) != 1 || suspects[0].EvidenceID != "ev-redis" {
+		t.Fatalf("Expected only ev-redis to be suspect, got %+v (%v)", suspects, err)
+	}
+
+	r, err := tools.CalculateR("use-redis")
+	if err != nil {
+		t.Fatalf("CalculateR failed: %v", err)
+	}
+	if !strings.Contains(r, "R_eff: 0.75") || !strings.Contains(r, "Evidence ev-redis suspect") {
+		t.Errorf("Expected suspect decay in R report, got:\n%s", r)
+	}
+
+	// Only passing evidence that re-covers the changed carrier clears it.
+	for _, rerun := range []struct{ verdict, carrier string }{
+		{"pass", "test-runner"},
+		{"pass", "cache/lru.go"},
+		{"fail", "cache/redis.go"},
+	} {
+		if _, err := tools.ManageEvidence("", "add", "use-redis", "test-report", "re-run", rerun.verdict, "L2", rerun.carrier, ""); err != nil {
+			t.Fatalf("ManageEvidence failed: %v", err)
+		}
+		if suspects, _ := tools.DB.GetSuspectEvidence(ctx); len(suspects) != 1 {
+			t.Errorf("Expected %s evidence on %s to leave ev-redis suspect, got %+v", rerun.verdict, rerun.carrier, suspects)
+		}
+	}
+
+	if _, err := tools.ManageEvidence("", "add", "use-redis", "test-report", "re-run", "pass", "L2", "cache/", ""); err != nil {
+		t.Fatalf("ManageEvidence failed: %v", err)
+	}
+	if suspects, _ := tools.DB.GetSuspectEvidence(ctx); len(suspects) != 0 {
+		t.Errorf("Expected fresh evidence on the carrier to clear suspicion, got %+v", suspects)
+	}
+}
@@ -0,0 +1,391 @@
+package fpf
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Characteristic scales (C.16) and directions.
+const (
+	ScaleOrdinal  = "ordinal"
+	ScaleInterval = "interval"
+	ScaleRatio    = "ratio"
+
+	HigherIsBetter = "higher"
+	LowerIsBetter  = "lower"
+)
+
+// errNothingToCompare means the options share no characteristic to compare.
+var errNothingToCompare = errors.New("no characteristic is recorded for every option; record them with quint_characterize")
+
+// Characterize records one characteristic of a hypothesis, replacing an
+// earlier value of the same name.
+func (t *Tools) Characterize(hypothesisID, name, scale, value, unit, direction string) (string, error) {
+	defer t.RecordWork("Characterize", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	if direction == "" {
+		direction = HigherIsBetter
+	}
+	if err := validateCharacteristic(name, scale, value, direction); err != nil {
+		return "", err
+	}
+
+	err := t.inUnit(func(tx *Tools) error {
+		ctx := context.Background()
+		if _, err := tx.DB.GetHolon(ctx, hypothesisID); errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("hypothesis not found: %s", hypothesisID)
+		} else if err != nil {
+			return err
+		}
+		if err := tx.DB.AddCharacteristic(ctx, hypothesisID, name, scale, value, unit, direction); err != nil {
+			return fmt.Errorf("failed to record characteristic: %v", err)
+		}
+		tx.AuditLog("quint_characterize", "characterize", tx.actor(), hypothesisID, "SUCCESS",
+			map[string]string{"name": name, "scale": scale, "value": value, "unit": unit, "direction": direction}, "")
+		return nil
+	})
+	if err != nil {
+		t.AuditLog("quint_characterize", "characterize", t.actor(), hypothesisID, "ERROR", map[string]string{"name": name}, err.Error())
+		return "", err
+	}
+
+	if unit != "" {
+		value += " " + unit
+	}
+	return fmt.Sprintf("%s: %s = %s (%s scale, %s is better)", hypothesisID, name, value, scale, direction), nil
+}
+
+func validateCharacteristic(name, scale, value, direction string) error {
+	if name == "" {
+		return fmt.Errorf("characteristic name is required")
+	}
+	if scale != ScaleOrdinal && scale != ScaleInterval && scale != ScaleRatio {
+		return fmt.Errorf("invalid scale %q: use ordinal, interval or ratio", scale)
+	}
+	if direction != HigherIsBetter && direction != LowerIsBetter {
+		return fmt.Errorf("invalid direction %q: use higher or lower", direction)
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return fmt.Errorf("invalid value %q: characteristics are numeric (ordinal values are ranks)", value)
+	}
+	if scale == ScaleRatio && v < 0 {
+		return fmt.Errorf("invalid value %s: a ratio scale has no negative values", value)
+	}
+	return nil
+}
+
+// criterion is one characteristic all compared options share.
+type criterion struct {
+	Name      string
+	Scale     string
+	Unit      string
+	Direction string
+	Weight    float64
+}
+
+// better reports whether a beats b on c.
+func (c criterion) better(a, b float64) bool {
+	if c.Direction == LowerIsBetter {
+		return a < b
+	}
+	return a > b
+}
+
+// normalize maps v onto [0,1], where 1 is the best value among the options.
+func (c criterion) normalize(v, lo, hi float64) float64 {
+	if hi == lo {
+		return 1
+	}
+	if c.Scale == ScaleRatio {
+		if c.Direction == LowerIsBetter {
+			if v == 0 {
+				return 1
+			}
+			return lo / v
+		}
+		return v / hi
+	}
+	if c.Direction == LowerIsBetter {
+		return (hi - v) / (hi - lo)
+	}
+	return (v - lo) / (hi - lo)
+}
+
+// comparisonRow is one option of a comparison.
+type comparisonRow struct {
+	ID          string
+	Values      map[string]float64
+	Display     map[string]string
+	Score       float64
+	DominatedBy []string
+}
+
+// comparison is the outcome of a multi-criteria comparison.
+type comparison struct {
+	Criteria []criterion
+	Skipped  []string // characteristics not recorded for every option
+	Rows     []comparisonRow
+}
+
+// Front lists the options no other option dominates.
+func (c *comparison) Front() []string {
+	var ids []string
+	for _, r := range c.Rows {
+		if len(r.DominatedBy) == 0 {
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids
+}
+
+// Compare sets the members of a decision context, and any other listed
+// hypotheses, against each other on their characteristics. Weights are
+// "name=weight" pairs; characteristics without one weigh 1.
+func (t *Tools) Compare(decisionContext string, hypothesisIDs, weights []string) (string, error) {
+	defer t.RecordWork("Compare", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	ctx := context.Background()
+
+	ids, err := t.comparedOptions(ctx, decisionContext, hypothesisIDs)
+	if err != nil {
+		return "", err
+	}
+	w, err := parseWeights(weights)
+	if err != nil {
+		return "", err
+	}
+	cmp, err := t.compare(ctx, ids, w)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	title := strings.Join(ids, ", ")
+	if decisionContext != "" {
+		title = decisionContext
+	}
+	out.WriteString(fmt.Sprintf("## Comparison: %s\n\n", title))
+	out.WriteString(cmp.markdown())
+	return out.String(), nil
+}
+
+// comparedOptions collects the members of decisionContext followed by the
+// listed hypotheses, without duplicates.
+func (t *Tools) comparedOptions(ctx context.Context, decisionContext string, hypothesisIDs []string) ([]string, error) {
+	var ids []string
+	seen := map[string]bool{}
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if decisionContext != "" {
+		if _, err := t.DB.GetHolon(ctx, decisionContext); errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("decision context not found: %s", decisionContext)
+		} else if err != nil {
+			return nil, err
+		}
+		members, err := t.DB.GetCollectionMembers(ctx, decisionContext)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			add(m.SourceID)
+		}
+	}
+	for _, id := range hypothesisIDs {
+		add(id)
+	}
+	if len(ids) < 2 {
+		return nil, fmt.Errorf("a comparison needs at least two options, got %d", len(ids))
+	}
+	return ids, nil
+}
+
+func parseWeights(pairs []string) (map[string]float64, error) {
+	weights := map[string]float64{}
+	for _, p := range pairs {
+		name, value, ok := strings.Cut(p, "=")
+		w, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil || w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("invalid weight %q: use name=weight with a weight of 0 or more", p)
+		}
+		weights[strings.TrimSpace(name)] = w
+	}
+	return weights, nil
+}
+
+// compare computes dominance, the Pareto front and weighted scores of ids.
+func (t *Tools) compare(ctx context.Context, ids []string, weights map[string]float64) (*comparison, error) {
+	cmp := &comparison{}
+	defs := map[string]criterion{}
+	count := map[string]int{}
+	for _, id := range ids {
+		chars, err := t.DB.GetCharacteristics(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		row := comparisonRow{ID: id, Values: map[string]float64{}, Display: map[string]string{}}
+		for _, ch := range chars {
+			c := criterion{Name: ch.Name, Scale: ch.Scale, Unit: ch.Unit.String, Direction: ch.Direction.String}
+			if c.Direction == "" {
+				c.Direction = HigherIsBetter
+			}
+			if prev, ok := defs[c.Name]; ok && (prev.Scale != c.Scale || prev.Direction != c.Direction || prev.Unit != c.Unit) {
+				return nil, fmt.Errorf("%s is recorded inconsistently: %s %s (%s is better) vs %s %s (%s is better); re-record it with quint_characterize",
+					c.Name, prev.Scale, prev.Unit, prev.Direction, c.Scale, c.Unit, c.Direction)
+			}
+			v, err := strconv.ParseFloat(ch.Value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s of %s is not numeric: %q", ch.Name, id, ch.Value)
+			}
+			defs[c.Name] = c
+			count[c.Name]++
+			row.Values[c.Name] = v
+			row.Display[c.Name] = ch.Value
+		}
+		cmp.Rows = append(cmp.Rows, row)
+	}
+
+	for name, c := range defs {
+		if count[name] < len(ids) {
+			cmp.Skipped = append(cmp.Skipped, name)
+			continue
+		}
+		c.Weight = 1
+		if w, ok := weights[name]; ok {
+			c.Weight = w
+		}
+		cmp.Criteria = append(cmp.Criteria, c)
+	}
+	for name := range weights {
+		if _, ok := defs[name]; !ok {
+			return nil, fmt.Errorf("weight given for %s, which no option has", name)
+		}
+	}
+	sort.Strings(cmp.Skipped)
+	sort.Slice(cmp.Criteria, func(i, j int) bool { return cmp.Criteria[i].Name < cmp.Criteria[j].Name })
+	if len(cmp.Criteria) == 0 {
+		return nil, errNothingToCompare
+	}
+
+	var total float64
+	for _, c := range cmp.Criteria {
+		total += c.Weight
+	}
+	for i := range cmp.Rows {
+		a := &cmp.Rows[i]
+		for _, b := range cmp.Rows {
+			if a.ID != b.ID && dominates(cmp.Criteria, b.Values, a.Values) {
+				a.DominatedBy = append(a.DominatedBy, b.ID)
+			}
+		}
+		if total == 0 {
+			continue
+		}
+		for _, c := range cmp.Criteria {
+			lo, hi := math.Inf(1), math.Inf(-1)
+			for _, r := range cmp.Rows {
+				lo, hi = math.Min(lo, r.Values[c.Name]), math.Max(hi, r.Values[c.Name])
+			}
+			a.Score += c.Weight * c.normalize(a.Values[c.Name], lo, hi)
+		}
+		a.Score /= total
+	}
+	sort.SliceStable(cmp.Rows, func(i, j int) bool { return cmp.Rows[i].Score > cmp.Rows[j].Score })
+	return cmp, nil
+}
+
+// dominates reports whether a is at least as good as b on every criterion
+// and better on one.
+func dominates(criteria []criterion, a, b map[string]float64) bool {
+	strictly := false
+	for _, c := range criteria {
+		if c.better(b[c.Name], a[c.Name]) {
+			return false
+		}
+		if c.better(a[c.Name], b[c.Name]) {
+			strictly = true
+		}
+	}
+	return strictly
+}
+
+// markdown renders the comparison table, the Pareto front and any
+// characteristics left out.
+func (c *comparison) markdown() string {
+	var out strings.Builder
+	out.WriteString("| Option |")
+	for _, cr := range c.Criteria {
+		arrow := "↑"
+		if cr.Direction == LowerIsBetter {
+			arrow = "↓"
+		}
+		label := cr.Name
+		if cr.Unit != "" {
+			label += " (" + cr.Unit + ")"
+		}
+		out.WriteString(fmt.Sprintf(" %s %s w=%s |", label, arrow, strconv.FormatFloat(cr.Weight, 'g', -1, 64)))
+	}
+	out.WriteString(" Score | Pareto |\n|--------|")
+	for range c.Criteria {
+		out.WriteString("------|")
+	}
+	out.WriteString("-------|--------|\n")
+	for _, r := range c.Rows {
+		out.WriteString(fmt.Sprintf("| %s |", r.ID))
+		for _, cr := range c.Criteria {
+			out.WriteString(fmt.Sprintf(" %s |", r.Display[cr.Name]))
+		}
+		pareto := "front"
+		if len(r.DominatedBy) > 0 {
+			pareto = "dominated by " + strings.Join(r.DominatedBy, ", ")
+		}
+		out.WriteString(fmt.Sprintf(" %.2f | %s |\n", r.Score, pareto))
+	}
+	out.WriteString(fmt.Sprintf("\n**Pareto front:** %s\n", strings.Join(c.Front(), ", ")))
+	if len(c.Skipped) > 0 {
+		out.WriteString(fmt.Sprintf("**Not compared** (not recorded for every option): %s\n", strings.Join(c.Skipped, ", ")))
+	}
+	return out.String()
+}
+
+// decisionComparison renders the comparison of a decision's options with
+// equal weights for its DRR, or "" when they share no characteristic.
+func (t *Tools) decisionComparison(winnerID string, rejectedIDs []string) string {
+	if t.DB == nil {
+		return ""
+	}
+	ids := []string{winnerID}
+	for _, id := range rejectedIDs {
+		if id != "" && id != winnerID {
+			ids = append(ids, id)
+		}
+	}
+	if winnerID == "" || len(ids) < 2 {
+		return ""
+	}
+	cmp, err := t.compare(context.Background(), ids, nil)
+	if errors.Is(err, errNothingToCompare) {
+		return ""
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: comparison left out of the DRR: %v\n", err)
+		return ""
+	}
+	return cmp.markdown()
+}
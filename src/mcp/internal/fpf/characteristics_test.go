@@ -0,0 +1,93 @@
+package fpf
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	if err := tools.DB.CreateHolon(ctx, "caching-decision", "decision", "episteme", "L0", "Caching Decision", "Content", "default", "backend", ""); err != nil {
+		t.Fatalf("Failed to create decision context: %v", err)
+	}
+	for _, title := range []string{"Use Redis", "Use Memcached", "Use LRU"} {
+		if _, err := tools.ProposeHypothesis(title, title+" cache", "backend", "system", "{}", "caching-decision", nil, 3, 0, "", ""); err != nil {
+			t.Fatalf("ProposeHypothesis failed: %v", err)
+		}
+	}
+	// Redis is fast and dear, LRU cheap and slow; Memcached is slower and
+	// dearer than Redis, so Redis dominates it.
+	for _, c := range []struct{ id, name, scale, value, unit, direction string }{
+		{"use-redis", "latency", ScaleRatio, "2", "ms", LowerIsBetter},
+		{"use-redis", "cost", ScaleRatio, "300", "usd", LowerIsBetter},
+		{"use-redis", "maturity", ScaleOrdinal, "3", "", ""},
+		{"use-memcached", "latency", ScaleRatio, "4", "ms", LowerIsBetter},
+		{"use-memcached", "cost", ScaleRatio, "400", "usd", LowerIsBetter},
+		{"use-lru", "latency", ScaleRatio, "10", "ms", LowerIsBetter},
+		{"use-lru", "cost", ScaleRatio, "0", "usd", LowerIsBetter},
+	} {
+		if _, err := tools.Characterize(c.id, c.name, c.scale, c.value, c.unit, c.direction); err != nil {
+			t.Fatalf("Characterize %s/%s failed: %v", c.id, c.name, err)
+		}
+	}
+
+	out, err := tools.Compare("caching-decision", nil, []string{"latency=3"})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	for _, want := range []string{
+		"| latency (ms) ↓ w=3 |",
+		"| use-memcached | 400 | 4 | 0.38 | dominated by use-redis |",
+		"| use-redis | 300 | 2 | 0.75 | front |",
+		"**Pareto front:** use-redis, use-lru",
+		"**Not compared** (not recorded for every option): maturity",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the comparison to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "| use-redis |") > strings.Index(out, "| use-lru |") {
+		t.Errorf("Expected options ordered by score, got:\n%s", out)
+	}
+
+	for _, bad := range []struct {
+		name                    string
+		scale, value, direction string
+	}{
+		{"latency", "nominal", "1", ""},
+		{"latency", ScaleRatio, "fast", ""},
+		{"latency", ScaleRatio, "-1", ""},
+		{"latency", ScaleRatio, "1", "sideways"},
+	} {
+		if _, err := tools.Characterize("use-redis", bad.name, bad.scale, bad.value, "", bad.direction); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+	if _, err := tools.Characterize("no-such-hypothesis", "latency", ScaleRatio, "1", "", ""); err == nil {
+		t.Error("Expected an unknown hypothesis to be rejected")
+	}
+	if _, err := tools.Compare("caching-decision", nil, []string{"throughput=2"}); err == nil {
+		t.Error("Expected a weight for an unknown characteristic to fail")
+	}
+	if _, err := tools.Compare("", []string{"use-redis"}, nil); err == nil {
+		t.Error("Expected a single option to fail")
+	}
+
+	for _, id := range []string{"use-redis", "use-lru"} {
+		if _, err := tools.VerifyHypothesis(id, "{}", "PASS"); err != nil {
+			t.Fatalf("VerifyHypothesis failed: %v", err)
+		}
+	}
+	path, err := tools.FinalizeDecision("Cache", "use-redis", []string{"use-lru"}, "Context", "Decision", "Rationale", "Consequences", "", "", nil)
+	if err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "### Multi-Criteria Comparison") || !strings.Contains(string(data), "| use-lru | 0 | 10 |") {
+		t.Errorf("Expected the DRR to embed the comparison, got:\n%s", data)
+	}
+}
@@ -0,0 +1,204 @@
+package fpf
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// CrossContextCL is the congruence level assigned to dependencies that cross
+// a bounded-context boundary: evidence from a different context is CL1.
+const CrossContextCL = 1
+
+// dependencyCLs returns the congruence level of each dependency: cl, capped
+// at CrossContextCL for holons in another bounded context. Dependencies that
+// do not exist keep cl.
+func (t *Tools) dependencyCLs(contextID string, dependsOn []string, cl int) ([]int, error) {
+	cls := make([]int, len(dependsOn))
+	for i, depID := range dependsOn {
+		cls[i] = cl
+		if t.DB == nil {
+			continue
+		}
+		dep, err := t.DB.GetHolon(context.Background(), depID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if dep.ContextID != contextID && cl > CrossContextCL {
+			cls[i] = CrossContextCL
+		}
+	}
+	return cls, nil
+}
+
+var contextIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// contextWorkspaceDirs are created for every bounded context.
+var contextWorkspaceDirs = []string{
+	"evidence",
+	"decisions",
+	"sessions",
+	"knowledge/L0",
+	"knowledge/L1",
+	"knowledge/L2",
+	"knowledge/invalid",
+}
+
+// ContextID returns the active bounded context.
+func (t *Tools) ContextID() string {
+	return t.FSM.ActiveContext()
+}
+
+// GetContextDir returns the workspace directory of the active context.
+func (t *Tools) GetContextDir() string {
+	return t.contextDir(t.ContextID())
+}
+
+func (t *Tools) contextDir(contextID string) string {
+	if contextID == "" || contextID == db.DefaultContextID {
+		return t.GetFPFDir()
+	}
+	return filepath.Join(t.GetFPFDir(), "contexts", contextID)
+}
+
+func createWorkspaceDirs(root string) error {
+	for _, d := range contextWorkspaceDirs {
+		path := filepath.Join(root, d)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(path, ".gitkeep"), []byte(""), 0644); err != nil {
+			return fmt.Errorf("failed to write .gitkeep file: %v", err)
+		}
+	}
+	return nil
+}
+
+// ManageContext lists, creates or switches bounded contexts.
+func (t *Tools) ManageContext(action, contextID, description string) (string, error) {
+	defer t.RecordWork("ManageContext", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+
+	switch action {
+	case "", "list":
+		return t.listContexts()
+	case "create":
+		return t.createContext(contextID, description)
+	case "switch":
+		return t.switchContext(contextID)
+	default:
+		return "", fmt.Errorf("unknown action %q (expected list, create or switch)", action)
+	}
+}
+
+func (t *Tools) listContexts() (string, error) {
+	ctx := context.Background()
+	contexts, err := t.DB.ListContexts(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	active := t.ContextID()
+	var out strings.Builder
+	out.WriteString("## Bounded Contexts\n\n")
+	out.WriteString("| Context | Phase | Holons | Description |\n")
+	out.WriteString("|---------|-------|--------|-------------|\n")
+	for _, c := range contexts {
+		name := c.ID
+		if c.ID == active {
+			name = "**" + c.ID + "** (active)"
+		}
+
+		var total int64
+		counts, _ := t.DB.CountHolonsByLayer(ctx, c.ID)
+		for _, row := range counts {
+			total += row.Count
+		}
+
+		phase := t.FSM.DerivePhase(c.ID)
+		out.WriteString(fmt.Sprintf("| %s | %s | %d | %s |\n", name, phase, total, c.Description.String))
+	}
+	return out.String(), nil
+}
+
+func (t *Tools) createContext(contextID, description string) (string, error) {
+	if !contextIDPattern.MatchString(contextID) {
+		return "", fmt.Errorf("invalid context id %q: use lowercase letters, digits, '-' or '_'", contextID)
+	}
+
+	ctx := context.Background()
+	if _, err := t.DB.GetContext(ctx, contextID); err == nil {
+		return "", fmt.Errorf("context %q already exists", contextID)
+	}
+
+	if err := createWorkspaceDirs(t.contextDir(contextID)); err != nil {
+		return "", fmt.Errorf("failed to create context directories: %w", err)
+	}
+	if err := t.DB.CreateContext(ctx, contextID, description); err != nil {
+		return "", err
+	}
+
+	t.AuditLog("quint_context", "create_context", t.actor(), contextID, "SUCCESS", map[string]string{"description": description}, "")
+	return fmt.Sprintf("Created context %q at %s. Use action=switch to make it active.", contextID, t.contextDir(contextID)), nil
+}
+
+func (t *Tools) switchContext(contextID string) (string, error) {
+	ctx := context.Background()
+	if _, err := t.DB.GetContext(ctx, contextID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("context %q does not exist (create it first)", contextID)
+		}
+		return "", err
+	}
+
+	fsm, err := LoadState(contextID, t.DB)
+	if err != nil {
+		return "", err
+	}
+	if err := t.DB.SetActiveContext(ctx, contextID); err != nil {
+		return "", err
+	}
+
+	previous := t.ContextID()
+	t.FSM = fsm
+	t.AuditLog("quint_context", "switch_context", t.actor(), contextID, "SUCCESS", map[string]string{"from": previous}, "")
+
+	return fmt.Sprintf("Switched context: %s → %s (phase: %s)", previous, contextID, t.currentPhase()), nil
+}
+
+// contextFSM returns the state of a bounded context: the active FSM for the
+// active context, otherwise the context's persisted state.
+func (t *Tools) contextFSM(contextID string) (*FSM, error) {
+	if contextID == t.ContextID() {
+		return t.FSM, nil
+	}
+	return LoadState(contextID, t.DB)
+}
+
+// ensureContext validates an explicit context argument, returning the
+// active context when it is empty.
+func (t *Tools) ensureContext(contextID string) (string, error) {
+	if contextID == "" {
+		return t.ContextID(), nil
+	}
+	if t.DB == nil {
+		return contextID, nil
+	}
+	if _, err := t.DB.GetContext(context.Background(), contextID); err != nil {
+		return "", fmt.Errorf("context %q does not exist", contextID)
+	}
+	return contextID, nil
+}
@@ -0,0 +1,226 @@
+package fpf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManageContext_CreateListSwitch(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+
+	if _, err := tools.ManageContext("create", "payments", "Payments service"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, err := tools.ManageContext("create", "payments", ""); err == nil {
+		t.Error("Expected error creating a duplicate context")
+	}
+	if _, err := tools.ManageContext("create", "Bad Id", ""); err == nil {
+		t.Error("Expected error for invalid context id")
+	}
+
+	l0 := filepath.Join(tempDir, ".quint", "contexts", "payments", "knowledge", "L0")
+	if _, err := os.Stat(l0); err != nil {
+		t.Errorf("Expected context workspace at %s: %v", l0, err)
+	}
+
+	list, err := tools.ManageContext("list", "", "")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if !strings.Contains(list, "**default** (active)") || !strings.Contains(list, "| payments |") {
+		t.Errorf("Unexpected context list:\n%s", list)
+	}
+
+	if _, err := tools.ManageContext("switch", "missing", ""); err == nil {
+		t.Error("Expected error switching to a missing context")
+	}
+	if _, err := tools.ManageContext("switch", "payments", ""); err != nil {
+		t.Fatalf("switch failed: %v", err)
+	}
+	if tools.ContextID() != "payments" {
+		t.Errorf("Expected active context payments, got %s", tools.ContextID())
+	}
+	if tools.GetContextDir() != filepath.Join(tempDir, ".quint", "contexts", "payments") {
+		t.Errorf("Unexpected context dir %s", tools.GetContextDir())
+	}
+
+	active, err := tools.DB.GetActiveContextID(context.Background())
+	if err != nil || active != "payments" {
+		t.Errorf("Expected persisted active context payments, got %q (%v)", active, err)
+	}
+}
+
+func TestManageContext_IndependentState(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	store := tools.DB
+
+	if _, err := tools.ManageContext("create", "search", ""); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	tools.FSM.State.AssuranceThreshold = 0.95
+	if err := tools.FSM.SaveState("default"); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	if _, err := tools.ProposeHypothesis("Default Idea", "Content", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	if _, err := tools.ManageContext("switch", "search", ""); err != nil {
+		t.Fatalf("switch failed: %v", err)
+	}
+	if got := tools.FSM.GetAssuranceThreshold(); got == 0.95 {
+		t.Error("Threshold leaked from default context into search")
+	}
+	if phase := tools.FSM.GetPhase(); phase != PhaseIdle {
+		t.Errorf("Expected search context to be IDLE, got %s", phase)
+	}
+
+	reloaded, err := LoadState("default", store)
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if reloaded.GetAssuranceThreshold() != 0.95 {
+		t.Errorf("Expected default threshold 0.95, got %.2f", reloaded.GetAssuranceThreshold())
+	}
+	if phase := reloaded.GetPhase(); phase != PhaseAbduction {
+		t.Errorf("Expected default context in ABDUCTION, got %s", phase)
+	}
+}
+
+func TestProposeHypothesis_InContext(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	if _, err := tools.ManageContext("create", "payments", ""); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if err := tools.DB.CreateHolon(ctx, "shared-cache", "hypothesis", "system", "L2", "Shared Cache", "Content", "default", "global", ""); err != nil {
+		t.Fatalf("CreateHolon failed: %v", err)
+	}
+
+	if _, err := tools.ProposeHypothesis("Ledger", "Content", "global", "system", "{}", "", []string{"shared-cache"}, 3, 0, "", "payments"); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	path := filepath.Join(tempDir, ".quint", "contexts", "payments", "knowledge", "L0", "ledger.md")
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected hypothesis file at %s: %v", path, err)
+	}
+
+	holon, err := tools.DB.GetHolon(ctx, "ledger")
+	if err != nil {
+		t.Fatalf("GetHolon failed: %v", err)
+	}
+	if holon.ContextID != "payments" {
+		t.Errorf("Expected context payments, got %s", holon.ContextID)
+	}
+
+	var cl int
+	err = sqlDB(t, tools).QueryRowContext(ctx,
+		`SELECT congruence_level FROM relations WHERE source_id = 'shared-cache' AND target_id = 'ledger'`).Scan(&cl)
+	if err != nil {
+		t.Fatalf("Failed to query relation: %v", err)
+	}
+	if cl != CrossContextCL {
+		t.Errorf("Expected cross-context CL%d, got CL%d", CrossContextCL, cl)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if !strings.Contains(string(data), "dependency_cl: 1\n") {
+		t.Errorf("Expected the capped CL in the frontmatter, got:\n%s", data)
+	}
+
+	if _, err := tools.ProposeHypothesis("Refunds", "Content", "global", "system", "{}", "", []string{"shared-cache", "ledger"}, 2, 0, "", "payments"); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	data, err = os.ReadFile(filepath.Join(tempDir, ".quint", "contexts", "payments", "knowledge", "L0", "refunds.md"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if !strings.Contains(string(data), "dependency_cl: 1, 2\n") {
+		t.Errorf("Expected one CL per dependency in the frontmatter, got:\n%s", data)
+	}
+
+	if _, err := tools.ProposeHypothesis("Ledger", "Content", "global", "system", "{}", "", nil, 3, 0, "", ""); err == nil {
+		t.Error("Expected error reusing a holon id from another context")
+	}
+	if _, err := tools.ProposeHypothesis("Other", "Content", "global", "system", "{}", "", nil, 3, 0, "", "missing"); err == nil {
+		t.Error("Expected error proposing into a missing context")
+	}
+}
+
+func TestCheckPreconditions_Context(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	tests := []struct {
+		name    string
+		args    map[string]string
+		wantErr bool
+	}{
+		{"list", map[string]string{"action": "list"}, false},
+		{"create with id", map[string]string{"action": "create", "context_id": "payments"}, false},
+		{"create without id", map[string]string{"action": "create"}, true},
+		{"switch without id", map[string]string{"action": "switch"}, true},
+		{"unknown action", map[string]string{"action": "delete", "context_id": "payments"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tools.CheckPreconditions("quint_context", tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckPreconditions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLifecycle_InactiveContext(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	if _, err := tools.ManageContext("create", "payments", ""); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis("Ledger", "Content", "global", "system", "{}", "", nil, 3, 0, "", "payments"); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if tools.ContextID() != "default" {
+		t.Fatalf("Expected default to stay active, got %s", tools.ContextID())
+	}
+
+	if err := tools.CheckPreconditions("quint_verify", map[string]string{"hypothesis_id": "ledger", "verdict": "PASS"}); err != nil {
+		t.Fatalf("Expected verify preconditions to find the hypothesis: %v", err)
+	}
+	if _, err := tools.VerifyHypothesis("ledger", "{}", "PASS"); err != nil {
+		t.Fatalf("VerifyHypothesis failed: %v", err)
+	}
+	if err := tools.CheckPreconditions("quint_test", map[string]string{"hypothesis_id": "ledger", "test_type": "internal", "result": "ok", "verdict": "PASS"}); err != nil {
+		t.Fatalf("Expected test preconditions to find the hypothesis: %v", err)
+	}
+	if _, err := tools.ManageEvidence(PhaseInduction, "add", "ledger", "internal", "ok", "pass", "L2", "test-runner", ""); err != nil {
+		t.Fatalf("ManageEvidence failed: %v", err)
+	}
+	if _, err := tools.FinalizeDecision("Adopt Ledger", "ledger", nil, "Context", "Decision", "Rationale", "Consequences", "", "", nil); err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+
+	workspace := filepath.Join(tempDir, ".quint", "contexts", "payments")
+	for _, pattern := range []string{"knowledge/L2/ledger.md", "evidence/*-internal-ledger.md", "evidence/*-verification-ledger.md", "decisions/DRR-*-adopt-ledger.md"} {
+		if matches, _ := filepath.Glob(filepath.Join(workspace, pattern)); len(matches) != 1 {
+			t.Errorf("Expected %s in the payments workspace, found %v", pattern, matches)
+		}
+	}
+	if matches, _ := filepath.Glob(filepath.Join(tempDir, ".quint", "decisions", "*.md")); len(matches) != 0 {
+		t.Errorf("Expected nothing in the default workspace, found %v", matches)
+	}
+	if drr, err := tools.DB.GetHolon(ctx, "adopt-ledger"); err != nil || drr.ContextID != "payments" {
+		t.Errorf("Expected the DRR in context payments, got %+v (%v)", drr, err)
+	}
+}
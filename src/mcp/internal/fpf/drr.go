@@ -0,0 +1,223 @@
+package fpf
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// DRR lifecycle statuses; superseded and deprecated are final.
+const (
+	DRRProposed   = "proposed"
+	DRRAccepted   = "accepted"
+	DRRSuperseded = "superseded"
+	DRRDeprecated = "deprecated"
+)
+
+var drrTransitions = map[string][]string{
+	DRRProposed: {DRRAccepted, DRRSuperseded, DRRDeprecated},
+	DRRAccepted: {DRRSuperseded, DRRDeprecated},
+}
+
+// drrStatus is the lifecycle status of a DRR holon.
+func drrStatus(h db.Holon) string {
+	return orAccepted(h.Status.String)
+}
+
+// orAccepted reads a recorded DRR status, where none means accepted.
+func orAccepted(status string) string {
+	if status == "" {
+		return DRRAccepted
+	}
+	return status
+}
+
+// isActiveDRR reports whether a DRR status still stands as the decision.
+func isActiveDRR(status string) bool {
+	return status == DRRProposed || status == DRRAccepted
+}
+
+func (t *Tools) getDRR(ctx context.Context, id string) (db.Holon, error) {
+	h, err := t.DB.GetHolon(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.Holon{}, fmt.Errorf("DRR not found: %s", id)
+	}
+	if err != nil {
+		return db.Holon{}, err
+	}
+	if h.Type != "DRR" {
+		return db.Holon{}, fmt.Errorf("%s is a %s, not a DRR", id, h.Type)
+	}
+	return h, nil
+}
+
+// SetDRRStatus accepts a proposed DRR or deprecates one. Superseding goes
+// through quint_decide, which records the replacement.
+func (t *Tools) SetDRRStatus(drrID, status, reason string) (string, error) {
+	defer t.RecordWork("SetDRRStatus", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	if status == DRRSuperseded {
+		return "", fmt.Errorf("a DRR is superseded by deciding again with supersedes=%s", drrID)
+	}
+	if status != DRRAccepted && status != DRRDeprecated {
+		return "", fmt.Errorf("invalid DRR status %q: use accepted or deprecated", status)
+	}
+
+	var from string
+	err := t.inUnit(func(tx *Tools) error {
+		ctx := context.Background()
+		h, err := tx.getDRR(ctx, drrID)
+		if err != nil {
+			return err
+		}
+		from = drrStatus(h)
+		fields := map[string]string{}
+		if reason != "" {
+			fields["status_reason"] = reason
+		}
+		if err := tx.transitionDRR(ctx, h, status, fields); err != nil {
+			return err
+		}
+		tx.AuditLog("quint_drr_status", "set_status", tx.actor(), drrID, "SUCCESS",
+			map[string]string{"from": from, "to": status}, reason)
+		return nil
+	})
+	if err != nil {
+		t.AuditLog("quint_drr_status", "set_status", t.actor(), drrID, "ERROR", map[string]string{"to": status}, err.Error())
+		return "", err
+	}
+
+	out := fmt.Sprintf("DRR %s: %s → %s", drrID, from, status)
+	if status == DRRDeprecated {
+		out += "\n\nThis decision no longer stands. Run /q1-hypothesize to explore a replacement, then decide with supersedes=" + drrID
+	}
+	return out, nil
+}
+
+// transitionDRR moves h to status, writing the status and any extra fields
+// into its frontmatter. It must run inside a unit of work.
+func (t *Tools) transitionDRR(ctx context.Context, h db.Holon, status string, fields map[string]string) error {
+	from := drrStatus(h)
+	if !slices.Contains(drrTransitions[from], status) {
+		return fmt.Errorf("DRR %s is %s and cannot become %s", h.ID, from, status)
+	}
+	if err := t.DB.UpdateHolonStatus(ctx, h.ID, status); err != nil {
+		return fmt.Errorf("failed to update status of %s: %v", h.ID, err)
+	}
+
+	path, err := t.drrFile(h)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "Warning: no DRR file found for %s; only the database was updated\n", h.ID)
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fm, body, ok := parseFrontmatter(string(content))
+	if !ok {
+		return fmt.Errorf("%s has no frontmatter", t.relPath(path))
+	}
+	updated := parseFrontmatterFields(fm)
+	delete(updated, "content_hash")
+	updated["status"] = status
+	for k, v := range fields {
+		updated[k] = v
+	}
+	return t.uow.writeProjection(path, updated, body)
+}
+
+// drrFile finds the markdown file of a DRR by its frontmatter id, returning
+// "" if there is none.
+func (t *Tools) drrFile(h db.Holon) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(t.contextDir(h.ContextID), "decisions", "*.md"))
+	if err != nil {
+		return "", err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		fm, body, ok := parseFrontmatter(string(data))
+		if !ok {
+			continue
+		}
+		id := parseFrontmatterFields(fm)["id"]
+		if id == "" {
+			id = t.Slugify(extractMarkdownHeading(body))
+		}
+		if id == h.ID {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// supersededBy lists the DRRs that replaced drrID.
+func (t *Tools) supersededBy(ctx context.Context, drrID string) []string {
+	rels, err := t.DB.GetRelationsByTarget(ctx, drrID, "supersedes")
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, r := range rels {
+		ids = append(ids, r.SourceID)
+	}
+	return ids
+}
+
+// describeInactiveDRR says why a DRR no longer stands, e.g. "superseded by
+// use-valkey".
+func (t *Tools) describeInactiveDRR(ctx context.Context, h db.Holon) string {
+	status := drrStatus(h)
+	if by := t.supersededBy(ctx, h.ID); status == DRRSuperseded && len(by) > 0 {
+		return fmt.Sprintf("%s by %s", status, strings.Join(by, ", "))
+	}
+	return status
+}
+
+// decisionFlags warns when a holon is a DRR that no longer stands, or the
+// winner of such DRRs with no active DRR still selecting it.
+func (t *Tools) decisionFlags(ctx context.Context, holonID string) []string {
+	h, err := t.DB.GetHolon(ctx, holonID)
+	if err != nil {
+		return nil
+	}
+	if h.Type == "DRR" {
+		if !isActiveDRR(drrStatus(h)) {
+			return []string{fmt.Sprintf("DRR %s", t.describeInactiveDRR(ctx, h))}
+		}
+		return nil
+	}
+
+	selectors, err := t.DB.GetRelationsByTarget(ctx, holonID, "selects")
+	if err != nil {
+		return nil
+	}
+	var flags []string
+	for _, r := range selectors {
+		drr, err := t.DB.GetHolon(ctx, r.SourceID)
+		if err != nil {
+			continue
+		}
+		if isActiveDRR(drrStatus(drr)) {
+			return nil
+		}
+		flags = append(flags, fmt.Sprintf("Selected by DRR %s, now %s", drr.ID, t.describeInactiveDRR(ctx, drr)))
+	}
+	return flags
+}
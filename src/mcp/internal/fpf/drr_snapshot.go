@@ -0,0 +1,182 @@
+package fpf
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Snapshot roles in drr_snapshots.
+const (
+	SnapshotWinner   = "winner"
+	SnapshotRejected = "rejected"
+)
+
+// snapshotEvidence is one evidence record as it stood at decision time.
+type snapshotEvidence struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Verdict     string `json:"verdict"`
+	ValidUntil  string `json:"valid_until,omitempty"`
+	WaivedUntil string `json:"waived_until,omitempty"`
+	WaivedBy    string `json:"waived_by,omitempty"`
+}
+
+// holonSnapshot is the assurance of one decision option at decision time.
+type holonSnapshot struct {
+	HolonID   string
+	Role      string
+	REff      float64
+	AuditTree string // winner only
+	Evidence  []snapshotEvidence
+}
+
+// decisionSnapshot computes the snapshot of the winner and each rejected
+// alternative. Options without a holon in the database are left out.
+func (t *Tools) decisionSnapshot(ctx context.Context, winnerID string, rejectedIDs []string) ([]holonSnapshot, error) {
+	calc := t.FSM.NewCalculator(t.DB)
+	var snaps []holonSnapshot
+	add := func(id, role string) error {
+		if _, err := t.DB.GetHolon(ctx, id); errors.Is(err, sql.ErrNoRows) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		report, err := calc.CalculateReliability(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to calculate R_eff of %s: %v", id, err)
+		}
+		snap := holonSnapshot{HolonID: id, Role: role, REff: report.FinalScore}
+		if role == SnapshotWinner {
+			if snap.AuditTree, err = t.buildAuditTree(id, 0, calc); err != nil {
+				return fmt.Errorf("failed to build the audit tree of %s: %v", id, err)
+			}
+		}
+		if snap.Evidence, err = t.evidenceSnapshot(ctx, id); err != nil {
+			return err
+		}
+		snaps = append(snaps, snap)
+		return nil
+	}
+
+	if winnerID != "" {
+		if err := add(winnerID, SnapshotWinner); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range rejectedIDs {
+		if id != "" && id != winnerID {
+			if err := add(id, SnapshotRejected); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return snaps, nil
+}
+
+func (t *Tools) evidenceSnapshot(ctx context.Context, holonID string) ([]snapshotEvidence, error) {
+	evidence, err := t.DB.GetEvidence(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+	var out []snapshotEvidence
+	for _, e := range evidence {
+		se := snapshotEvidence{ID: e.ID, Type: e.Type, Verdict: e.Verdict}
+		if e.ValidUntil.Valid {
+			se.ValidUntil = e.ValidUntil.Time.Format("2006-01-02")
+		}
+		w, err := t.DB.GetActiveWaiverForEvidence(ctx, e.ID)
+		if err == nil {
+			se.WaivedUntil, se.WaivedBy = w.WaivedUntil.Format("2006-01-02"), w.WaivedBy
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		out = append(out, se)
+	}
+	return out, nil
+}
+
+// saveDecisionSnapshot writes snaps to drr_snapshots.
+func (t *Tools) saveDecisionSnapshot(ctx context.Context, drrID string, snaps []holonSnapshot) error {
+	for _, s := range snaps {
+		evidence, err := json.Marshal(s.Evidence)
+		if err != nil {
+			return err
+		}
+		if err := t.DB.InsertDRRSnapshot(ctx, drrID, s.HolonID, s.Role, s.REff, s.AuditTree, string(evidence)); err != nil {
+			return fmt.Errorf("failed to store snapshot of %s: %v", s.HolonID, err)
+		}
+	}
+	return nil
+}
+
+// snapshotMarkdown renders the snapshot section of a DRR.
+func snapshotMarkdown(snaps []holonSnapshot, at time.Time) string {
+	if len(snaps) == 0 {
+		return ""
+	}
+	var out strings.Builder
+	out.WriteString("## Assurance Snapshot\n")
+	out.WriteString(fmt.Sprintf("Frozen at decision time (%s). Compare with `quint_calculate_r` to see how assurance has moved since.\n\n", at.UTC().Format(time.RFC3339)))
+
+	var rejected []holonSnapshot
+	for _, s := range snaps {
+		if s.Role != SnapshotWinner {
+			rejected = append(rejected, s)
+			continue
+		}
+		out.WriteString(fmt.Sprintf("### Winner: %s (R_eff %.2f)\n", s.HolonID, s.REff))
+		out.WriteString("```\n" + s.AuditTree + "```\n\n")
+	}
+	if len(rejected) > 0 {
+		out.WriteString("### Rejected Alternatives\n")
+		out.WriteString("| Hypothesis | R_eff |\n")
+		out.WriteString("|------------|-------|\n")
+		for _, s := range rejected {
+			out.WriteString(fmt.Sprintf("| %s | %.2f |\n", s.HolonID, s.REff))
+		}
+		out.WriteString("\n")
+	}
+
+	out.WriteString("### Evidence\n")
+	out.WriteString("| Holon | Evidence | Verdict | Valid Until | Waiver |\n")
+	out.WriteString("|-------|----------|---------|-------------|--------|\n")
+	rows := 0
+	for _, s := range snaps {
+		for _, e := range s.Evidence {
+			waiver := "-"
+			if e.WaivedUntil != "" {
+				waiver = fmt.Sprintf("until %s by %s", e.WaivedUntil, e.WaivedBy)
+			}
+			out.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", s.HolonID, e.ID, e.Verdict, orDash(e.ValidUntil), waiver))
+			rows++
+		}
+	}
+	if rows == 0 {
+		out.WriteString("| - | none recorded | - | - | - |\n")
+	}
+	return out.String()
+}
+
+// snapshotComparison sets the R_eff recorded in each DRR snapshot of a holon
+// against its R_eff now.
+func (t *Tools) snapshotComparison(ctx context.Context, holonID string, now float64) (string, error) {
+	snaps, err := t.DB.GetDRRSnapshotsByHolon(ctx, holonID)
+	if err != nil || len(snaps) == 0 {
+		return "", err
+	}
+	var out strings.Builder
+	out.WriteString("\n**At decision time:**\n")
+	for _, s := range snaps {
+		when := ""
+		if s.CreatedAt.Valid {
+			when = ", " + s.CreatedAt.Time.Format("2006-01-02")
+		}
+		out.WriteString(fmt.Sprintf("- %s (%s%s): R_eff %.2f → now %.2f (%+.2f)\n", s.DrrID, s.Role, when, s.REff, now, now-s.REff))
+	}
+	return out.String(), nil
+}
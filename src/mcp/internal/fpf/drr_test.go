@@ -0,0 +1,142 @@
+package fpf
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDRRLifecycle(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	for _, title := range []string{"Use Redis", "Use Valkey", "Use LRU"} {
+		if _, err := tools.ProposeHypothesis(title, title+" cache", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+			t.Fatalf("ProposeHypothesis failed: %v", err)
+		}
+		if _, err := tools.VerifyHypothesis(tools.Slugify(title), "{}", "PASS"); err != nil {
+			t.Fatalf("VerifyHypothesis failed: %v", err)
+		}
+	}
+
+	oldPath, err := tools.FinalizeDecision("Cache v1", "use-redis", nil, "Context", "Decision", "Rationale", "Consequences", "", "", nil)
+	if err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+	if h, _ := tools.DB.GetHolon(ctx, "cache-v1"); drrStatus(h) != DRRAccepted {
+		t.Fatalf("Expected a new DRR to be accepted, got %q", h.Status.String)
+	}
+
+	if _, err := tools.FinalizeDecision("Cache v2", "use-valkey", nil, "Context", "Decision", "Rationale", "Consequences", "", "", []string{"cache-v1"}); err != nil {
+		t.Fatalf("FinalizeDecision with supersedes failed: %v", err)
+	}
+	if h, _ := tools.DB.GetHolon(ctx, "cache-v1"); drrStatus(h) != DRRSuperseded {
+		t.Errorf("Expected cache-v1 to be superseded, got %q", h.Status.String)
+	}
+	if by := tools.supersededBy(ctx, "cache-v1"); len(by) != 1 || by[0] != "cache-v2" {
+		t.Errorf("Expected cache-v2 supersedes cache-v1, got %v", by)
+	}
+	data, _ := os.ReadFile(oldPath)
+	if !strings.Contains(string(data), "status: superseded") || !strings.Contains(string(data), "superseded_by: cache-v2") {
+		t.Errorf("Expected the old DRR frontmatter to be updated, got:\n%s", data)
+	}
+	if _, tampered, _, _, _ := ValidateFile(oldPath); tampered {
+		t.Error("Expected the rewritten DRR to keep a valid content hash")
+	}
+
+	tree, err := tools.VisualizeAudit("use-redis")
+	if err != nil {
+		t.Fatalf("VisualizeAudit failed: %v", err)
+	}
+	if !strings.Contains(tree, "! Selected by DRR cache-v1, now superseded by cache-v2") {
+		t.Errorf("Expected the old winner to be flagged, got:\n%s", tree)
+	}
+	if tree, _ := tools.VisualizeAudit("use-valkey"); strings.Contains(tree, "Selected by DRR") {
+		t.Errorf("Expected the new winner not to be flagged, got:\n%s", tree)
+	}
+	report, err := tools.CheckDecay("", "", "", "")
+	if err != nil {
+		t.Fatalf("CheckDecay failed: %v", err)
+	}
+	if !strings.Contains(report, "### SUPERSEDED") || !strings.Contains(report, "| cache-v1 | superseded by cache-v2 | use-redis (L2) ⚠ no standing DRR |") {
+		t.Errorf("Expected the freshness report to list cache-v1, got:\n%s", report)
+	}
+
+	// A superseded DRR is final: nothing of the failed decision is kept.
+	if _, err := tools.FinalizeDecision("Cache v3", "use-lru", nil, "Context", "Decision", "Rationale", "Consequences", "", "", []string{"cache-v1"}); err == nil {
+		t.Error("Expected superseding a superseded DRR to fail")
+	}
+	if _, err := tools.DB.GetHolon(ctx, "cache-v3"); err == nil {
+		t.Error("Expected the failed DRR to be rolled back")
+	}
+
+	if _, err := tools.FinalizeDecision("Cache v3", "use-lru", nil, "Context", "Decision", "Rationale", "Consequences", "", DRRProposed, nil); err != nil {
+		t.Fatalf("FinalizeDecision as proposed failed: %v", err)
+	}
+	if _, err := tools.SetDRRStatus("cache-v3", DRRAccepted, "reviewed"); err != nil {
+		t.Fatalf("SetDRRStatus failed: %v", err)
+	}
+	if _, err := tools.CheckDecay("cache-v3", "", "", ""); err != nil {
+		t.Fatalf("Deprecating a DRR through CheckDecay failed: %v", err)
+	}
+	if h, _ := tools.DB.GetHolon(ctx, "cache-v3"); drrStatus(h) != DRRDeprecated {
+		t.Errorf("Expected cache-v3 to be deprecated, got %q", h.Status.String)
+	}
+	if _, err := tools.SetDRRStatus("cache-v3", DRRAccepted, ""); err == nil {
+		t.Error("Expected a deprecated DRR not to be accepted again")
+	}
+	if _, err := tools.SetDRRStatus("use-lru", DRRDeprecated, ""); err == nil {
+		t.Error("Expected SetDRRStatus to reject a hypothesis")
+	}
+}
+
+func TestDRRAssuranceSnapshot(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	for _, title := range []string{"Use Redis", "Use LRU"} {
+		if _, err := tools.ProposeHypothesis(title, title+" cache", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+			t.Fatalf("ProposeHypothesis failed: %v", err)
+		}
+		if _, err := tools.VerifyHypothesis(tools.Slugify(title), "{}", "PASS"); err != nil {
+			t.Fatalf("VerifyHypothesis failed: %v", err)
+		}
+	}
+	if err := tools.DB.AddEvidence(ctx, "e-bench", "use-redis", "test", "bench ok", "pass", "L1", "bench", "2020-01-01"); err != nil {
+		t.Fatalf("AddEvidence failed: %v", err)
+	}
+	if _, err := tools.CheckDecay("", "e-bench", "2099-12-31", "rerun scheduled"); err != nil {
+		t.Fatalf("Waiver failed: %v", err)
+	}
+
+	path, err := tools.FinalizeDecision("Cache", "use-redis", []string{"use-lru"}, "Context", "Decision", "Rationale", "Consequences", "", "", nil)
+	if err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{"## Assurance Snapshot", "### Winner: use-redis", "[use-redis R:", "| use-lru |", "| use-redis | e-bench | pass | 2020-01-01 | until 2099-12-31 by "} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected the DRR to contain %q, got:\n%s", want, data)
+		}
+	}
+
+	snaps, err := tools.DB.GetDRRSnapshots(ctx, "cache")
+	if err != nil {
+		t.Fatalf("GetDRRSnapshots failed: %v", err)
+	}
+	if len(snaps) != 2 || snaps[0].HolonID != "use-redis" || snaps[0].Role != SnapshotWinner || snaps[1].Role != SnapshotRejected {
+		t.Fatalf("Expected the winner then the rejected alternative, got %+v", snaps)
+	}
+	if !strings.Contains(snaps[0].Evidence.String, `"waived_until":"2099-12-31"`) || snaps[1].AuditTree.String != "" {
+		t.Errorf("Unexpected snapshot rows: %+v", snaps)
+	}
+
+	report, err := tools.CalculateR("use-lru")
+	if err != nil {
+		t.Fatalf("CalculateR failed: %v", err)
+	}
+	if !strings.Contains(report, "**At decision time:**") || !strings.Contains(report, "- cache (rejected, ") {
+		t.Errorf("Expected CalculateR to compare with the snapshot, got:\n%s", report)
+	}
+}
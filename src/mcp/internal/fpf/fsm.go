@@ -3,13 +3,14 @@ package fpf
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/m0n0x41d/quint-code/assurance"
+	"github.com/m0n0x41d/quint-code/db"
 )
 
 // Phase definitions
@@ -57,6 +58,11 @@ type State struct {
 	ActiveRole         RoleAssignment `json:"active_role,omitempty"`
 	LastCommit         string         `json:"last_commit,omitempty"`
 	AssuranceThreshold float64        `json:"assurance_threshold,omitempty"`
+	FormalityThreshold int            `json:"formality_threshold,omitempty"`
+	CLPenaltyProfile   string         `json:"cl_penalty_profile,omitempty"`
+	DecayProfiles      string         `json:"decay_profiles,omitempty"`
+	EvidenceWeighting  string         `json:"evidence_weighting,omitempty"`
+	StrictMode         bool           `json:"strict_mode,omitempty"`
 }
 
 // TransitionRule defines a valid state change
@@ -66,62 +72,92 @@ type TransitionRule struct {
 	Role Role
 }
 
+// transitionRules lists every valid phase change and the role that makes it
+var transitionRules = []TransitionRule{
+	{PhaseIdle, PhaseAbduction, RoleAbductor},
+	{PhaseAbduction, PhaseDeduction, RoleDeductor},
+	{PhaseDeduction, PhaseInduction, RoleInductor},
+	{PhaseInduction, PhaseDeduction, RoleDeductor},
+	{PhaseInduction, PhaseAudit, RoleAuditor},
+	{PhaseInduction, PhaseDecision, RoleDecider},
+	{PhaseAudit, PhaseDecision, RoleDecider},
+	{PhaseDecision, PhaseIdle, RoleDecider},
+	{PhaseDecision, PhaseAbduction, RoleAbductor}, // next decision cycle
+	{PhaseDecision, PhaseOperation, RoleDecider},
+}
+
 // FSM manages the state transitions
 type FSM struct {
-	State State
-	DB    *sql.DB
+	State     State
+	DB        db.Repository
+	ContextID string // Bounded context this state belongs to
 }
 
-// LoadState reads state from fpf_state table in SQLite
-func LoadState(contextID string, db *sql.DB) (*FSM, error) {
+// LoadState reads the persisted state of a bounded context
+func LoadState(contextID string, store db.Repository) (*FSM, error) {
 	fsm := &FSM{
 		State: State{
 			Phase:              PhaseIdle,
 			AssuranceThreshold: 0.8,
 		},
-		DB: db,
+		DB:        store,
+		ContextID: contextID,
 	}
 
-	if db == nil {
+	if store == nil {
 		return fsm, nil
 	}
 
-	row := db.QueryRow(`
-		SELECT active_role, active_session_id, active_role_context, last_commit, assurance_threshold
-		FROM fpf_state WHERE context_id = ?`, contextID)
-
-	var activeRole, activeSessionID, activeRoleContext, lastCommit sql.NullString
-	var threshold sql.NullFloat64
-
-	err := row.Scan(&activeRole, &activeSessionID, &activeRoleContext, &lastCommit, &threshold)
-	if err == sql.ErrNoRows {
+	row, err := store.GetState(context.Background(), contextID)
+	if errors.Is(err, sql.ErrNoRows) {
 		return fsm, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to load state: %w", err)
 	}
 
-	if activeRole.Valid {
+	if row.ActiveRole.Valid {
 		fsm.State.ActiveRole = RoleAssignment{
-			Role:      Role(activeRole.String),
-			SessionID: activeSessionID.String,
-			Context:   activeRoleContext.String,
+			Role:      Role(row.ActiveRole.String),
+			SessionID: row.ActiveSessionID.String,
+			Context:   row.ActiveRoleContext.String,
 		}
 	}
-	if lastCommit.Valid {
-		fsm.State.LastCommit = lastCommit.String
+	if row.LastCommit.Valid {
+		fsm.State.LastCommit = row.LastCommit.String
+	}
+	if row.AssuranceThreshold.Valid {
+		fsm.State.AssuranceThreshold = row.AssuranceThreshold.Float64
+	}
+	if row.FormalityThreshold.Valid {
+		fsm.State.FormalityThreshold = int(row.FormalityThreshold.Int64)
 	}
-	if threshold.Valid {
-		fsm.State.AssuranceThreshold = threshold.Float64
+	if row.ClPenaltyProfile.Valid {
+		fsm.State.CLPenaltyProfile = row.ClPenaltyProfile.String
 	}
+	if row.DecayProfiles.Valid {
+		fsm.State.DecayProfiles = row.DecayProfiles.String
+	}
+	if row.EvidenceWeighting.Valid {
+		fsm.State.EvidenceWeighting = row.EvidenceWeighting.String
+	}
+	fsm.State.StrictMode = row.StrictMode.Valid && row.StrictMode.Int64 != 0
 
 	return fsm, nil
 }
 
+// ActiveContext returns the bounded context of this state, defaulting to "default"
+func (f *FSM) ActiveContext() string {
+	if f == nil || f.ContextID == "" {
+		return db.DefaultContextID
+	}
+	return f.ContextID
+}
+
 // GetPhase returns the current phase, deriving from DB if available
 func (f *FSM) GetPhase() Phase {
 	if f.DB != nil {
-		return f.DerivePhase("default")
+		return f.DerivePhase(f.ActiveContext())
 	}
 	return f.State.Phase
 }
@@ -132,21 +168,15 @@ func (f *FSM) DerivePhase(contextID string) Phase {
 		return PhaseIdle
 	}
 
-	rows, err := f.DB.QueryContext(context.Background(),
-		"SELECT layer, COUNT(*) as count FROM holons WHERE context_id = ? GROUP BY layer", contextID)
+	ctx := context.Background()
+	rows, err := f.DB.CountHolonsByLayer(ctx, contextID)
 	if err != nil {
 		return PhaseIdle
 	}
-	defer rows.Close() //nolint:errcheck
 
 	counts := make(map[string]int64)
-	for rows.Next() {
-		var layer string
-		var count int64
-		if err := rows.Scan(&layer, &count); err != nil {
-			continue
-		}
-		counts[layer] = count
+	for _, r := range rows {
+		counts[r.Layer] = r.Count
 	}
 
 	l0 := counts["L0"]
@@ -158,12 +188,11 @@ func (f *FSM) DerivePhase(contextID string) Phase {
 		return PhaseIdle
 	}
 
-	row := f.DB.QueryRowContext(context.Background(),
-		"SELECT layer FROM holons WHERE context_id = ? ORDER BY updated_at DESC LIMIT 1", contextID)
-	var latestLayer string
-	if err := row.Scan(&latestLayer); err != nil {
+	latest, err := f.DB.GetLatestHolonByContext(ctx, contextID)
+	if err != nil {
 		return PhaseIdle
 	}
+	latestLayer := latest.Layer
 
 	switch latestLayer {
 	case "L0":
@@ -188,30 +217,29 @@ func (f *FSM) DerivePhase(contextID string) Phase {
 	return PhaseAbduction
 }
 
-// SaveState writes state to fpf_state table in SQLite
+// SaveState persists the state of a bounded context
 func (f *FSM) SaveState(contextID string) error {
 	if f.DB == nil {
 		return fmt.Errorf("database connection required for SaveState")
 	}
 
-	_, err := f.DB.Exec(`
-		INSERT INTO fpf_state (context_id, active_role, active_session_id, active_role_context, last_commit, assurance_threshold, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(context_id) DO UPDATE SET
-			active_role = excluded.active_role,
-			active_session_id = excluded.active_session_id,
-			active_role_context = excluded.active_role_context,
-			last_commit = excluded.last_commit,
-			assurance_threshold = excluded.assurance_threshold,
-			updated_at = excluded.updated_at`,
-		contextID,
-		string(f.State.ActiveRole.Role),
-		f.State.ActiveRole.SessionID,
-		f.State.ActiveRole.Context,
-		f.State.LastCommit,
-		f.State.AssuranceThreshold,
-		time.Now().UTC(),
-	)
+	strictMode := int64(0)
+	if f.State.StrictMode {
+		strictMode = 1
+	}
+	err := f.DB.SaveState(context.Background(), db.FpfState{
+		ContextID:          contextID,
+		ActiveRole:         sql.NullString{String: string(f.State.ActiveRole.Role), Valid: true},
+		ActiveSessionID:    sql.NullString{String: f.State.ActiveRole.SessionID, Valid: true},
+		ActiveRoleContext:  sql.NullString{String: f.State.ActiveRole.Context, Valid: true},
+		LastCommit:         sql.NullString{String: f.State.LastCommit, Valid: true},
+		AssuranceThreshold: sql.NullFloat64{Float64: f.State.AssuranceThreshold, Valid: true},
+		FormalityThreshold: sql.NullInt64{Int64: int64(f.State.FormalityThreshold), Valid: true},
+		ClPenaltyProfile:   sql.NullString{String: f.State.CLPenaltyProfile, Valid: true},
+		StrictMode:         sql.NullInt64{Int64: strictMode, Valid: true},
+		DecayProfiles:      sql.NullString{String: f.State.DecayProfiles, Valid: true},
+		EvidenceWeighting:  sql.NullString{String: f.State.EvidenceWeighting, Valid: true},
+	})
 	if err != nil {
 		return fmt.Errorf("failed to save state: %w", err)
 	}
@@ -226,14 +254,77 @@ func (f *FSM) GetAssuranceThreshold() float64 {
 	return f.State.AssuranceThreshold
 }
 
+// GetFormalityThreshold returns the minimum F required for Operation, defaulting to F0
+func (f *FSM) GetFormalityThreshold() int {
+	if f.State.FormalityThreshold < assurance.MinFormality {
+		return assurance.MinFormality
+	}
+	return f.State.FormalityThreshold
+}
+
+// GetCLPenaltyProfile resolves the configured Φ(CL) profile, defaulting to
+// the FPF B.1.3 normative table
+func (f *FSM) GetCLPenaltyProfile() assurance.CLPenaltyProfile {
+	if f == nil || f.State.CLPenaltyProfile == "" {
+		return assurance.DefaultCLPenaltyProfile
+	}
+	phi, err := assurance.ParseCLPenaltyProfile(f.State.CLPenaltyProfile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid cl_penalty_profile %q, using %s: %v\n", f.State.CLPenaltyProfile, assurance.ProfileNormative, err)
+		return assurance.DefaultCLPenaltyProfile
+	}
+	return phi
+}
+
+// GetDecayProfiles resolves the configured evidence decay profiles,
+// defaulting to a step at valid_until for every type
+func (f *FSM) GetDecayProfiles() assurance.DecayProfiles {
+	if f == nil || f.State.DecayProfiles == "" {
+		return assurance.DefaultDecayProfiles
+	}
+	decay, err := assurance.ParseDecayProfiles(f.State.DecayProfiles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid decay_profiles %q, using %s: %v\n", f.State.DecayProfiles, assurance.DecayStep, err)
+		return assurance.DefaultDecayProfiles
+	}
+	return decay
+}
+
+// GetEvidenceWeighting resolves the configured evidence weighting,
+// defaulting to the plain mean
+func (f *FSM) GetEvidenceWeighting() assurance.EvidenceWeighting {
+	if f == nil || f.State.EvidenceWeighting == "" {
+		return assurance.DefaultEvidenceWeighting
+	}
+	weighting, err := assurance.ParseEvidenceWeighting(f.State.EvidenceWeighting)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid evidence_weighting %q, using %s: %v\n", f.State.EvidenceWeighting, assurance.AggregateMean, err)
+		return assurance.DefaultEvidenceWeighting
+	}
+	return weighting
+}
+
+// NewCalculator returns an assurance calculator using the configured Φ(CL),
+// decay profiles and evidence weighting
+func (f *FSM) NewCalculator(store assurance.Store) *assurance.Calculator {
+	calc := assurance.NewWithProfile(store, f.GetCLPenaltyProfile())
+	calc.Decay = f.GetDecayProfiles()
+	calc.Weighting = f.GetEvidenceWeighting()
+	return calc
+}
+
 // CanTransition checks if a role can move the system to a target phase
 func (f *FSM) CanTransition(target Phase, assignment RoleAssignment, evidence *EvidenceStub) (bool, string) {
+	return f.CanTransitionFrom(f.GetPhase(), target, assignment, evidence)
+}
+
+// CanTransitionFrom checks if a role can move the system from currentPhase
+// to a target phase
+func (f *FSM) CanTransitionFrom(currentPhase, target Phase, assignment RoleAssignment, evidence *EvidenceStub) (bool, string) {
 	if assignment.Role == "" {
 		return false, "Role is required"
 	}
 
-	currentPhase := f.GetPhase()
-
 	if currentPhase == target {
 		if isValidRoleForPhase(currentPhase, assignment.Role) {
 			return true, "OK"
@@ -241,20 +332,8 @@ func (f *FSM) CanTransition(target Phase, assignment RoleAssignment, evidence *E
 		return false, fmt.Sprintf("Role %s is not active in %s phase", assignment.Role, currentPhase)
 	}
 
-	valid := []TransitionRule{
-		{PhaseIdle, PhaseAbduction, RoleAbductor},
-		{PhaseAbduction, PhaseDeduction, RoleDeductor},
-		{PhaseDeduction, PhaseInduction, RoleInductor},
-		{PhaseInduction, PhaseDeduction, RoleDeductor},
-		{PhaseInduction, PhaseAudit, RoleAuditor},
-		{PhaseInduction, PhaseDecision, RoleDecider},
-		{PhaseAudit, PhaseDecision, RoleDecider},
-		{PhaseDecision, PhaseIdle, RoleDecider},
-		{PhaseDecision, PhaseOperation, RoleDecider},
-	}
-
 	isValidTransition := false
-	for _, rule := range valid {
+	for _, rule := range transitionRules {
 		if rule.From == currentPhase && rule.To == target {
 			if rule.Role == assignment.Role {
 				isValidTransition = true
@@ -276,7 +355,7 @@ func (f *FSM) CanTransition(target Phase, assignment RoleAssignment, evidence *E
 			return false, "Transition to Operation requires a specific Holon ID in evidence stub"
 		}
 
-		calc := assurance.New(f.DB)
+		calc := f.NewCalculator(f.DB)
 		report, err := calc.CalculateReliability(context.Background(), evidence.HolonID)
 		if err != nil {
 			return false, fmt.Sprintf("Failed to calculate assurance: %v", err)
@@ -286,12 +365,26 @@ func (f *FSM) CanTransition(target Phase, assignment RoleAssignment, evidence *E
 		if report.FinalScore < threshold {
 			return false, fmt.Sprintf("Transition Denied: Reliability (%.2f) is below threshold (%.2f). Weakest link: %s", report.FinalScore, threshold, report.WeakestLink)
 		}
+
+		formalityThreshold := f.GetFormalityThreshold()
+		if report.Formality < formalityThreshold {
+			return false, fmt.Sprintf("Transition Denied: Formality (%s) is below threshold (%s)",
+				assurance.FormatFormality(report.Formality), assurance.FormatFormality(formalityThreshold))
+		}
+
+		if report.ClaimScope.IsEmpty() {
+			return false, fmt.Sprintf("Transition Denied: Claim scope is empty (%s); dependencies hold under disjoint conditions", report.ClaimScope)
+		}
 	}
 
 	return true, "OK"
 }
 
 func validateEvidence(fromPhase, toPhase Phase, evidence *EvidenceStub) bool {
+	// Starting a cycle or returning to Idle produces no artifact to anchor.
+	if toPhase == PhaseAbduction || toPhase == PhaseIdle {
+		return true
+	}
 	if evidence == nil || evidence.URI == "" {
 		return false
 	}
@@ -19,7 +19,7 @@ func TestLoadState(t *testing.T) {
 	defer database.Close()
 
 	// Test loading non-existent state (should initialize to IDLE)
-	fsm, err := LoadState("default", database.GetRawDB())
+	fsm, err := LoadState("default", database)
 	if err != nil {
 		t.Fatalf("LoadState failed: %v", err)
 	}
@@ -34,7 +34,7 @@ func TestLoadState(t *testing.T) {
 		t.Fatalf("SaveState failed: %v", err)
 	}
 
-	fsm2, err := LoadState("default", database.GetRawDB())
+	fsm2, err := LoadState("default", database)
 	if err != nil {
 		t.Fatalf("LoadState failed for existing state: %v", err)
 	}
@@ -57,8 +57,8 @@ func TestSaveState(t *testing.T) {
 	defer database.Close()
 
 	fsm := &FSM{
-		State: State{Phase: PhaseDeduction, AssuranceThreshold: 0.75, LastCommit: "abc123"},
-		DB:    database.GetRawDB(),
+		State: State{Phase: PhaseDeduction, AssuranceThreshold: 0.75, FormalityThreshold: 3, CLPenaltyProfile: "linear:0.6", DecayProfiles: "none", EvidenceWeighting: "bayes,class:empirical=2", LastCommit: "abc123"},
+		DB:    database,
 	}
 	err = fsm.SaveState("default")
 	if err != nil {
@@ -66,13 +66,25 @@ func TestSaveState(t *testing.T) {
 	}
 
 	// Verify data was written
-	fsm2, err := LoadState("default", database.GetRawDB())
+	fsm2, err := LoadState("default", database)
 	if err != nil {
 		t.Fatalf("LoadState failed: %v", err)
 	}
 	if fsm2.State.AssuranceThreshold != 0.75 {
 		t.Errorf("Expected threshold 0.75, got %f", fsm2.State.AssuranceThreshold)
 	}
+	if fsm2.State.FormalityThreshold != 3 {
+		t.Errorf("Expected formality threshold 3, got %d", fsm2.State.FormalityThreshold)
+	}
+	if fsm2.GetCLPenaltyProfile().Spec() != "linear:0.6" {
+		t.Errorf("Expected Φ(CL) profile linear:0.6, got %s", fsm2.GetCLPenaltyProfile())
+	}
+	if fsm2.GetDecayProfiles().Spec() != "none" {
+		t.Errorf("Expected decay profiles none, got %s", fsm2.GetDecayProfiles().Spec())
+	}
+	if calc := fsm2.NewCalculator(database); calc.Weighting.Spec() != "bayes,class:empirical=2" {
+		t.Errorf("Expected the calculator to use the saved weighting, got %s", calc.Weighting.Spec())
+	}
 	if fsm2.State.LastCommit != "abc123" {
 		t.Errorf("Expected last commit abc123, got %s", fsm2.State.LastCommit)
 	}
@@ -142,6 +154,7 @@ func TestCanTransition(t *testing.T) {
 		// So l0Dir works.
 		{"InductionToDecision", PhaseInduction, PhaseDecision, RoleDecider, l2File, true, "OK"},
 		{"DecisionToIdle", PhaseDecision, PhaseIdle, RoleDecider, "any", true, "OK"},
+		{"DecisionToAbductionNextCycle", PhaseDecision, PhaseAbduction, RoleAbductor, "", true, "OK"},
 		{"SelfLoopValid", PhaseAbduction, PhaseAbduction, RoleAbductor, "", true, "OK"},
 	}
 
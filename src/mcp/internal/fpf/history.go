@@ -0,0 +1,231 @@
+package fpf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// phaseOrder ranks the phases of a decision cycle.
+var phaseOrder = map[Phase]int{
+	PhaseIdle:      0,
+	PhaseAbduction: 1,
+	PhaseDeduction: 2,
+	PhaseInduction: 3,
+	PhaseAudit:     4,
+	PhaseDecision:  5,
+	PhaseOperation: 6,
+}
+
+// IsLoopback reports whether a transition moves backwards within a cycle.
+func IsLoopback(from, to Phase) bool {
+	return !startsNewCycle(from, to) && phaseOrder[to] < phaseOrder[from]
+}
+
+func startsNewCycle(from, to Phase) bool {
+	return (from == PhaseDecision || from == PhaseOperation) && (to == PhaseAbduction || to == PhaseIdle)
+}
+
+// currentPhase is the phase of the active context.
+func (t *Tools) currentPhase() Phase {
+	return t.contextPhase(t.ContextID())
+}
+
+// contextPhase is the phase a context last transitioned into, falling back
+// to the phase derived from holons when no history has been recorded. It is
+// the one phase that authorization, history and quint_status report.
+func (t *Tools) contextPhase(contextID string) Phase {
+	if t.DB == nil {
+		return t.FSM.GetPhase()
+	}
+	if last, err := t.DB.GetLastPhaseTransition(context.Background(), contextID); err == nil {
+		return Phase(last.ToPhase)
+	}
+	return t.FSM.DerivePhase(contextID)
+}
+
+// startPhase is the phase a tool call moves the context from. Without
+// recorded history (after rebuild-db, or on a database older than phase
+// history) the phase is derived from holons, which cannot tell a finished
+// cycle from a running one, so a proposal may always start a new cycle.
+func (t *Tools) startPhase(contextID, toolName string) Phase {
+	if toolTargetPhases[toolName] == PhaseAbduction && !t.hasPhaseHistory(contextID) {
+		return PhaseIdle
+	}
+	return t.contextPhase(contextID)
+}
+
+func (t *Tools) hasPhaseHistory(contextID string) bool {
+	if t.DB == nil {
+		return false
+	}
+	_, err := t.DB.GetLastPhaseTransition(context.Background(), contextID)
+	return err == nil
+}
+
+// RecordTransition persists an accepted phase change of a context, attributed
+// to the current caller. It is a no-op when the phase is unchanged.
+func (t *Tools) RecordTransition(contextID string, from, to Phase, toolName string, evidence *EvidenceStub, reason string) error {
+	if t.DB == nil || from == to {
+		return nil
+	}
+
+	var evidenceJSON string
+	if evidence != nil && *evidence != (EvidenceStub{}) {
+		data, err := json.Marshal(evidence)
+		if err != nil {
+			return err
+		}
+		evidenceJSON = string(data)
+	}
+
+	return t.DB.InsertPhaseTransition(context.Background(), contextID, string(from), string(to),
+		string(t.Caller.Role), t.Caller.SessionID, toolName, evidenceJSON, reason)
+}
+
+// recordToolTransition records the phase change a successful mutating tool
+// call caused in the context it acted on. Phase-moving tools enter their
+// target phase; other tools are credited with whatever phase change they
+// caused in the holons, derivedBefore being the phase derived from holons
+// before the call. A decision is followed by the reset to IDLE.
+func (t *Tools) recordToolTransition(contextID, toolName string, args map[string]string, from, derivedBefore Phase, evidence *EvidenceStub) {
+	to, ok := toolTargetPhases[toolName]
+	if !ok {
+		to = from
+		if derived := t.FSM.DerivePhase(contextID); derived != derivedBefore {
+			to = derived
+		}
+	}
+	if err := t.RecordTransition(contextID, from, to, toolName, evidence, transitionReason(toolName, args)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record phase transition: %v\n", err)
+	}
+	if to == PhaseDecision {
+		if err := t.RecordTransition(contextID, PhaseDecision, PhaseIdle, toolName, nil, "decision closes the cycle"); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record phase transition: %v\n", err)
+		}
+	}
+}
+
+func transitionReason(toolName string, args map[string]string) string {
+	switch toolName {
+	case "quint_propose":
+		return fmt.Sprintf("proposed %q", args["title"])
+	case "quint_verify":
+		return fmt.Sprintf("verified %s (%s)", args["hypothesis_id"], args["verdict"])
+	case "quint_test":
+		return fmt.Sprintf("tested %s (%s)", args["hypothesis_id"], args["verdict"])
+	case "quint_ingest_results":
+		return fmt.Sprintf("ingested %s", args["path"])
+	case "quint_audit":
+		return fmt.Sprintf("audited %s", args["hypothesis_id"])
+	case "quint_decide":
+		return fmt.Sprintf("decided %s", args["winner_id"])
+	case "quint_check_decay":
+		if args["deprecate"] != "" {
+			return fmt.Sprintf("deprecated %s", args["deprecate"])
+		}
+	}
+	return toolName
+}
+
+// History renders the phase transitions of a context, oldest first, followed
+// by the path each decision cycle took. limit keeps only the most recent
+// transitions when positive.
+func (t *Tools) History(contextID string, limit int) (string, error) {
+	defer t.RecordWork("History", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	if contextID == "" {
+		contextID = t.ContextID()
+	}
+
+	transitions, err := t.DB.GetPhaseTransitions(context.Background(), contextID)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("## Phase History: %s\n\n", contextID))
+	if len(transitions) == 0 {
+		out.WriteString("No phase transitions recorded yet.\n")
+		return out.String(), nil
+	}
+
+	shown := transitions
+	if limit > 0 && len(shown) > limit {
+		shown = shown[len(shown)-limit:]
+	}
+
+	out.WriteString("| # | When | Transition | Role | Session | Tool | Reason |\n")
+	out.WriteString("|---|------|------------|------|---------|------|--------|\n")
+	for _, tr := range shown {
+		arrow := "→"
+		if IsLoopback(Phase(tr.FromPhase), Phase(tr.ToPhase)) {
+			arrow = "↺"
+		}
+		when := ""
+		if tr.CreatedAt.Valid {
+			when = tr.CreatedAt.Time.Format("2006-01-02 15:04")
+		}
+		out.WriteString(fmt.Sprintf("| %d | %s | %s %s %s | %s | %s | %s | %s |\n",
+			tr.ID, when, tr.FromPhase, arrow, tr.ToPhase,
+			orDash(tr.Role.String), orDash(tr.SessionID.String), orDash(tr.ToolName.String), orDash(tr.Reason.String)))
+	}
+
+	out.WriteString("\n### Cycles\n\n")
+	for i, cycle := range splitCycles(transitions) {
+		loopbacks := 0
+		var path strings.Builder
+		path.WriteString(cycle[0].FromPhase)
+		for _, tr := range cycle {
+			if IsLoopback(Phase(tr.FromPhase), Phase(tr.ToPhase)) {
+				loopbacks++
+				path.WriteString(" ↺ ")
+			} else {
+				path.WriteString(" → ")
+			}
+			path.WriteString(tr.ToPhase)
+		}
+		out.WriteString(fmt.Sprintf("%d. %s", i+1, path.String()))
+		if loopbacks > 0 {
+			out.WriteString(fmt.Sprintf(" (%d loopback(s))", loopbacks))
+		}
+		out.WriteString("\n")
+	}
+
+	return out.String(), nil
+}
+
+// splitCycles groups transitions into decision cycles.
+func splitCycles(transitions []db.PhaseTransition) [][]db.PhaseTransition {
+	var cycles [][]db.PhaseTransition
+	var current []db.PhaseTransition
+	for _, tr := range transitions {
+		if len(current) > 0 && startsNewCycle(Phase(tr.FromPhase), Phase(tr.ToPhase)) && Phase(tr.ToPhase) != PhaseIdle {
+			cycles = append(cycles, current)
+			current = nil
+		}
+		current = append(current, tr)
+		if Phase(tr.ToPhase) == PhaseIdle {
+			cycles = append(cycles, current)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		cycles = append(cycles, current)
+	}
+	return cycles
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,182 @@
+package fpf
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		from, to Phase
+		want     bool
+	}{
+		{PhaseAbduction, PhaseDeduction, false},
+		{PhaseInduction, PhaseDeduction, true},
+		{PhaseDeduction, PhaseAbduction, true},
+		{PhaseDecision, PhaseAbduction, false}, // new cycle
+		{PhaseDecision, PhaseIdle, false},
+	}
+	for _, tt := range tests {
+		if got := IsLoopback(tt.from, tt.to); got != tt.want {
+			t.Errorf("IsLoopback(%s, %s) = %t, want %t", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestHistory_Empty(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	out, err := tools.History("", 0)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if !strings.Contains(out, "No phase transitions recorded") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+}
+
+func TestHandleToolsCall_RecordsPhaseTransitions(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	call := func(name, args string) {
+		t.Helper()
+		params := json.RawMessage(`{"name":"` + name + `","arguments":` + args + `}`)
+		resp := server.Handle(nil, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: params})
+		result := resp.Result.(CallToolResult)
+		if result.IsError {
+			t.Fatalf("%s failed: %s", name, result.Content[0].Text)
+		}
+	}
+
+	call("quint_propose", `{"title":"Alpha","content":"c","kind":"system","scope":"global","rationale":"{}","role":"Abductor","session_id":"s1"}`)
+	call("quint_propose", `{"title":"Beta","content":"c","kind":"system","scope":"global","rationale":"{}"}`)
+	call("quint_verify", `{"hypothesis_id":"alpha","checks_json":"{}","verdict":"PASS","role":"Deductor","session_id":"s2"}`)
+	call("quint_test", `{"hypothesis_id":"alpha","test_type":"internal","result":"ok","verdict":"PASS"}`)
+	call("quint_verify", `{"hypothesis_id":"beta","checks_json":"{}","verdict":"PASS"}`)
+	call("quint_status", `{}`)
+
+	transitions, err := tools.DB.GetPhaseTransitions(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetPhaseTransitions failed: %v", err)
+	}
+
+	var got []string
+	for _, tr := range transitions {
+		got = append(got, tr.FromPhase+">"+tr.ToPhase)
+	}
+	want := []string{"IDLE>ABDUCTION", "ABDUCTION>DEDUCTION", "DEDUCTION>INDUCTION", "INDUCTION>DEDUCTION"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("Expected transitions %v, got %v", want, got)
+	}
+
+	first := transitions[0]
+	if first.Role.String != "Abductor" || first.SessionID.String != "s1" || first.ToolName.String != "quint_propose" {
+		t.Errorf("Unexpected attribution on first transition: %+v", first)
+	}
+
+	out, err := tools.History("", 0)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if !strings.Contains(out, "IDLE → ABDUCTION → DEDUCTION → INDUCTION ↺ DEDUCTION (1 loopback(s))") {
+		t.Errorf("Expected cycle path with loopback, got:\n%s", out)
+	}
+
+	out, _ = tools.History("", 1)
+	if strings.Contains(out, "| IDLE → ABDUCTION |") || !strings.Contains(out, "INDUCTION ↺ DEDUCTION | - | - | quint_verify | verified beta (PASS) |") {
+		t.Errorf("Expected limit to keep only the latest transition, got:\n%s", out)
+	}
+}
+
+func TestHandleToolsCall_RecordsDecisionReset(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	call := func(name, args string) string {
+		t.Helper()
+		params := json.RawMessage(`{"name":"` + name + `","arguments":` + args + `}`)
+		resp := server.Handle(nil, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: params})
+		result := resp.Result.(CallToolResult)
+		if result.IsError {
+			t.Fatalf("%s failed: %s", name, result.Content[0].Text)
+		}
+		return result.Content[0].Text
+	}
+
+	call("quint_propose", `{"title":"Alpha","content":"c","kind":"system","scope":"global","rationale":"{}"}`)
+	call("quint_verify", `{"hypothesis_id":"alpha","checks_json":"{}","verdict":"PASS"}`)
+	call("quint_test", `{"hypothesis_id":"alpha","test_type":"internal","result":"ok","verdict":"PASS"}`)
+	call("quint_decide", `{"title":"Use Alpha","winner_id":"alpha","context":"c","decision":"d","rationale":"r","consequences":"q"}`)
+
+	if phase := call("quint_status", `{}`); phase != string(PhaseIdle) {
+		t.Errorf("Expected quint_status to report IDLE after a decision, got %s", phase)
+	}
+	if phase := tools.currentPhase(); phase != PhaseIdle {
+		t.Errorf("Expected current phase IDLE after a decision, got %s", phase)
+	}
+
+	// A non-phase tool after the reset must not move the phase back.
+	call("quint_record_context", `{"vocabulary":"v","invariants":"i"}`)
+
+	transitions, err := tools.DB.GetPhaseTransitions(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetPhaseTransitions failed: %v", err)
+	}
+	var got []string
+	for _, tr := range transitions {
+		got = append(got, tr.FromPhase+">"+tr.ToPhase)
+	}
+	want := []string{"IDLE>ABDUCTION", "ABDUCTION>DEDUCTION", "DEDUCTION>INDUCTION", "INDUCTION>DECISION", "DECISION>IDLE"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("Expected transitions %v, got %v", want, got)
+	}
+
+	out, err := tools.History("", 0)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if !strings.Contains(out, "1. IDLE → ABDUCTION → DEDUCTION → INDUCTION → DECISION → IDLE\n") {
+		t.Errorf("Expected the reset to close the cycle, got:\n%s", out)
+	}
+}
+
+func TestHandleToolsCall_RecordsTransitionsInTargetContext(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+	ctx := context.Background()
+
+	if _, err := tools.ManageContext("create", "payments", ""); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	call := func(name string, args map[string]interface{}) {
+		t.Helper()
+		if result := server.CallTool(name, args); result.IsError {
+			t.Fatalf("%s failed: %s", name, result.Content[0].Text)
+		}
+	}
+	call("quint_propose", map[string]interface{}{"title": "Alpha", "content": "c", "kind": "system", "scope": "global", "rationale": "{}"})
+	call("quint_propose", map[string]interface{}{"title": "Stripe", "content": "c", "kind": "system", "scope": "global", "rationale": "{}", "context": "payments"})
+	call("quint_verify", map[string]interface{}{"hypothesis_id": "stripe", "checks_json": "{}", "verdict": "PASS"})
+
+	paths := func(contextID string) string {
+		transitions, err := tools.DB.GetPhaseTransitions(ctx, contextID)
+		if err != nil {
+			t.Fatalf("GetPhaseTransitions failed: %v", err)
+		}
+		var got []string
+		for _, tr := range transitions {
+			got = append(got, tr.FromPhase+">"+tr.ToPhase)
+		}
+		return strings.Join(got, " ")
+	}
+	if got := paths("default"); got != "IDLE>ABDUCTION" {
+		t.Errorf("Expected default to record only its own proposal, got %q", got)
+	}
+	if got := paths("payments"); got != "IDLE>ABDUCTION ABDUCTION>DEDUCTION" {
+		t.Errorf("Expected payments to record its proposal and verification, got %q", got)
+	}
+}
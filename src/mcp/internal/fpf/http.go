@@ -0,0 +1,331 @@
+package fpf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionHeader carries the session ID assigned on initialize (MCP Streamable HTTP).
+const SessionHeader = "Mcp-Session-Id"
+
+const (
+	maxRequestBody    = 4 << 20
+	sessionEventQueue = 64
+)
+
+// DefaultSessionIdleTimeout is how long a session may go without a request
+// before it expires.
+const DefaultSessionIdleTimeout = 30 * time.Minute
+
+// HTTPTransport serves the MCP Streamable HTTP transport on a single endpoint:
+// POST carries client messages, GET opens an SSE stream for server-initiated
+// notifications, DELETE terminates the session. All sessions share one Server,
+// so several agents can work against the same .quint/quint.db.
+type HTTPTransport struct {
+	server *Server
+
+	// IdleTimeout ends sessions, and closes their streams, once they have
+	// gone this long without a request.
+	IdleTimeout time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*httpSession
+}
+
+type httpSession struct {
+	*Session
+	events chan []byte
+	done   chan struct{}
+	idle   *time.Timer
+}
+
+func NewHTTPTransport(s *Server) *HTTPTransport {
+	return &HTTPTransport{
+		server:      s,
+		IdleTimeout: DefaultSessionIdleTimeout,
+		sessions:    make(map[string]*httpSession),
+	}
+}
+
+func (h *HTTPTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isAllowedOrigin(r) {
+		http.Error(w, "Forbidden: origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodGet:
+		h.handleStream(w, r)
+	case http.MethodDelete:
+		h.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *HTTPTransport) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	messages, batch, err := splitMessages(body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse(nil, -32700, "Parse error"))
+		return
+	}
+
+	var requests []JSONRPCRequest
+	isInitialize := false
+	for _, raw := range messages {
+		var req JSONRPCRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse(nil, -32700, "Parse error"))
+			return
+		}
+		if req.Method == "" {
+			// A response to a server-initiated request; none are issued yet.
+			continue
+		}
+		if req.Method == "initialize" {
+			isInitialize = true
+		}
+		requests = append(requests, req)
+	}
+
+	var sess *httpSession
+	if isInitialize {
+		if len(messages) > 1 {
+			writeJSON(w, http.StatusBadRequest, errorResponse(nil, -32600, "initialize must not be part of a batch"))
+			return
+		}
+		sess = h.newSession()
+		w.Header().Set(SessionHeader, sess.ID)
+	} else {
+		var status int
+		sess, status = h.lookupSession(r)
+		if sess == nil {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+	}
+
+	var responses []*JSONRPCResponse
+	for _, req := range requests {
+		if resp := h.server.Handle(sess.Session, req); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var payload interface{} = responses[0]
+	if batch {
+		payload = responses
+	}
+
+	accept := r.Header.Get("Accept")
+	if !strings.Contains(accept, "application/json") && strings.Contains(accept, "text/event-stream") {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		writeEvent(w, data)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, payload)
+}
+
+func (h *HTTPTransport) handleStream(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		http.Error(w, "Accept must include text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+
+	sess, status := h.lookupSession(r)
+	if sess == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set(SessionHeader, sess.ID)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case data := <-sess.events:
+			writeEvent(w, data)
+			flusher.Flush()
+		case <-sess.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (h *HTTPTransport) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(SessionHeader)
+	if id == "" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if !h.endSession(id) {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// endSession removes a session and closes its streams. It reports false when
+// the session was already gone, so concurrent DELETEs and expiry end a
+// session exactly once.
+func (h *HTTPTransport) endSession(id string) bool {
+	h.mu.Lock()
+	sess, ok := h.sessions[id]
+	if ok {
+		delete(h.sessions, id)
+		sess.idle.Stop()
+		close(sess.done)
+	}
+	h.mu.Unlock()
+
+	if ok {
+		h.server.DropSession(sess.Session)
+	}
+	return ok
+}
+
+func (h *HTTPTransport) newSession() *httpSession {
+	sess := &httpSession{
+		Session: &Session{ID: uuid.New().String()},
+		events:  make(chan []byte, sessionEventQueue),
+		done:    make(chan struct{}),
+	}
+	sess.notify = func(n JSONRPCNotification) {
+		data, err := json.Marshal(n)
+		if err != nil {
+			return
+		}
+		select {
+		case sess.events <- data:
+		default:
+			// No stream is draining the queue; drop rather than block dispatch.
+		}
+	}
+
+	h.mu.Lock()
+	sess.idle = time.AfterFunc(h.IdleTimeout, func() { h.endSession(sess.ID) })
+	h.sessions[sess.ID] = sess
+	h.mu.Unlock()
+	return sess
+}
+
+// lookupSession resolves the Mcp-Session-Id header and restarts the session's
+// idle timer. A missing header is a 400 and an unknown, terminated or expired
+// session a 404, per the transport spec.
+func (h *HTTPTransport) lookupSession(r *http.Request) (*httpSession, int) {
+	id := r.Header.Get(SessionHeader)
+	if id == "" {
+		return nil, http.StatusBadRequest
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sess, ok := h.sessions[id]
+	if !ok {
+		return nil, http.StatusNotFound
+	}
+	sess.idle.Reset(h.IdleTimeout)
+	return sess, 0
+}
+
+func splitMessages(body []byte) ([]json.RawMessage, bool, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, false, fmt.Errorf("empty body")
+	}
+
+	if trimmed[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			return nil, true, err
+		}
+		if len(batch) == 0 {
+			return nil, true, fmt.Errorf("empty batch")
+		}
+		return batch, true, nil
+	}
+
+	return []json.RawMessage{trimmed}, false, nil
+}
+
+// isAllowedOrigin guards against DNS rebinding: browsers always send Origin,
+// and it must point at loopback or at the host being served.
+func isAllowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	host := u.Hostname()
+	switch host {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+
+	reqHost := r.Host
+	if h, _, err := net.SplitHostPort(reqHost); err == nil {
+		reqHost = h
+	}
+	return host == reqHost
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write HTTP response: %v\n", err)
+	}
+}
+
+func writeEvent(w io.Writer, data []byte) {
+	_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+}
@@ -0,0 +1,291 @@
+package fpf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sessionCount(h *HTTPTransport) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.sessions)
+}
+
+func postMCP(t *testing.T, url, sessionID, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set(SessionHeader, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	return resp
+}
+
+func setupHTTPTransport(t *testing.T) (*HTTPTransport, *httptest.Server) {
+	tools, _, _ := setupTools(t)
+	transport := NewHTTPTransport(NewServer(tools))
+	srv := httptest.NewServer(transport)
+	t.Cleanup(srv.Close)
+	return transport, srv
+}
+
+func initializeSession(t *testing.T, url string) string {
+	t.Helper()
+	resp := postMCP(t, url, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("initialize: expected 200, got %d", resp.StatusCode)
+	}
+	sessionID := resp.Header.Get(SessionHeader)
+	if sessionID == "" {
+		t.Fatal("initialize: expected Mcp-Session-Id header")
+	}
+
+	var rpc JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpc); err != nil {
+		t.Fatalf("Failed to decode initialize response: %v", err)
+	}
+	result, _ := rpc.Result.(map[string]interface{})
+	if result["protocolVersion"] != "2025-03-26" {
+		t.Errorf("Expected negotiated protocol 2025-03-26, got %v", result["protocolVersion"])
+	}
+	return sessionID
+}
+
+func TestHTTPTransport_SessionLifecycle(t *testing.T) {
+	transport, srv := setupHTTPTransport(t)
+
+	sessionID := initializeSession(t, srv.URL)
+	if sessionCount(transport) != 1 {
+		t.Errorf("Expected 1 session, got %d", sessionCount(transport))
+	}
+
+	resp := postMCP(t, srv.URL, sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Notification: expected 202, got %d", resp.StatusCode)
+	}
+
+	resp = postMCP(t, srv.URL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	var rpc JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpc); err != nil {
+		t.Fatalf("Failed to decode tools/list: %v", err)
+	}
+	resp.Body.Close()
+	if rpc.Error != nil {
+		t.Fatalf("tools/list returned error: %v", rpc.Error.Message)
+	}
+	if !strings.Contains(mustMarshal(t, rpc.Result), "quint_propose") {
+		t.Error("tools/list should include quint_propose")
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL, nil)
+	req.Header.Set(SessionHeader, sessionID)
+	delResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE failed: %v", err)
+	}
+	delResp.Body.Close()
+	if delResp.StatusCode != http.StatusNoContent {
+		t.Errorf("DELETE: expected 204, got %d", delResp.StatusCode)
+	}
+
+	resp = postMCP(t, srv.URL, sessionID, `{"jsonrpc":"2.0","id":3,"method":"tools/list"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Terminated session: expected 404, got %d", resp.StatusCode)
+	}
+
+	delResp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE failed: %v", err)
+	}
+	delResp.Body.Close()
+	if delResp.StatusCode != http.StatusNotFound {
+		t.Errorf("Second DELETE: expected 404, got %d", delResp.StatusCode)
+	}
+}
+
+func TestHTTPTransport_IdleSessionExpires(t *testing.T) {
+	transport, srv := setupHTTPTransport(t)
+	transport.IdleTimeout = 200 * time.Millisecond
+	sessionID := initializeSession(t, srv.URL)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(SessionHeader, sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the idle session's stream to close")
+	}
+	if sessionCount(transport) != 0 {
+		t.Errorf("Expected the idle session to expire, got %d sessions", sessionCount(transport))
+	}
+
+	post := postMCP(t, srv.URL, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	post.Body.Close()
+	if post.StatusCode != http.StatusNotFound {
+		t.Errorf("Expired session: expected 404, got %d", post.StatusCode)
+	}
+}
+
+func TestHTTPTransport_RejectsMissingSession(t *testing.T) {
+	_, srv := setupHTTPTransport(t)
+
+	resp := postMCP(t, srv.URL, "", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 without session, got %d", resp.StatusCode)
+	}
+
+	resp = postMCP(t, srv.URL, "no-such-session", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown session, got %d", resp.StatusCode)
+	}
+}
+
+func TestHTTPTransport_Batch(t *testing.T) {
+	_, srv := setupHTTPTransport(t)
+	sessionID := initializeSession(t, srv.URL)
+
+	resp := postMCP(t, srv.URL, sessionID, `[
+		{"jsonrpc":"2.0","id":10,"method":"tools/list"},
+		{"jsonrpc":"2.0","method":"notifications/initialized"},
+		{"jsonrpc":"2.0","id":11,"method":"no/such/method"}
+	]`)
+	defer resp.Body.Close()
+
+	var batch []JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		t.Fatalf("Failed to decode batch: %v", err)
+	}
+	if len(batch) != 2 {
+		t.Fatalf("Expected 2 responses (notification has none), got %d", len(batch))
+	}
+	if batch[1].Error == nil || batch[1].Error.Code != -32601 {
+		t.Errorf("Expected method-not-found for second request, got %+v", batch[1].Error)
+	}
+}
+
+func TestHTTPTransport_SSENotifications(t *testing.T) {
+	transport, srv := setupHTTPTransport(t)
+	sessionID := initializeSession(t, srv.URL)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(SessionHeader, sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream, got %q", ct)
+	}
+
+	transport.mu.Lock()
+	sess := transport.sessions[sessionID]
+	transport.mu.Unlock()
+	sess.Notify("notifications/message", map[string]string{"data": "hello"})
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+				lines <- strings.TrimPrefix(line, "data: ")
+				return
+			}
+		}
+	}()
+
+	select {
+	case data := <-lines:
+		if !strings.Contains(data, "notifications/message") || !strings.Contains(data, "hello") {
+			t.Errorf("Unexpected SSE payload: %s", data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for SSE notification")
+	}
+}
+
+func TestHTTPTransport_RejectsForeignOrigin(t *testing.T) {
+	_, srv := setupHTTPTransport(t)
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+	req.Header.Set("Origin", "http://evil.example.com")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 for foreign origin, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_ServeStdio(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	in := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}
+{"jsonrpc":"2.0","method":"notifications/initialized"}
+not json
+{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"quint_status","arguments":{}}}
+`)
+	var out bytes.Buffer
+	server.Serve(in, &out)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 responses (initialize, parse error, tools/call), got %d: %s", len(lines), out.String())
+	}
+	if !strings.Contains(lines[0], `"protocolVersion":"2024-11-05"`) {
+		t.Errorf("Expected default protocol version, got %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "-32700") {
+		t.Errorf("Expected parse error, got %s", lines[1])
+	}
+	if !strings.Contains(lines[2], `"id":2`) {
+		t.Errorf("Expected tools/call response with id 2, got %s", lines[2])
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,398 @@
+package fpf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Test report formats.
+const (
+	ReportFormatAuto   = "auto"
+	ReportFormatGoJSON = "go-json"
+	ReportFormatJUnit  = "junit"
+)
+
+// TestOutcome is the final outcome of one test case.
+type TestOutcome string
+
+const (
+	TestPass TestOutcome = "pass"
+	TestFail TestOutcome = "fail"
+	TestSkip TestOutcome = "skip"
+)
+
+// TestCaseResult is one test case from a report.
+type TestCaseResult struct {
+	Name    string
+	Suite   string // Go package or JUnit classname
+	Outcome TestOutcome
+}
+
+// FullName qualifies the test name with its suite.
+func (r TestCaseResult) FullName() string {
+	if r.Suite == "" {
+		return r.Name
+	}
+	return r.Suite + "." + r.Name
+}
+
+// TestReport is a parsed test report.
+type TestReport struct {
+	Format string
+	Cases  []TestCaseResult
+}
+
+// ParseTestReport parses a `go test -json` stream or a JUnit XML report.
+// With ReportFormatAuto (or ""), the format is detected from the content.
+func ParseTestReport(data []byte, format string) (*TestReport, error) {
+	if format == "" || format == ReportFormatAuto {
+		trimmed := bytes.TrimSpace(data)
+		switch {
+		case bytes.HasPrefix(trimmed, []byte("<")):
+			format = ReportFormatJUnit
+		case bytes.HasPrefix(trimmed, []byte("{")), bytes.Contains(trimmed, []byte("\n{")):
+			format = ReportFormatGoJSON
+		default:
+			return nil, fmt.Errorf("cannot detect report format: expected go test -json or JUnit XML")
+		}
+	}
+
+	var cases []TestCaseResult
+	var err error
+	switch format {
+	case ReportFormatGoJSON:
+		cases, err = parseGoTestJSON(data)
+	case ReportFormatJUnit:
+		cases, err = parseJUnit(data)
+	default:
+		return nil, fmt.Errorf("unknown report format %q (expected %s, %s or %s)", format, ReportFormatAuto, ReportFormatGoJSON, ReportFormatJUnit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no test cases found in %s report", format)
+	}
+	return &TestReport{Format: format, Cases: cases}, nil
+}
+
+// goTestEvent is one line of `go test -json` output.
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+}
+
+func parseGoTestJSON(data []byte) ([]TestCaseResult, error) {
+	type key struct{ pkg, test string }
+	outcomes := map[key]TestOutcome{}
+	var order []key
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 || text[0] != '{' {
+			continue // interleaved non-JSON build output
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal(text, &ev); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if ev.Test == "" {
+			continue
+		}
+		var outcome TestOutcome
+		switch ev.Action {
+		case "pass":
+			outcome = TestPass
+		case "fail":
+			outcome = TestFail
+		case "skip":
+			outcome = TestSkip
+		default:
+			continue
+		}
+		k := key{ev.Package, ev.Test}
+		if _, seen := outcomes[k]; !seen {
+			order = append(order, k)
+		}
+		outcomes[k] = outcome
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	cases := make([]TestCaseResult, 0, len(order))
+	for _, k := range order {
+		cases = append(cases, TestCaseResult{Name: k.test, Suite: k.pkg, Outcome: outcomes[k]})
+	}
+	return cases, nil
+}
+
+// junitTestCase is a <testcase> element; suites may nest arbitrarily.
+type junitTestCase struct {
+	Name      string    `xml:"name,attr"`
+	ClassName string    `xml:"classname,attr"`
+	Failure   *struct{} `xml:"failure"`
+	Error     *struct{} `xml:"error"`
+	Skipped   *struct{} `xml:"skipped"`
+}
+
+func parseJUnit(data []byte) ([]TestCaseResult, error) {
+	var cases []TestCaseResult
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JUnit XML: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "testcase" {
+			continue
+		}
+		var tc junitTestCase
+		if err := decoder.DecodeElement(&tc, &start); err != nil {
+			return nil, fmt.Errorf("invalid JUnit testcase: %w", err)
+		}
+		outcome := TestPass
+		switch {
+		case tc.Failure != nil || tc.Error != nil:
+			outcome = TestFail
+		case tc.Skipped != nil:
+			outcome = TestSkip
+		}
+		cases = append(cases, TestCaseResult{Name: tc.Name, Suite: tc.ClassName, Outcome: outcome})
+	}
+	return cases, nil
+}
+
+// ParseTestMapping parses a hypothesis → test pattern mapping, given either
+// as a JSON object ({"hypothesis-id": "^TestFoo"}) or as
+// "hypothesis-id=^TestFoo; other-id=TestBar|TestBaz".
+func ParseTestMapping(spec string) (map[string]*regexp.Regexp, error) {
+	raw := map[string]string{}
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "{") {
+		if err := json.Unmarshal([]byte(spec), &raw); err != nil {
+			return nil, fmt.Errorf("invalid mapping JSON: %w", err)
+		}
+	} else {
+		for _, part := range strings.Split(spec, ";") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, pattern, ok := strings.Cut(part, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid mapping %q: expected hypothesis-id=pattern", part)
+			}
+			raw[strings.TrimSpace(id)] = strings.TrimSpace(pattern)
+		}
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("mapping is empty")
+	}
+
+	mapping := make(map[string]*regexp.Regexp, len(raw))
+	for id, pattern := range raw {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for %s: %w", id, err)
+		}
+		mapping[id] = re
+	}
+	return mapping, nil
+}
+
+// DeriveVerdict maps test outcomes to an evidence verdict. Skipped tests do
+// not count; ok is false when no test ran.
+func DeriveVerdict(cases []TestCaseResult) (verdict string, ok bool) {
+	passed, failed, _ := countOutcomes(cases)
+	switch {
+	case passed == 0 && failed == 0:
+		return "", false
+	case failed == 0:
+		return "pass", true
+	case passed == 0:
+		return "fail", true
+	default:
+		return "degrade", true
+	}
+}
+
+// FormatCarrierRef renders a report carrier reference: the path and the
+// SHA-256 of the content the evidence was derived from.
+func FormatCarrierRef(path string, sum []byte) string {
+	return fmt.Sprintf("%s#sha256=%s", filepath.ToSlash(path), hex.EncodeToString(sum))
+}
+
+// IngestResults records evidence for hypotheses from a test report. Either
+// hypothesisID (all tests count) or mapping must be given.
+func (t *Tools) IngestResults(reportPath, format, hypothesisID, mapping string) (string, error) {
+	defer t.RecordWork("IngestResults", time.Now())
+
+	absPath := reportPath
+	if !filepath.IsAbs(absPath) {
+		absPath = filepath.Join(t.RootDir, reportPath)
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read report: %w", err)
+	}
+	report, err := ParseTestReport(data, format)
+	if err != nil {
+		return "", err
+	}
+
+	var patterns map[string]*regexp.Regexp
+	switch {
+	case mapping != "":
+		if patterns, err = ParseTestMapping(mapping); err != nil {
+			return "", err
+		}
+	case hypothesisID != "":
+		patterns = map[string]*regexp.Regexp{hypothesisID: regexp.MustCompile(".*")}
+	default:
+		return "", fmt.Errorf("hypothesis_id or mapping is required")
+	}
+
+	relPath := reportPath
+	if rel, err := filepath.Rel(t.RootDir, absPath); err == nil && !strings.HasPrefix(rel, "..") {
+		relPath = rel
+	}
+	sum := sha256.Sum256(data)
+	carrierRef := FormatCarrierRef(relPath, sum[:])
+
+	ids := make([]string, 0, len(patterns))
+	for id := range patterns {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("## Ingested %s report: %s\n\n", report.Format, relPath))
+	out.WriteString(fmt.Sprintf("Carrier: `%s`\n\n", carrierRef))
+	out.WriteString("| Hypothesis | Passed | Failed | Skipped | Verdict | Result |\n")
+	out.WriteString("|------------|--------|--------|---------|---------|--------|\n")
+
+	recorded := 0
+	for _, id := range ids {
+		var matched []TestCaseResult
+		for _, c := range report.Cases {
+			if patterns[id].MatchString(c.Name) || patterns[id].MatchString(c.FullName()) {
+				matched = append(matched, c)
+			}
+		}
+
+		passed, failed, skipped := countOutcomes(matched)
+		verdict, ok := DeriveVerdict(matched)
+		if !ok {
+			out.WriteString(fmt.Sprintf("| %s | %d | %d | %d | - | no tests ran |\n", id, passed, failed, skipped))
+			continue
+		}
+
+		input := map[string]string{"path": relPath, "format": report.Format, "carrier_ref": carrierRef, "verdict": verdict}
+		result, err := t.recordTestEvidence(id, verdict, relPath, report.Format, carrierRef, matched)
+		if err != nil {
+			t.AuditLog("quint_ingest_results", "ingest_results", t.actor(), id, "ERROR", input, err.Error())
+			result = "error: " + err.Error()
+		} else {
+			t.AuditLog("quint_ingest_results", "ingest_results", t.actor(), id, "SUCCESS", input, result)
+			recorded++
+		}
+		out.WriteString(fmt.Sprintf("| %s | %d | %d | %d | %s | %s |\n", id, passed, failed, skipped, verdict, result))
+	}
+
+	if recorded == 0 {
+		return out.String(), fmt.Errorf("no evidence recorded from %s", relPath)
+	}
+	return out.String(), nil
+}
+
+// recordTestEvidence stores the evidence for one hypothesis. L1 hypotheses
+// are promoted or invalidated like quint_test. L2 hypotheses have their
+// evidence refreshed, and a report in which every matched test failed
+// invalidates them.
+func (t *Tools) recordTestEvidence(hypothesisID, verdict, reportPath, format, carrierRef string, cases []TestCaseResult) (string, error) {
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	holon, err := t.DB.GetHolon(context.Background(), hypothesisID)
+	if err != nil {
+		return "", fmt.Errorf("hypothesis %s not found", hypothesisID)
+	}
+
+	var phase Phase
+	level := holon.Layer
+	switch holon.Layer {
+	case "L1":
+		phase = PhaseInduction
+		if verdict == "pass" {
+			level = "L2"
+		}
+	case "L2":
+		// Audit records the evidence without promoting, and still clears
+		// the suspicion the report re-checks.
+		phase = PhaseAudit
+	default:
+		return "", fmt.Errorf("hypothesis %s is in %s: only L1 and L2 hypotheses take test evidence", hypothesisID, holon.Layer)
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("Test report: %s (%s)\n\n", reportPath, format))
+	for _, c := range cases {
+		content.WriteString(fmt.Sprintf("- [%s] %s\n", c.Outcome, c.FullName()))
+	}
+
+	err = t.inUnit(func(tx *Tools) error {
+		if _, err := tx.ManageEvidence(phase, "add", hypothesisID, "test-report", content.String(), verdict, level, carrierRef, ""); err != nil {
+			return err
+		}
+		if holon.Layer == "L2" && verdict == "fail" {
+			if _, err := tx.MoveHypothesis(hypothesisID, "L2", "invalid"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	after, err := t.DB.GetHolon(context.Background(), hypothesisID)
+	if err == nil && after.Layer != holon.Layer {
+		return fmt.Sprintf("%s → %s", holon.Layer, after.Layer), nil
+	}
+	return "evidence recorded", nil
+}
+
+func countOutcomes(cases []TestCaseResult) (passed, failed, skipped int) {
+	for _, c := range cases {
+		switch c.Outcome {
+		case TestPass:
+			passed++
+		case TestFail:
+			failed++
+		case TestSkip:
+			skipped++
+		}
+	}
+	return passed, failed, skipped
+}
@@ -0,0 +1,284 @@
+package fpf
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const goTestJSONReport = `# building x/cache
+{"Action":"run","Package":"x/cache","Test":"TestRedisGet"}
+{"Action":"output","Package":"x/cache","Test":"TestRedisGet","Output":"=== RUN TestRedisGet\n"}
+{"Action":"pass","Package":"x/cache","Test":"TestRedisGet","Elapsed":0.01}
+{"Action":"run","Package":"x/cache","Test":"TestRedisSet/ttl"}
+{"Action":"fail","Package":"x/cache","Test":"TestRedisSet/ttl","Elapsed":0.01}
+{"Action":"fail","Package":"x/cache","Test":"TestRedisSet","Elapsed":0.01}
+{"Action":"pass","Package":"x/cache","Test":"TestLRUEvict","Elapsed":0.01}
+{"Action":"skip","Package":"x/cache","Test":"TestLRUSlow","Elapsed":0}
+{"Action":"fail","Package":"x/cache","Elapsed":0.05}
+`
+
+const junitReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="cache">
+    <testcase classname="cache.Redis" name="get"/>
+    <testcase classname="cache.Redis" name="set"><failure message="boom">trace</failure></testcase>
+    <testsuite name="nested">
+      <testcase classname="cache.LRU" name="evict"><error/></testcase>
+      <testcase classname="cache.LRU" name="slow"><skipped/></testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>
+`
+
+func TestParseTestReport_GoJSON(t *testing.T) {
+	report, err := ParseTestReport([]byte(goTestJSONReport), ReportFormatAuto)
+	if err != nil {
+		t.Fatalf("ParseTestReport failed: %v", err)
+	}
+	if report.Format != ReportFormatGoJSON {
+		t.Errorf("Expected go-json format, got %s", report.Format)
+	}
+
+	got := map[string]TestOutcome{}
+	for _, c := range report.Cases {
+		got[c.Name] = c.Outcome
+	}
+	want := map[string]TestOutcome{
+		"TestRedisGet":     TestPass,
+		"TestRedisSet/ttl": TestFail,
+		"TestRedisSet":     TestFail,
+		"TestLRUEvict":     TestPass,
+		"TestLRUSlow":      TestSkip,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d cases, got %d: %v", len(want), len(got), got)
+	}
+	for name, outcome := range want {
+		if got[name] != outcome {
+			t.Errorf("%s: expected %s, got %s", name, outcome, got[name])
+		}
+	}
+}
+
+func TestParseTestReport_JUnit(t *testing.T) {
+	report, err := ParseTestReport([]byte(junitReport), "")
+	if err != nil {
+		t.Fatalf("ParseTestReport failed: %v", err)
+	}
+	if report.Format != ReportFormatJUnit {
+		t.Errorf("Expected junit format, got %s", report.Format)
+	}
+
+	got := map[string]TestOutcome{}
+	for _, c := range report.Cases {
+		got[c.FullName()] = c.Outcome
+	}
+	want := map[string]TestOutcome{
+		"cache.Redis.get": TestPass,
+		"cache.Redis.set": TestFail,
+		"cache.LRU.evict": TestFail,
+		"cache.LRU.slow":  TestSkip,
+	}
+	for name, outcome := range want {
+		if got[name] != outcome {
+			t.Errorf("%s: expected %s, got %s", name, outcome, got[name])
+		}
+	}
+}
+
+func TestParseTestReport_Invalid(t *testing.T) {
+	if _, err := ParseTestReport([]byte("ok  \tx/cache\t0.1s"), ReportFormatAuto); err == nil {
+		t.Error("Expected error for plain go test output")
+	}
+	if _, err := ParseTestReport([]byte("<testsuites/>"), ReportFormatJUnit); err == nil {
+		t.Error("Expected error for a report without test cases")
+	}
+	if _, err := ParseTestReport([]byte("{}"), "tap"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestParseTestMapping(t *testing.T) {
+	for _, spec := range []string{
+		"use-redis=^TestRedis; use-lru=TestLRU",
+		`{"use-redis": "^TestRedis", "use-lru": "TestLRU"}`,
+	} {
+		mapping, err := ParseTestMapping(spec)
+		if err != nil {
+			t.Fatalf("ParseTestMapping(%q) failed: %v", spec, err)
+		}
+		if len(mapping) != 2 || !mapping["use-redis"].MatchString("TestRedisGet") || mapping["use-redis"].MatchString("XTestRedis") {
+			t.Errorf("Unexpected mapping for %q: %v", spec, mapping)
+		}
+	}
+
+	for _, spec := range []string{"", "use-redis", "use-redis=("} {
+		if _, err := ParseTestMapping(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
+
+func TestDeriveVerdict(t *testing.T) {
+	c := func(outcomes ...TestOutcome) []TestCaseResult {
+		var cases []TestCaseResult
+		for _, o := range outcomes {
+			cases = append(cases, TestCaseResult{Name: "T", Outcome: o})
+		}
+		return cases
+	}
+
+	tests := []struct {
+		name    string
+		cases   []TestCaseResult
+		verdict string
+		ok      bool
+	}{
+		{"all pass", c(TestPass, TestPass, TestSkip), "pass", true},
+		{"all fail", c(TestFail), "fail", true},
+		{"mixed", c(TestPass, TestFail), "degrade", true},
+		{"only skipped", c(TestSkip), "", false},
+		{"none", nil, "", false},
+	}
+	for _, tt := range tests {
+		verdict, ok := DeriveVerdict(tt.cases)
+		if verdict != tt.verdict || ok != tt.ok {
+			t.Errorf("%s: got (%q, %t), want (%q, %t)", tt.name, verdict, ok, tt.verdict, tt.ok)
+		}
+	}
+}
+
+func TestIngestResults(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"use-redis", "use-lru"} {
+		if err := tools.DB.CreateHolon(ctx, id, "hypothesis", "system", "L1", id, "Content", "default", "global", ""); err != nil {
+			t.Fatalf("CreateHolon failed: %v", err)
+		}
+		path := filepath.Join(tempDir, ".quint", "knowledge", "L1", id+".md")
+		if err := os.WriteFile(path, []byte("# "+id), 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	reportPath := filepath.Join(tempDir, "report.json")
+	report := goTestJSONReport + `{"Action":"pass","Package":"x/cache","Test":"TestLRUGet","Elapsed":0.01}` + "\n"
+	if err := os.WriteFile(reportPath, []byte(report), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	out, err := tools.IngestResults("report.json", "", "", "use-redis=^TestRedis; use-lru=^TestLRU")
+	if err != nil {
+		t.Fatalf("IngestResults failed: %v", err)
+	}
+	if !strings.Contains(out, "| use-lru | 2 | 0 | 1 | pass | L1 → L2 |") {
+		t.Errorf("Expected use-lru to pass and be promoted, got:\n%s", out)
+	}
+	if !strings.Contains(out, "| use-redis | 1 | 2 | 0 | degrade | evidence recorded |") {
+		t.Errorf("Expected use-redis to degrade, got:\n%s", out)
+	}
+
+	sum := sha256.Sum256([]byte(report))
+	wantRef := "report.json#sha256=" + hex.EncodeToString(sum[:])
+
+	evidence, err := tools.DB.GetEvidence(ctx, "use-redis")
+	if err != nil || len(evidence) != 1 {
+		t.Fatalf("Expected one evidence record for use-redis, got %d (%v)", len(evidence), err)
+	}
+	if evidence[0].Verdict != "degrade" || evidence[0].CarrierRef.String != wantRef {
+		t.Errorf("Unexpected evidence: verdict=%s carrier_ref=%s", evidence[0].Verdict, evidence[0].CarrierRef.String)
+	}
+
+	holon, _ := tools.DB.GetHolon(ctx, "use-redis")
+	if holon.Layer != "L1" {
+		t.Errorf("Degraded hypothesis should stay in L1, got %s", holon.Layer)
+	}
+
+	if _, err := tools.IngestResults("report.json", "", "", "missing=^TestNothing"); err == nil {
+		t.Error("Expected error when no tests match")
+	}
+}
+
+func TestIngestResults_L2(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"use-redis", "use-lru"} {
+		if err := tools.DB.CreateHolon(ctx, id, "hypothesis", "system", "L2", id, "Content", "default", "global", ""); err != nil {
+			t.Fatalf("CreateHolon failed: %v", err)
+		}
+		path := filepath.Join(tempDir, ".quint", "knowledge", "L2", id+".md")
+		if err := os.WriteFile(path, []byte("# "+id), 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	report := `{"Action":"fail","Package":"x/cache","Test":"TestRedisGet"}` + "\n" +
+		`{"Action":"pass","Package":"x/cache","Test":"TestLRUGet"}` + "\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "report.json"), []byte(report), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	out, err := tools.IngestResults("report.json", "", "", "use-redis=^TestRedis; use-lru=^TestLRU")
+	if err != nil {
+		t.Fatalf("IngestResults failed: %v", err)
+	}
+	if !strings.Contains(out, "| use-redis | 0 | 1 | 0 | fail | L2 → invalid |") {
+		t.Errorf("Expected the failing L2 hypothesis to be invalidated, got:\n%s", out)
+	}
+	if !strings.Contains(out, "| use-lru | 1 | 0 | 0 | pass | evidence recorded |") {
+		t.Errorf("Expected the passing L2 hypothesis to keep its layer, got:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, ".quint", "knowledge", "invalid", "use-redis.md")); err != nil {
+		t.Errorf("Expected use-redis to move to invalid: %v", err)
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "use-lru"); holon.Layer != "L2" {
+		t.Errorf("Expected use-lru to stay in L2, got %s", holon.Layer)
+	}
+
+	logs, err := tools.DB.ListAuditLog(ctx)
+	if err != nil {
+		t.Fatalf("ListAuditLog failed: %v", err)
+	}
+	audited := map[string]bool{}
+	for _, l := range logs {
+		if l.ToolName == "quint_ingest_results" {
+			audited[l.TargetID.String] = true
+		}
+	}
+	if !audited["use-redis"] || !audited["use-lru"] || audited[""] {
+		t.Errorf("Expected one ingest audit entry per mapped hypothesis, got %v", audited)
+	}
+}
+
+func TestCheckPreconditions_IngestResults(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	if err := os.WriteFile(filepath.Join(tempDir, "report.xml"), []byte(junitReport), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]string
+		wantErr bool
+	}{
+		{"valid", map[string]string{"path": "report.xml", "hypothesis_id": "h1"}, false},
+		{"missing path", map[string]string{"hypothesis_id": "h1"}, true},
+		{"missing file", map[string]string{"path": "nope.xml", "hypothesis_id": "h1"}, true},
+		{"no target", map[string]string{"path": "report.xml"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tools.CheckPreconditions("quint_ingest_results", tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckPreconditions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -46,7 +46,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 
 	// --- 0. Initialize FPF Project ---
 	t.Run("0_InitProject", func(t *testing.T) {
-		fsm, err := fpf.LoadState(contextID, database.GetRawDB())
+		fsm, err := fpf.LoadState(contextID, database)
 		if err != nil {
 			t.Fatalf("Failed to load initial state: %v", err)
 		}
@@ -65,7 +65,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 	})
 
 	// Reload FSM state for subsequent steps
-	fsm, err := fpf.LoadState(contextID, database.GetRawDB())
+	fsm, err := fpf.LoadState(contextID, database)
 	if err != nil {
 		t.Fatalf("Failed to load state after init: %v", err)
 	}
@@ -94,7 +94,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 		if fsm.GetPhase() != fpf.PhaseIdle {
 			t.Fatalf("Expected phase IDLE before first proposal, got %s", fsm.GetPhase())
 		}
-		path, err := tools.ProposeHypothesis(hypo1Title, hypo1Content, "global", "system", "Integration Test Rationale", "", nil, 3)
+		path, err := tools.ProposeHypothesis(hypo1Title, hypo1Content, "global", "system", "Integration Test Rationale", "", nil, 3, 0, "", "")
 		if err != nil {
 			t.Fatalf("ProposeHypothesis failed: %v", err)
 		}
@@ -317,7 +317,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 			t.Fatalf("SaveState failed: %v", err)
 		}
 
-		path, err := tools.FinalizeDecision("Final Decision", finalWinnerID, nil, "Context", "Decision", drrContent, "Consequences", "Characteristics")
+		path, err := tools.FinalizeDecision("Final Decision", finalWinnerID, nil, "Context", "Decision", drrContent, "Consequences", "Characteristics", "", nil)
 		if err != nil {
 			t.Fatalf("FinalizeDecision failed: %v", err)
 		}
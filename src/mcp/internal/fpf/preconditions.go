@@ -8,9 +8,9 @@ import (
 )
 
 type PreconditionError struct {
-	Tool       string
-	Condition  string
-	Suggestion string
+	Tool       string `json:"tool"`
+	Condition  string `json:"condition"`
+	Suggestion string `json:"suggestion"`
 }
 
 func (e *PreconditionError) Error() string {
@@ -33,6 +33,12 @@ func (t *Tools) CheckPreconditions(toolName string, args map[string]string) erro
 		return t.checkCalculateRPreconditions(args)
 	case "quint_audit_tree":
 		return t.checkAuditTreePreconditions(args)
+	case "quint_query":
+		return t.checkQueryPreconditions(args)
+	case "quint_context":
+		return t.checkContextPreconditions(args)
+	case "quint_ingest_results":
+		return t.checkIngestPreconditions(args)
 	default:
 		return nil
 	}
@@ -73,7 +79,7 @@ func (t *Tools) checkVerifyPreconditions(args map[string]string) error {
 		}
 	}
 
-	l0Path := filepath.Join(t.GetFPFDir(), "knowledge", "L0", hypoID+".md")
+	l0Path := filepath.Join(t.holonContextDir(hypoID), "knowledge", "L0", hypoID+".md")
 	if _, err := os.Stat(l0Path); os.IsNotExist(err) {
 		return &PreconditionError{
 			Tool:       "quint_verify",
@@ -104,7 +110,7 @@ func (t *Tools) checkTestPreconditions(args map[string]string) error {
 		}
 	}
 
-	l0Path := filepath.Join(t.GetFPFDir(), "knowledge", "L0", hypoID+".md")
+	l0Path := filepath.Join(t.holonContextDir(hypoID), "knowledge", "L0", hypoID+".md")
 	if _, err := os.Stat(l0Path); err == nil {
 		return &PreconditionError{
 			Tool:       "quint_test",
@@ -113,8 +119,9 @@ func (t *Tools) checkTestPreconditions(args map[string]string) error {
 		}
 	}
 
-	l1Path := filepath.Join(t.GetFPFDir(), "knowledge", "L1", hypoID+".md")
-	l2Path := filepath.Join(t.GetFPFDir(), "knowledge", "L2", hypoID+".md")
+	dir := t.holonContextDir(hypoID)
+	l1Path := filepath.Join(dir, "knowledge", "L1", hypoID+".md")
+	l2Path := filepath.Join(dir, "knowledge", "L2", hypoID+".md")
 	l1Exists := false
 	l2Exists := false
 
@@ -209,7 +216,7 @@ func (t *Tools) checkDecidePreconditions(args map[string]string) error {
 
 	if t.DB != nil {
 		ctx := context.Background()
-		counts, _ := t.DB.CountHolonsByLayer(ctx, "default")
+		counts, _ := t.DB.CountHolonsByLayer(ctx, t.ContextID())
 
 		l2Count := int64(0)
 		for _, c := range counts {
@@ -282,3 +289,91 @@ func (t *Tools) checkAuditTreePreconditions(args map[string]string) error {
 
 	return nil
 }
+
+func (t *Tools) checkQueryPreconditions(args map[string]string) error {
+	if t.DB == nil {
+		return &PreconditionError{
+			Tool:       "quint_query",
+			Condition:  "database not initialized",
+			Suggestion: "Run /q0-init to initialize the project first",
+		}
+	}
+
+	if args["query"] == "" {
+		return &PreconditionError{
+			Tool:       "quint_query",
+			Condition:  "query is required",
+			Suggestion: "Provide search terms, e.g. a title word or technology name",
+		}
+	}
+
+	return nil
+}
+
+func (t *Tools) checkContextPreconditions(args map[string]string) error {
+	if t.DB == nil {
+		return &PreconditionError{
+			Tool:       "quint_context",
+			Condition:  "database not initialized",
+			Suggestion: "Run /q0-init to initialize the project first",
+		}
+	}
+
+	switch args["action"] {
+	case "", "list":
+		return nil
+	case "create", "switch":
+		if args["context_id"] == "" {
+			return &PreconditionError{
+				Tool:       "quint_context",
+				Condition:  "context_id is required for " + args["action"],
+				Suggestion: "Provide a context ID such as 'payments' or 'search'",
+			}
+		}
+		return nil
+	default:
+		return &PreconditionError{
+			Tool:       "quint_context",
+			Condition:  fmt.Sprintf("unknown action '%s'", args["action"]),
+			Suggestion: "Use one of: list, create, switch",
+		}
+	}
+}
+
+func (t *Tools) checkIngestPreconditions(args map[string]string) error {
+	if t.DB == nil {
+		return &PreconditionError{
+			Tool:       "quint_ingest_results",
+			Condition:  "database not initialized",
+			Suggestion: "Run /q0-init to initialize the project first",
+		}
+	}
+
+	path := args["path"]
+	if path == "" {
+		return &PreconditionError{
+			Tool:       "quint_ingest_results",
+			Condition:  "path is required",
+			Suggestion: "Provide the path of a go test -json output file or a JUnit XML report",
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(t.RootDir, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return &PreconditionError{
+			Tool:       "quint_ingest_results",
+			Condition:  fmt.Sprintf("report '%s' not found", args["path"]),
+			Suggestion: "Write the report first, e.g. go test -json ./... > test-report.json",
+		}
+	}
+
+	if args["hypothesis_id"] == "" && args["mapping"] == "" {
+		return &PreconditionError{
+			Tool:       "quint_ingest_results",
+			Condition:  "hypothesis_id or mapping is required",
+			Suggestion: "Map tests to hypotheses, e.g. mapping=\"use-redis=^TestRedis; use-lru=TestLRU\"",
+		}
+	}
+	return nil
+}
@@ -185,7 +185,7 @@ func extractLayerFromPath(path string) string {
 	return ""
 }
 
-func RegenerateHolonFile(store *db.Store, holonID, fpfDir string) error {
+func RegenerateHolonFile(store db.Repository, holonID, fpfDir string) error {
 	if store == nil {
 		return fmt.Errorf("DB not initialized")
 	}
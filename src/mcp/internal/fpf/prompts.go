@@ -0,0 +1,171 @@
+package fpf
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ArgumentsPlaceholder is replaced with the free-form input of a prompt,
+// matching the slash-command convention used by the installed commands.
+const ArgumentsPlaceholder = "$ARGUMENTS"
+
+var positionalPlaceholder = regexp.MustCompile(`\$([1-9])`)
+
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+type PromptMessage struct {
+	Role    string      `json:"role"`
+	Content ContentItem `json:"content"`
+}
+
+type promptEntry struct {
+	Prompt
+	body       string
+	positional int
+}
+
+// PromptSet serves the FPF slash commands (q0–q5, q-*) as MCP prompts.
+type PromptSet struct {
+	entries map[string]promptEntry
+	order   []string
+}
+
+// LoadPrompts reads every *.md command at the root of fsys.
+func LoadPrompts(fsys fs.FS) (*PromptSet, error) {
+	files, err := fs.Glob(fsys, "*.md")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompts: %w", err)
+	}
+
+	ps := &PromptSet{entries: make(map[string]promptEntry)}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt %s: %w", file, err)
+		}
+		entry := parsePrompt(strings.TrimSuffix(file, ".md"), string(data))
+		ps.entries[entry.Name] = entry
+		ps.order = append(ps.order, entry.Name)
+	}
+	sort.Strings(ps.order)
+
+	return ps, nil
+}
+
+func parsePrompt(name, content string) promptEntry {
+	frontmatter, body, _ := parseFrontmatter(content)
+	fields := parseFrontmatterFields(frontmatter)
+
+	description := fields["description"]
+	if description == "" {
+		description = extractMarkdownHeading(body)
+	}
+	if description == "" {
+		description = "FPF command: " + name
+	}
+
+	hint := fields["argument-hint"]
+	if hint == "" {
+		hint = "Input for the command"
+	}
+
+	entry := promptEntry{
+		Prompt: Prompt{
+			Name:        name,
+			Description: description,
+			Arguments: []PromptArgument{
+				{Name: "arguments", Description: hint},
+			},
+		},
+		body: strings.TrimLeft(body, "\n"),
+	}
+
+	for _, m := range positionalPlaceholder.FindAllStringSubmatch(body, -1) {
+		n := int(m[1][0] - '0')
+		if n > entry.positional {
+			entry.positional = n
+		}
+	}
+	for i := 1; i <= entry.positional; i++ {
+		entry.Arguments = append(entry.Arguments, PromptArgument{
+			Name:        fmt.Sprintf("arg%d", i),
+			Description: fmt.Sprintf("Positional argument $%d", i),
+		})
+	}
+
+	return entry
+}
+
+// List returns prompt definitions sorted by name.
+func (ps *PromptSet) List() []Prompt {
+	prompts := make([]Prompt, 0, len(ps.order))
+	for _, name := range ps.order {
+		prompts = append(prompts, ps.entries[name].Prompt)
+	}
+	return prompts
+}
+
+// Get renders a prompt. $ARGUMENTS is replaced with args["arguments"] and $N
+// with args["argN"]; if the body has no $ARGUMENTS placeholder, non-empty
+// input is appended so it still reaches the model.
+func (ps *PromptSet) Get(name string, args map[string]string) (string, []PromptMessage, error) {
+	entry, ok := ps.entries[name]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown prompt: %s", name)
+	}
+
+	input := args["arguments"]
+	text := entry.body
+
+	for i := entry.positional; i >= 1; i-- {
+		text = strings.ReplaceAll(text, fmt.Sprintf("$%d", i), args[fmt.Sprintf("arg%d", i)])
+	}
+
+	if strings.Contains(text, ArgumentsPlaceholder) {
+		text = strings.ReplaceAll(text, ArgumentsPlaceholder, input)
+	} else if strings.TrimSpace(input) != "" {
+		text = strings.TrimRight(text, "\n") + "\n\nARGUMENTS: " + input + "\n"
+	}
+
+	return entry.Description, []PromptMessage{
+		{Role: "user", Content: ContentItem{Type: "text", Text: text}},
+	}, nil
+}
+
+func parseFrontmatterFields(frontmatter string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(frontmatter, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = strings.ReplaceAll(value[1:len(value)-1], `\"`, `"`)
+		}
+		fields[strings.TrimSpace(key)] = value
+	}
+	return fields
+}
+
+func extractMarkdownHeading(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimLeft(line, "# "))
+		}
+	}
+	return ""
+}
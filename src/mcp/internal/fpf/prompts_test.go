@@ -0,0 +1,143 @@
+package fpf
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testPromptFS() fstest.MapFS {
+	return fstest.MapFS{
+		"q1-hypothesize.md": {Data: []byte("---\ndescription: \"Generate Hypotheses (Abduction)\"\nargument-hint: \"<problem statement>\"\n---\n\n# Phase 1\n\nProblem: $ARGUMENTS\n")},
+		"q-status.md":       {Data: []byte("---\ndescription: \"Show FPF status\"\n---\n\n# Status Check\n\nCall quint_status.\n")},
+		"q-decay.md":        {Data: []byte("# q-decay: Evidence Freshness\n\nDeprecate $1 until $2.\n")},
+		"notes.txt":         {Data: []byte("ignored")},
+	}
+}
+
+func TestLoadPrompts(t *testing.T) {
+	ps, err := LoadPrompts(testPromptFS())
+	if err != nil {
+		t.Fatalf("LoadPrompts failed: %v", err)
+	}
+
+	prompts := ps.List()
+	if len(prompts) != 3 {
+		t.Fatalf("Expected 3 prompts, got %d", len(prompts))
+	}
+
+	byName := make(map[string]Prompt)
+	for _, p := range prompts {
+		byName[p.Name] = p
+	}
+
+	hypo := byName["q1-hypothesize"]
+	if hypo.Description != "Generate Hypotheses (Abduction)" {
+		t.Errorf("Unexpected description %q", hypo.Description)
+	}
+	if len(hypo.Arguments) != 1 || hypo.Arguments[0].Description != "<problem statement>" {
+		t.Errorf("Expected argument hint from frontmatter, got %+v", hypo.Arguments)
+	}
+
+	decay := byName["q-decay"]
+	if decay.Description != "q-decay: Evidence Freshness" {
+		t.Errorf("Expected heading as fallback description, got %q", decay.Description)
+	}
+	if len(decay.Arguments) != 3 {
+		t.Errorf("Expected arguments + arg1 + arg2, got %+v", decay.Arguments)
+	}
+}
+
+func TestPromptSet_Get(t *testing.T) {
+	ps, err := LoadPrompts(testPromptFS())
+	if err != nil {
+		t.Fatalf("LoadPrompts failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		args     map[string]string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "q1-hypothesize",
+			args:     map[string]string{"arguments": "slow checkout"},
+			contains: []string{"Problem: slow checkout"},
+			excludes: []string{"$ARGUMENTS", "description:"},
+		},
+		{
+			name:     "q-status",
+			args:     map[string]string{"arguments": "verbose"},
+			contains: []string{"Call quint_status.", "ARGUMENTS: verbose"},
+		},
+		{
+			name:     "q-status",
+			args:     nil,
+			excludes: []string{"ARGUMENTS"},
+		},
+		{
+			name:     "q-decay",
+			args:     map[string]string{"arg1": "redis", "arg2": "2030-01-01"},
+			contains: []string{"Deprecate redis until 2030-01-01."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, messages, err := ps.Get(tt.name, tt.args)
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			if len(messages) != 1 || messages[0].Role != "user" {
+				t.Fatalf("Expected a single user message, got %+v", messages)
+			}
+			text := messages[0].Content.Text
+			for _, c := range tt.contains {
+				if !strings.Contains(text, c) {
+					t.Errorf("Expected %q in %q", c, text)
+				}
+			}
+			for _, e := range tt.excludes {
+				if strings.Contains(text, e) {
+					t.Errorf("Did not expect %q in %q", e, text)
+				}
+			}
+		})
+	}
+
+	if _, _, err := ps.Get("q9-missing", nil); err == nil {
+		t.Error("Expected error for unknown prompt")
+	}
+}
+
+func TestServer_Prompts(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	resp := server.Handle(nil, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "prompts/list"})
+	if resp.Error == nil {
+		t.Error("Expected prompts/list to be unavailable before SetPrompts")
+	}
+
+	ps, err := LoadPrompts(testPromptFS())
+	if err != nil {
+		t.Fatalf("LoadPrompts failed: %v", err)
+	}
+	server.SetPrompts(ps)
+
+	init := server.Handle(nil, JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "initialize", Params: json.RawMessage(`{}`)})
+	if !strings.Contains(mustMarshal(t, init.Result), `"prompts"`) {
+		t.Error("Expected prompts capability after SetPrompts")
+	}
+
+	params, _ := json.Marshal(map[string]interface{}{"name": "q1-hypothesize", "arguments": map[string]string{"arguments": "x"}})
+	resp = server.Handle(nil, JSONRPCRequest{JSONRPC: "2.0", ID: 3, Method: "prompts/get", Params: params})
+	if resp.Error != nil {
+		t.Fatalf("prompts/get failed: %s", resp.Error.Message)
+	}
+	if !strings.Contains(mustMarshal(t, resp.Result), "Problem: x") {
+		t.Errorf("Unexpected prompts/get result: %s", mustMarshal(t, resp.Result))
+	}
+}
@@ -0,0 +1,613 @@
+package fpf
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// RebuildReport describes what a rebuild found and did.
+type RebuildReport struct {
+	Files          int
+	HolonsAdded    int
+	EvidenceAdded  int
+	RelationsAdded int
+	Unchanged      int
+	DatabaseOnly   []string // holons kept because no file describes them
+	HashMismatches []string // files edited outside quint-code
+	Skipped        []string // files or relations that could not be restored
+	Conflicts      []string // disagreements between files and database
+	DryRun         bool
+	Applied        bool
+}
+
+type projectedHolon struct {
+	path            string
+	id              string
+	typ             string
+	kind            string
+	layer           string
+	title           string
+	content         string
+	contextID       string
+	scope           string
+	parentID        string
+	formality       int
+	claimScope      string
+	decisionContext string
+	dependsOn       []string
+	dependencyCLs   []int // parallel to dependsOn
+	rejectedIDs     []string
+	status          string
+	supersedes      []string
+}
+
+type projectedEvidence struct {
+	path           string
+	id             string
+	holonID        string
+	typ            string
+	content        string
+	verdict        string
+	assuranceLevel string
+	carrierRef     string
+	validUntil     string
+}
+
+type projectedRelation struct {
+	source, relType, target string
+	cl                      int
+	link                    bool // evidence link without congruence level
+}
+
+// RebuildDB restores the database from the markdown projection of every
+// bounded context, in one transaction and only adding rows; if the files and
+// the database disagree, nothing is written. With dryRun, nothing is written.
+func (t *Tools) RebuildDB(dryRun bool) (*RebuildReport, error) {
+	defer t.RecordWork("RebuildDB", time.Now())
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+	ctx := context.Background()
+	report := &RebuildReport{DryRun: dryRun}
+
+	contexts, err := t.projectedContexts()
+	if err != nil {
+		return nil, err
+	}
+
+	holons := map[string]*projectedHolon{}
+	var holonIDs []string
+	var evidence []*projectedEvidence
+	for _, contextID := range contexts {
+		hs, es, err := t.readProjection(contextID, report)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range hs {
+			if prev, ok := holons[h.id]; ok {
+				report.Conflicts = append(report.Conflicts, fmt.Sprintf("holon %s is described by both %s and %s", h.id, t.relPath(prev.path), t.relPath(h.path)))
+				continue
+			}
+			holons[h.id] = h
+			holonIDs = append(holonIDs, h.id)
+		}
+		evidence = append(evidence, es...)
+	}
+	sort.Strings(holonIDs)
+
+	// Holons
+	var newHolons []*projectedHolon
+	for _, id := range holonIDs {
+		h := holons[id]
+		existing, err := t.DB.GetHolon(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			newHolons = append(newHolons, h)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if diff := holonDiff(existing, h); diff != "" {
+			report.Conflicts = append(report.Conflicts, fmt.Sprintf("holon %s: %s (%s)", id, diff, t.relPath(h.path)))
+			continue
+		}
+		report.Unchanged++
+	}
+
+	dbIDs, err := t.DB.ListAllHolonIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dbOnly := map[string]string{}
+	for _, id := range dbIDs {
+		if _, ok := holons[id]; ok {
+			continue
+		}
+		h, err := t.DB.GetHolon(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		dbOnly[id] = h.ContextID
+		report.DatabaseOnly = append(report.DatabaseOnly, id)
+	}
+
+	holonContext := func(id string) (string, bool) {
+		if h, ok := holons[id]; ok {
+			return h.contextID, true
+		}
+		contextID, ok := dbOnly[id]
+		return contextID, ok
+	}
+
+	// Evidence
+	var newEvidence []*projectedEvidence
+	seenEvidence := map[string]string{}
+	for _, e := range evidence {
+		if prev, ok := seenEvidence[e.id]; ok {
+			report.Conflicts = append(report.Conflicts, fmt.Sprintf("evidence %s is described by both %s and %s", e.id, prev, t.relPath(e.path)))
+			continue
+		}
+		seenEvidence[e.id] = t.relPath(e.path)
+		if _, ok := holonContext(e.holonID); !ok {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%s: target holon %s not found", t.relPath(e.path), e.holonID))
+			continue
+		}
+		existing, err := t.DB.GetEvidenceByID(ctx, e.id)
+		if errors.Is(err, sql.ErrNoRows) {
+			newEvidence = append(newEvidence, e)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if diff := evidenceDiff(existing, e); diff != "" {
+			report.Conflicts = append(report.Conflicts, fmt.Sprintf("evidence %s: %s (%s)", e.id, diff, t.relPath(e.path)))
+			continue
+		}
+		report.Unchanged++
+	}
+
+	// Relations, derived the same way quint_propose and quint_decide create them
+	var relations []projectedRelation
+	for _, id := range holonIDs {
+		h := holons[id]
+		relType := "componentOf"
+		if h.kind == "episteme" {
+			relType = "constituentOf"
+		}
+		for i, dep := range h.dependsOn {
+			depContext, ok := holonContext(dep)
+			if !ok {
+				report.Skipped = append(report.Skipped, fmt.Sprintf("%s: dependency %s not found", id, dep))
+				continue
+			}
+			// Files written before the cap was projected still carry the
+			// requested level.
+			cl := h.dependencyCLs[i]
+			if depContext != h.contextID && cl > CrossContextCL {
+				cl = CrossContextCL
+			}
+			relations = append(relations, projectedRelation{source: dep, relType: relType, target: id, cl: cl})
+		}
+		if h.decisionContext != "" {
+			if _, ok := holonContext(h.decisionContext); ok {
+				relations = append(relations, projectedRelation{source: id, relType: "memberOf", target: h.decisionContext, cl: 3})
+			} else {
+				report.Skipped = append(report.Skipped, fmt.Sprintf("%s: decision context %s not found", id, h.decisionContext))
+			}
+		}
+		if h.typ == "DRR" {
+			if h.parentID != "" {
+				if _, ok := holonContext(h.parentID); ok {
+					relations = append(relations, projectedRelation{source: id, relType: "selects", target: h.parentID, cl: 3})
+				} else {
+					report.Skipped = append(report.Skipped, fmt.Sprintf("%s: selected hypothesis %s not found", id, h.parentID))
+				}
+			}
+			for _, rej := range h.rejectedIDs {
+				if rej == h.parentID {
+					continue
+				}
+				if _, ok := holonContext(rej); ok {
+					relations = append(relations, projectedRelation{source: id, relType: "rejects", target: rej, cl: 3})
+				} else {
+					report.Skipped = append(report.Skipped, fmt.Sprintf("%s: rejected hypothesis %s not found", id, rej))
+				}
+			}
+			for _, old := range h.supersedes {
+				if _, ok := holonContext(old); ok {
+					relations = append(relations, projectedRelation{source: id, relType: "supersedes", target: old, cl: 3})
+				} else {
+					report.Skipped = append(report.Skipped, fmt.Sprintf("%s: superseded DRR %s not found", id, old))
+				}
+			}
+		}
+	}
+	for _, e := range newEvidence {
+		relations = append(relations, projectedRelation{source: e.id, relType: "verifiedBy", target: e.holonID, link: true})
+	}
+
+	var newRelations []projectedRelation
+	for _, r := range relations {
+		if r.source == r.target {
+			continue
+		}
+		existing, err := t.DB.GetRelationsByTarget(ctx, r.target, r.relType)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, e := range existing {
+			if e.SourceID != r.source {
+				continue
+			}
+			found = true
+			if !r.link && e.CongruenceLevel.Valid && int(e.CongruenceLevel.Int64) != r.cl {
+				report.Conflicts = append(report.Conflicts, fmt.Sprintf("relation %s %s %s: CL%d in database, CL%d in files", r.source, r.relType, r.target, e.CongruenceLevel.Int64, r.cl))
+			}
+		}
+		if !found {
+			newRelations = append(newRelations, r)
+		}
+	}
+
+	report.HolonsAdded = len(newHolons)
+	report.EvidenceAdded = len(newEvidence)
+	report.RelationsAdded = len(newRelations)
+	if dryRun || len(report.Conflicts) > 0 {
+		return report, nil
+	}
+
+	// Everything is restored in one transaction, so a failure part way
+	// leaves the database as it was.
+	err = t.inUnit(func(tx *Tools) error {
+		for _, contextID := range contexts {
+			_, err := tx.DB.GetContext(ctx, contextID)
+			if errors.Is(err, sql.ErrNoRows) {
+				err = tx.DB.CreateContext(ctx, contextID, "")
+			}
+			if err != nil {
+				return fmt.Errorf("failed to create context %s: %w", contextID, err)
+			}
+		}
+		for _, h := range newHolons {
+			if err := tx.DB.CreateHolon(ctx, h.id, h.typ, h.kind, h.layer, h.title, h.content, h.contextID, h.scope, h.parentID); err != nil {
+				return fmt.Errorf("failed to restore holon %s: %w", h.id, err)
+			}
+			if h.typ == "hypothesis" {
+				if err := tx.DB.UpdateHolonClaim(ctx, h.id, h.formality, h.claimScope); err != nil {
+					return fmt.Errorf("failed to restore F-G of %s: %w", h.id, err)
+				}
+			}
+			if h.typ == "DRR" {
+				if err := tx.DB.UpdateHolonStatus(ctx, h.id, h.status); err != nil {
+					return fmt.Errorf("failed to restore status of %s: %w", h.id, err)
+				}
+			}
+		}
+		for _, e := range newEvidence {
+			if err := tx.DB.AddEvidence(ctx, e.id, e.holonID, e.typ, e.content, e.verdict, e.assuranceLevel, e.carrierRef, e.validUntil); err != nil {
+				return fmt.Errorf("failed to restore evidence %s: %w", e.id, err)
+			}
+		}
+		for _, r := range newRelations {
+			var err error
+			if r.link {
+				err = tx.DB.Link(ctx, r.source, r.target, r.relType)
+			} else {
+				err = tx.DB.CreateRelation(ctx, r.source, r.relType, r.target, r.cl)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to restore relation %s %s %s: %w", r.source, r.relType, r.target, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+	report.Applied = true
+
+	t.AuditLog("rebuild_db", "rebuild", t.actor(), "", "SUCCESS", map[string]string{
+		"holons":    strconv.Itoa(report.HolonsAdded),
+		"evidence":  strconv.Itoa(report.EvidenceAdded),
+		"relations": strconv.Itoa(report.RelationsAdded),
+	}, fmt.Sprintf("%d hash mismatch(es)", len(report.HashMismatches)))
+
+	return report, nil
+}
+
+// projectedContexts lists the bounded contexts that have a workspace on disk.
+func (t *Tools) projectedContexts() ([]string, error) {
+	contexts := []string{db.DefaultContextID}
+	entries, err := os.ReadDir(filepath.Join(t.GetFPFDir(), "contexts"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() && contextIDPattern.MatchString(e.Name()) {
+			contexts = append(contexts, e.Name())
+		}
+	}
+	return contexts, nil
+}
+
+// readProjection parses the holon, DRR and evidence files of one context.
+func (t *Tools) readProjection(contextID string, report *RebuildReport) ([]*projectedHolon, []*projectedEvidence, error) {
+	dir := t.contextDir(contextID)
+	var holons []*projectedHolon
+	var evidence []*projectedEvidence
+
+	read := func(pattern string, parse func(path string, fields map[string]string, body string) error) error {
+		paths, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return err
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			content, tampered, expected, actual, err := ValidateFile(path)
+			if err != nil {
+				return err
+			}
+			report.Files++
+			if tampered {
+				report.HashMismatches = append(report.HashMismatches, fmt.Sprintf("%s (expected %s, found %s)", t.relPath(path), expected, actual))
+			}
+			fm, body, ok := parseFrontmatter(content)
+			if !ok {
+				report.Skipped = append(report.Skipped, fmt.Sprintf("%s: no frontmatter", t.relPath(path)))
+				continue
+			}
+			if err := parse(path, parseFrontmatterFields(fm), body); err != nil {
+				report.Skipped = append(report.Skipped, fmt.Sprintf("%s: %v", t.relPath(path), err))
+			}
+		}
+		return nil
+	}
+
+	for _, layer := range []string{"L0", "L1", "L2", "invalid"} {
+		err := read(filepath.Join("knowledge", layer, "*.md"), func(path string, fields map[string]string, body string) error {
+			h, err := parseHolonFile(path, fields, body)
+			if err != nil {
+				return err
+			}
+			h.layer = layer
+			h.contextID = contextID
+			holons = append(holons, h)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err := read(filepath.Join("decisions", "*.md"), func(path string, fields map[string]string, body string) error {
+		title := extractMarkdownHeading(body)
+		if title == "" {
+			return fmt.Errorf("missing title heading")
+		}
+		id := fields["id"]
+		if id == "" {
+			id = t.Slugify(title)
+		}
+		holons = append(holons, &projectedHolon{
+			path:        path,
+			id:          id,
+			typ:         "DRR",
+			layer:       "DRR",
+			title:       title,
+			content:     body,
+			contextID:   contextID,
+			parentID:    fields["winner_id"],
+			rejectedIDs: splitList(fields["rejected_ids"]),
+			status:      orAccepted(fields["status"]),
+			supersedes:  splitList(fields["supersedes"]),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = read(filepath.Join("evidence", "*.md"), func(path string, fields map[string]string, body string) error {
+		e := &projectedEvidence{
+			path:           path,
+			id:             fields["id"],
+			holonID:        fields["target"],
+			typ:            fields["type"],
+			content:        strings.TrimPrefix(body, "\n"),
+			verdict:        fields["verdict"],
+			assuranceLevel: fields["assurance_level"],
+			carrierRef:     fields["carrier_ref"],
+			validUntil:     fields["valid_until"],
+		}
+		if e.id == "" {
+			e.id = filepath.Base(path)
+		}
+		if e.holonID == "" || e.typ == "" || e.verdict == "" {
+			return fmt.Errorf("evidence frontmatter needs target, type and verdict")
+		}
+		evidence = append(evidence, e)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return holons, evidence, nil
+}
+
+func parseHolonFile(path string, fields map[string]string, body string) (*projectedHolon, error) {
+	title := strings.TrimPrefix(extractMarkdownHeading(body), "Hypothesis: ")
+	if title == "" {
+		return nil, fmt.Errorf("missing title heading")
+	}
+	formality, err := assurance.ParseFormality(fields["formality"])
+	if err != nil {
+		return nil, err
+	}
+	g, err := assurance.ParseClaimScope(fields["claim_scope"])
+	if err != nil {
+		return nil, err
+	}
+	dependsOn := splitList(fields["depends_on"])
+	cls, err := parseDependencyCLs(fields["dependency_cl"], len(dependsOn))
+	if err != nil {
+		return nil, err
+	}
+	return &projectedHolon{
+		path:            path,
+		id:              strings.TrimSuffix(filepath.Base(path), ".md"),
+		typ:             "hypothesis",
+		kind:            fields["kind"],
+		title:           title,
+		content:         body,
+		scope:           fields["scope"],
+		formality:       formality,
+		claimScope:      g.JSON(),
+		decisionContext: fields["decision_context"],
+		dependsOn:       dependsOn,
+		dependencyCLs:   cls,
+	}, nil
+}
+
+// holonDiff describes how a database holon disagrees with its file, or
+// returns "" if they agree.
+func holonDiff(existing db.Holon, h *projectedHolon) string {
+	var diffs []string
+	compare := func(field, dbValue, fileValue string) {
+		if dbValue != fileValue {
+			diffs = append(diffs, fmt.Sprintf("%s %q in database, %q in files", field, dbValue, fileValue))
+		}
+	}
+	compare("type", existing.Type, h.typ)
+	compare("layer", existing.Layer, h.layer)
+	compare("context", existing.ContextID, h.contextID)
+	compare("title", existing.Title, h.title)
+	if existing.Content != h.content {
+		diffs = append(diffs, "content differs")
+	}
+	if h.typ == "DRR" {
+		compare("status", drrStatus(existing), h.status)
+	}
+	if h.typ == "hypothesis" {
+		compare("formality", assurance.FormatFormality(int(existing.Formality.Int64)), assurance.FormatFormality(h.formality))
+	}
+	return strings.Join(diffs, "; ")
+}
+
+func evidenceDiff(existing db.Evidence, e *projectedEvidence) string {
+	var diffs []string
+	compare := func(field, dbValue, fileValue string) {
+		if dbValue != fileValue {
+			diffs = append(diffs, fmt.Sprintf("%s %q in database, %q in files", field, dbValue, fileValue))
+		}
+	}
+	compare("target", existing.HolonID, e.holonID)
+	compare("type", existing.Type, e.typ)
+	compare("verdict", existing.Verdict, e.verdict)
+	compare("carrier_ref", existing.CarrierRef.String, e.carrierRef)
+	if existing.Content != e.content {
+		diffs = append(diffs, "content differs")
+	}
+	return strings.Join(diffs, "; ")
+}
+
+// parseDependencyCLs reads dependency_cl for deps dependencies, defaulting
+// to CL3.
+func parseDependencyCLs(v string, deps int) ([]int, error) {
+	levels := splitList(v)
+	if len(levels) == 0 {
+		levels = []string{"3"}
+	}
+	if len(levels) != 1 && len(levels) != deps {
+		return nil, fmt.Errorf("invalid dependency_cl %q: expected one level, or one per dependency", v)
+	}
+	cls := make([]int, len(levels))
+	for i, level := range levels {
+		cl, err := strconv.Atoi(level)
+		if err != nil || cl < 1 || cl > 3 {
+			return nil, fmt.Errorf("invalid dependency_cl %q", v)
+		}
+		cls[i] = cl
+	}
+	for len(cls) < deps {
+		cls = append(cls, cls[0])
+	}
+	return cls, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (t *Tools) relPath(path string) string {
+	if rel, err := filepath.Rel(t.RootDir, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// String renders the report as markdown.
+func (r *RebuildReport) String() string {
+	var out strings.Builder
+	out.WriteString("## Rebuild from Markdown\n\n")
+	out.WriteString(fmt.Sprintf("Scanned %d file(s).\n\n", r.Files))
+
+	if len(r.Conflicts) > 0 {
+		out.WriteString(fmt.Sprintf("### CONFLICTS (%d) — nothing was written\n\n", len(r.Conflicts)))
+		for _, c := range r.Conflicts {
+			out.WriteString(fmt.Sprintf("- %s\n", c))
+		}
+		out.WriteString("\nThe database and the markdown files disagree. Decide which side is right,\nfix the other (or move quint.db aside to rebuild from files alone) and re-run.\n\n")
+	}
+
+	if len(r.HashMismatches) > 0 {
+		out.WriteString("### Hash mismatches (edited outside quint-code)\n\n")
+		for _, m := range r.HashMismatches {
+			out.WriteString(fmt.Sprintf("- %s\n", m))
+		}
+		out.WriteString("\n")
+	}
+
+	heading := "### Restored"
+	switch {
+	case r.DryRun:
+		heading = "### Would restore (dry run)"
+	case !r.Applied:
+		heading = "### Pending (blocked by conflicts)"
+	}
+	out.WriteString(heading + "\n\n")
+	out.WriteString(fmt.Sprintf("- Holons and DRRs: %d\n", r.HolonsAdded))
+	out.WriteString(fmt.Sprintf("- Evidence: %d\n", r.EvidenceAdded))
+	out.WriteString(fmt.Sprintf("- Relations: %d\n", r.RelationsAdded))
+	out.WriteString(fmt.Sprintf("- Already in database: %d\n", r.Unchanged))
+	if len(r.DatabaseOnly) > 0 {
+		out.WriteString(fmt.Sprintf("- Kept database-only holons: %s\n", strings.Join(r.DatabaseOnly, ", ")))
+	}
+
+	if len(r.Skipped) > 0 {
+		out.WriteString("\n### Skipped\n\n")
+		for _, s := range r.Skipped {
+			out.WriteString(fmt.Sprintf("- %s\n", s))
+		}
+	}
+	return out.String()
+}
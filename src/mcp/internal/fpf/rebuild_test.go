@@ -0,0 +1,335 @@
+package fpf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// setupProjectedProject runs a small decision cycle and returns its tools.
+func setupProjectedProject(t *testing.T) (*Tools, string) {
+	t.Helper()
+	tools, _, tempDir := setupTools(t)
+
+	steps := []func() (string, error){
+		func() (string, error) {
+			return tools.ProposeHypothesis("Caching", "Decide caching", "global", "system", "{}", "", nil, 3, 0, "", "")
+		},
+		func() (string, error) {
+			return tools.ProposeHypothesis("Use Redis", "Redis cache", "global", "system", "{}", "caching", nil, 3, 2, "env=prod", "")
+		},
+		func() (string, error) {
+			return tools.ProposeHypothesis("Use LRU", "In-process LRU", "global", "system", "{}", "caching", []string{"use-redis"}, 2, 0, "", "")
+		},
+		func() (string, error) { return tools.VerifyHypothesis("use-redis", "{}", "PASS") },
+		func() (string, error) { return tools.VerifyHypothesis("use-lru", "{}", "PASS") },
+		func() (string, error) {
+			return tools.ManageEvidence(PhaseInduction, "add", "use-lru", "internal", "bench ok", "PASS", "L2", "cache/lru.go", "")
+		},
+		func() (string, error) {
+			return tools.FinalizeDecision("Cache Choice", "use-redis", []string{"use-lru"}, "Context", "Decision", "Rationale", "Consequences", "", "", nil)
+		},
+	}
+	for i, step := range steps {
+		if _, err := step(); err != nil {
+			t.Fatalf("setup step %d failed: %v", i, err)
+		}
+	}
+	return tools, tempDir
+}
+
+// freshTools opens an empty database for the same project root.
+func freshTools(t *testing.T, root string) *Tools {
+	t.Helper()
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "fresh.db"))
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	fsm := &FSM{State: State{Phase: PhaseIdle}, DB: store}
+	return NewTools(fsm, root, store)
+}
+
+func TestRebuildDB(t *testing.T) {
+	original, root := setupProjectedProject(t)
+	ctx := context.Background()
+
+	rebuilt := freshTools(t, root)
+	report, err := rebuilt.RebuildDB(false)
+	if err != nil {
+		t.Fatalf("RebuildDB failed: %v", err)
+	}
+	if !report.Applied || len(report.Conflicts) > 0 || len(report.HashMismatches) > 0 {
+		t.Fatalf("Expected a clean rebuild, got:\n%s", report)
+	}
+	// Two verification records plus the induction test evidence.
+	if report.HolonsAdded != 4 || report.EvidenceAdded != 3 {
+		t.Errorf("Expected 4 holons and 3 evidence, got:\n%s", report)
+	}
+
+	for _, id := range []string{"caching", "use-redis", "use-lru", "cache-choice"} {
+		want, err := original.DB.GetHolon(ctx, id)
+		if err != nil {
+			t.Fatalf("GetHolon(%s) on original failed: %v", id, err)
+		}
+		got, err := rebuilt.DB.GetHolon(ctx, id)
+		if err != nil {
+			t.Fatalf("holon %s was not restored", id)
+		}
+		if got.Layer != want.Layer || got.Title != want.Title || got.Content != want.Content ||
+			got.Formality != want.Formality || got.ClaimScope != want.ClaimScope || got.ParentID != want.ParentID {
+			t.Errorf("holon %s differs:\n got  %+v\n want %+v", id, got, want)
+		}
+	}
+
+	wantRelations := map[string]string{
+		"use-redis memberOf":    "caching",
+		"use-redis componentOf": "use-lru",
+		"cache-choice selects":  "use-redis",
+		"cache-choice rejects":  "use-lru",
+	}
+	for key, target := range wantRelations {
+		source, relType, _ := strings.Cut(key, " ")
+		if relType == "memberOf" {
+			source, target = "use-redis", "caching"
+		}
+		rels, _ := rebuilt.DB.GetRelationsByTarget(ctx, target, relType)
+		found := false
+		for _, r := range rels {
+			if r.SourceID == source {
+				found = true
+				if relType == "componentOf" && r.CongruenceLevel.Int64 != 2 {
+					t.Errorf("Expected dependency at CL2, got CL%d", r.CongruenceLevel.Int64)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("relation %s %s %s was not restored", source, relType, target)
+		}
+	}
+
+	evidence, _ := rebuilt.DB.GetEvidence(ctx, "use-lru")
+	var restored *db.Evidence
+	for i := range evidence {
+		if evidence[i].Type == "internal" {
+			restored = &evidence[i]
+		}
+	}
+	if len(evidence) != 2 || restored == nil || restored.Verdict != "pass" || restored.CarrierRef.String != "cache/lru.go" {
+		t.Errorf("Expected restored verification and test evidence, got %+v", evidence)
+	}
+
+	if phase := rebuilt.FSM.GetPhase(); phase != original.FSM.GetPhase() {
+		t.Errorf("Derived phase differs after rebuild: %s vs %s", phase, original.FSM.GetPhase())
+	}
+
+	// A second run finds everything in place.
+	again, err := rebuilt.RebuildDB(false)
+	if err != nil {
+		t.Fatalf("second RebuildDB failed: %v", err)
+	}
+	if again.HolonsAdded+again.EvidenceAdded+again.RelationsAdded != 0 || len(again.Conflicts) > 0 {
+		t.Errorf("Expected an idempotent rebuild, got:\n%s", again)
+	}
+}
+
+func TestRebuildDB_RefusesOnConflict(t *testing.T) {
+	tools, root := setupProjectedProject(t)
+	ctx := context.Background()
+
+	// The database moved on without the files, and a file was edited by hand.
+	if err := tools.DB.UpdateHolonLayer(ctx, "use-lru", "invalid"); err != nil {
+		t.Fatalf("UpdateHolonLayer failed: %v", err)
+	}
+	path := filepath.Join(root, ".quint", "knowledge", "L0", "new-idea.md")
+	if err := WriteWithHash(path, map[string]string{"kind": "system", "scope": "global"}, "\n# Hypothesis: New Idea\n\nBody"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, []byte(string(data)+"\nedited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := tools.RebuildDB(false)
+	if err != nil {
+		t.Fatalf("RebuildDB failed: %v", err)
+	}
+	if report.Applied || len(report.Conflicts) != 1 || !strings.Contains(report.Conflicts[0], `layer "invalid" in database, "L2" in files`) {
+		t.Fatalf("Expected a single layer conflict and no writes, got:\n%s", report)
+	}
+	if len(report.HashMismatches) != 1 || !strings.Contains(report.HashMismatches[0], "new-idea.md") {
+		t.Errorf("Expected the edited file to be reported, got %v", report.HashMismatches)
+	}
+	if _, err := tools.DB.GetHolon(ctx, "new-idea"); err == nil {
+		t.Error("Nothing should be written when there are conflicts")
+	}
+	if out := report.String(); !strings.Contains(out, "CONFLICTS (1)") || !strings.Contains(out, "Pending (blocked by conflicts)") {
+		t.Errorf("Unexpected report:\n%s", out)
+	}
+}
+
+func TestRebuildDB_DryRun(t *testing.T) {
+	_, root := setupProjectedProject(t)
+	rebuilt := freshTools(t, root)
+
+	report, err := rebuilt.RebuildDB(true)
+	if err != nil {
+		t.Fatalf("RebuildDB failed: %v", err)
+	}
+	if report.Applied || report.HolonsAdded != 4 {
+		t.Errorf("Expected a dry run planning 4 holons, got:\n%s", report)
+	}
+	if ids, _ := rebuilt.DB.ListAllHolonIDs(context.Background()); len(ids) != 0 {
+		t.Errorf("Dry run wrote holons: %v", ids)
+	}
+}
+
+func TestRebuildDB_RestoresDRRLifecycle(t *testing.T) {
+	original, root := setupProjectedProject(t)
+	ctx := context.Background()
+	if _, err := original.FinalizeDecision("Cache Choice v2", "use-lru", nil, "Context", "Decision", "Rationale", "Consequences", "", "", []string{"cache-choice"}); err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+
+	rebuilt := freshTools(t, root)
+	report, err := rebuilt.RebuildDB(false)
+	if err != nil || !report.Applied {
+		t.Fatalf("RebuildDB failed: %v\n%s", err, report)
+	}
+	for id, want := range map[string]string{"cache-choice": DRRSuperseded, "cache-choice-v2": DRRAccepted} {
+		if h, _ := rebuilt.DB.GetHolon(ctx, id); h.Status.String != want {
+			t.Errorf("Expected %s to be restored as %s, got %q", id, want, h.Status.String)
+		}
+	}
+	if by := rebuilt.supersededBy(ctx, "cache-choice"); len(by) != 1 || by[0] != "cache-choice-v2" {
+		t.Errorf("Expected the supersedes relation to be restored, got %v", by)
+	}
+
+	// A status changed in the database but not in the files is a conflict.
+	if err := rebuilt.DB.UpdateHolonStatus(ctx, "cache-choice-v2", DRRDeprecated); err != nil {
+		t.Fatal(err)
+	}
+	report, _ = rebuilt.RebuildDB(true)
+	if len(report.Conflicts) != 1 || !strings.Contains(report.Conflicts[0], `status "deprecated" in database, "accepted" in files`) {
+		t.Errorf("Expected a status conflict, got:\n%s", report)
+	}
+}
+
+// failingRelations is a repository whose relation writes fail.
+type failingRelations struct {
+	db.Repository
+}
+
+func (f failingRelations) WithTx(ctx context.Context, fn func(db.Repository) error) error {
+	return f.Repository.WithTx(ctx, func(repo db.Repository) error {
+		return fn(failingRelations{repo})
+	})
+}
+
+func (f failingRelations) CreateRelation(ctx context.Context, sourceID, relationType, targetID string, cl int) error {
+	return fmt.Errorf("relation %s %s %s rejected", sourceID, relationType, targetID)
+}
+
+// lockedHolons is a repository whose holon reads fail.
+type lockedHolons struct {
+	db.Repository
+}
+
+func (l lockedHolons) GetHolon(ctx context.Context, id string) (db.Holon, error) {
+	return db.Holon{}, fmt.Errorf("database is locked")
+}
+
+func TestRebuildDB_ReturnsLookupErrors(t *testing.T) {
+	_, root := setupProjectedProject(t)
+	rebuilt := freshTools(t, root)
+	rebuilt.DB = lockedHolons{rebuilt.DB}
+
+	report, err := rebuilt.RebuildDB(false)
+	if err == nil || !strings.Contains(err.Error(), "database is locked") {
+		t.Fatalf("Expected the lookup error to be returned, got %v (%+v)", err, report)
+	}
+}
+
+func TestRebuildDB_FailureLeavesDatabaseUnchanged(t *testing.T) {
+	_, root := setupProjectedProject(t)
+	rebuilt := freshTools(t, root)
+	rebuilt.DB = failingRelations{rebuilt.DB}
+	ctx := context.Background()
+
+	report, err := rebuilt.RebuildDB(false)
+	if err == nil || !strings.Contains(err.Error(), "failed to restore relation") {
+		t.Fatalf("Expected the relation failure to be returned, got %v", err)
+	}
+	if report.Applied {
+		t.Error("A failed rebuild must not be reported as applied")
+	}
+	if ids, _ := rebuilt.DB.ListAllHolonIDs(ctx); len(ids) != 0 {
+		t.Errorf("Expected no holons after a failed rebuild, got %v", ids)
+	}
+	if ev, _ := rebuilt.DB.GetEvidence(ctx, "use-lru"); len(ev) != 0 {
+		t.Errorf("Expected no evidence after a failed rebuild, got %d record(s)", len(ev))
+	}
+}
+
+func TestRebuildDB_SkipsDanglingDecisionTargets(t *testing.T) {
+	_, root := setupProjectedProject(t)
+	if err := os.Remove(filepath.Join(root, ".quint", "knowledge", "L2", "use-lru.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	rebuilt := freshTools(t, root)
+	report, err := rebuilt.RebuildDB(false)
+	if err != nil || !report.Applied {
+		t.Fatalf("RebuildDB failed: %v\n%s", err, report)
+	}
+	if !strings.Contains(strings.Join(report.Skipped, "\n"), "cache-choice: rejected hypothesis use-lru not found") {
+		t.Errorf("Expected the dangling rejects target to be skipped, got %v", report.Skipped)
+	}
+	if rels, _ := rebuilt.DB.GetRelationsByTarget(context.Background(), "use-lru", "rejects"); len(rels) != 0 {
+		t.Errorf("Expected no rejects relation to a missing holon, got %+v", rels)
+	}
+}
+
+func TestRebuildDB_StrictModeCanStartNewCycle(t *testing.T) {
+	tools, _, root := setupTools(t)
+	if _, err := tools.ProposeHypothesis("Alpha", "Content", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.VerifyHypothesis("alpha", "{}", "PASS"); err != nil {
+		t.Fatalf("VerifyHypothesis failed: %v", err)
+	}
+	if _, err := tools.ManageEvidence(PhaseInduction, "add", "alpha", "internal", "ok", "PASS", "L2", "test-runner", ""); err != nil {
+		t.Fatalf("ManageEvidence failed: %v", err)
+	}
+
+	rebuilt := freshTools(t, root)
+	if _, err := rebuilt.RebuildDB(false); err != nil {
+		t.Fatalf("RebuildDB failed: %v", err)
+	}
+	if phase := rebuilt.currentPhase(); phase != PhaseInduction {
+		t.Fatalf("Expected derived phase INDUCTION, got %s", phase)
+	}
+
+	rebuilt.FSM.State.StrictMode = true
+	abductor := RoleAssignment{Role: RoleAbductor, SessionID: "s1", Context: "default"}
+	if err := rebuilt.AuthorizeToolCall("quint_propose", nil, abductor, nil); err != nil {
+		t.Fatalf("Expected a rebuilt project to accept a new proposal, got %v", err)
+	}
+
+	server := NewServer(rebuilt)
+	result := server.CallTool("quint_propose", map[string]interface{}{
+		"title": "Beta", "content": "c", "kind": "system", "scope": "global", "rationale": "{}",
+		"role": "Abductor", "session_id": "s1",
+	})
+	if result.IsError {
+		t.Fatalf("quint_propose failed: %s", result.Content[0].Text)
+	}
+	last, err := rebuilt.DB.GetLastPhaseTransition(context.Background(), "default")
+	if err != nil || last.FromPhase != string(PhaseIdle) || last.ToPhase != string(PhaseAbduction) {
+		t.Errorf("Expected the new cycle to be recorded from IDLE, got %+v (%v)", last, err)
+	}
+}
@@ -0,0 +1,253 @@
+package fpf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// Resource URIs exposed over MCP. Holons and DRRs are addressed by holon ID,
+// evidence by its file name (which is also its evidence ID).
+const (
+	ResourceScheme         = "quint://"
+	holonResourcePrefix    = ResourceScheme + "holon/"
+	evidenceResourcePrefix = ResourceScheme + "evidence/"
+	decisionResourcePrefix = ResourceScheme + "decision/"
+	ContextResourceURI     = ResourceScheme + "context"
+)
+
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// HolonResourceURI returns the resource URI for a holon.
+func HolonResourceURI(holonID string) string {
+	return holonResourcePrefix + holonID
+}
+
+func ResourceTemplates() []ResourceTemplate {
+	return []ResourceTemplate{
+		{
+			URITemplate: holonResourcePrefix + "{id}",
+			Name:        "Holon",
+			Description: "Hypothesis or decision by holon ID, as its markdown projection",
+			MimeType:    "text/markdown",
+		},
+		{
+			URITemplate: evidenceResourcePrefix + "{id}",
+			Name:        "Evidence",
+			Description: "Evidence record by evidence ID",
+			MimeType:    "text/markdown",
+		},
+		{
+			URITemplate: decisionResourcePrefix + "{id}",
+			Name:        "Decision (DRR)",
+			Description: "Design Rationale Record by DRR holon ID",
+			MimeType:    "text/markdown",
+		},
+	}
+}
+
+// ListResources enumerates the knowledge base: bounded context, hypotheses,
+// DRRs and evidence.
+func (t *Tools) ListResources() ([]Resource, error) {
+	var resources []Resource
+
+	if _, err := os.Stat(filepath.Join(t.GetFPFDir(), "context.md")); err == nil {
+		resources = append(resources, Resource{
+			URI:         ContextResourceURI,
+			Name:        "Bounded Context",
+			Description: "Vocabulary and invariants (context.md)",
+			MimeType:    "text/markdown",
+		})
+	}
+
+	if t.DB == nil {
+		return resources, nil
+	}
+
+	ctx := context.Background()
+	holons, err := t.DB.ListHolons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range holons {
+		if h.Layer == "DRR" {
+			resources = append(resources, Resource{
+				URI:         decisionResourcePrefix + h.ID,
+				Name:        h.Title,
+				Description: "Design Rationale Record",
+				MimeType:    "text/markdown",
+			})
+			continue
+		}
+		resources = append(resources, Resource{
+			URI:         HolonResourceURI(h.ID),
+			Name:        h.Title,
+			Description: fmt.Sprintf("%s hypothesis (%s)", h.Layer, h.Kind.String),
+			MimeType:    "text/markdown",
+		})
+	}
+
+	evidence, err := t.DB.ListAllEvidence(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range evidence {
+		resources = append(resources, Resource{
+			URI:         evidenceResourcePrefix + e.ID,
+			Name:        e.ID,
+			Description: fmt.Sprintf("%s evidence for %s (%s)", e.Type, e.HolonID, e.Verdict),
+			MimeType:    "text/markdown",
+		})
+	}
+
+	return resources, nil
+}
+
+// ReadResource resolves a quint:// URI to its markdown content.
+func (t *Tools) ReadResource(uri string) (ResourceContents, error) {
+	var text string
+	var err error
+
+	switch {
+	case uri == ContextResourceURI:
+		text, err = readFileString(filepath.Join(t.GetContextDir(), "context.md"))
+	case strings.HasPrefix(uri, holonResourcePrefix):
+		text, err = t.readHolonResource(strings.TrimPrefix(uri, holonResourcePrefix))
+	case strings.HasPrefix(uri, decisionResourcePrefix):
+		text, err = t.readDecisionResource(strings.TrimPrefix(uri, decisionResourcePrefix))
+	case strings.HasPrefix(uri, evidenceResourcePrefix):
+		text, err = t.readEvidenceResource(strings.TrimPrefix(uri, evidenceResourcePrefix))
+	default:
+		return ResourceContents{}, fmt.Errorf("unknown resource: %s", uri)
+	}
+	if err != nil {
+		return ResourceContents{}, err
+	}
+
+	return ResourceContents{URI: uri, MimeType: "text/markdown", Text: text}, nil
+}
+
+func (t *Tools) readHolonResource(id string) (string, error) {
+	if !isSafeResourceID(id) {
+		return "", fmt.Errorf("invalid holon id: %s", id)
+	}
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+
+	holon, err := t.DB.GetHolon(context.Background(), id)
+	if err != nil {
+		return "", fmt.Errorf("holon not found: %s", id)
+	}
+	if holon.Layer == "DRR" {
+		return t.readDecisionResource(id)
+	}
+
+	path := filepath.Join(t.contextDir(holon.ContextID), "knowledge", holon.Layer, id+".md")
+	if text, err := readFileString(path); err == nil {
+		return text, nil
+	}
+
+	// Projection missing: fall back to what the database knows.
+	return fmt.Sprintf("# Hypothesis: %s\n\nLayer: %s\nKind: %s\nScope: %s\n\n%s",
+		holon.Title, holon.Layer, holon.Kind.String, holon.Scope.String, holon.Content), nil
+}
+
+func (t *Tools) readDecisionResource(id string) (string, error) {
+	if !isSafeResourceID(id) {
+		return "", fmt.Errorf("invalid decision id: %s", id)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(t.holonContextDir(id), "decisions", "DRR-*-"+id+".md"))
+	if len(matches) > 0 {
+		sort.Strings(matches)
+		return readFileString(matches[len(matches)-1])
+	}
+
+	if t.DB != nil {
+		holon, err := t.DB.GetHolon(context.Background(), id)
+		if err == nil && holon.Layer == "DRR" {
+			return holon.Content, nil
+		}
+	}
+	return "", fmt.Errorf("decision not found: %s", id)
+}
+
+func (t *Tools) readEvidenceResource(id string) (string, error) {
+	if !isSafeResourceID(id) {
+		return "", fmt.Errorf("invalid evidence id: %s", id)
+	}
+
+	var e db.Evidence
+	var found bool
+	evidenceDir := t.GetContextDir()
+	if t.DB != nil {
+		if ev, err := t.DB.GetEvidenceByID(context.Background(), id); err == nil {
+			e, found = ev, true
+			evidenceDir = t.holonContextDir(ev.HolonID)
+		}
+	}
+
+	if text, err := readFileString(filepath.Join(evidenceDir, "evidence", id)); err == nil {
+		return text, nil
+	}
+
+	if found {
+		return fmt.Sprintf("# Evidence: %s\n\nTarget: %s\nType: %s\nVerdict: %s\n\n%s",
+			e.ID, e.HolonID, e.Type, e.Verdict, e.Content), nil
+	}
+	return "", fmt.Errorf("evidence not found: %s", id)
+}
+
+// holonContextID returns the context that owns holonID, falling back to the
+// active context.
+func (t *Tools) holonContextID(holonID string) string {
+	if t.DB != nil {
+		if holon, err := t.DB.GetHolon(context.Background(), holonID); err == nil && holon.ContextID != "" {
+			return holon.ContextID
+		}
+	}
+	return t.ContextID()
+}
+
+// holonContextDir returns the workspace of the context that owns holonID,
+// falling back to the active context.
+func (t *Tools) holonContextDir(holonID string) string {
+	return t.contextDir(t.holonContextID(holonID))
+}
+
+// isSafeResourceID rejects IDs that would escape the .quint directory.
+func isSafeResourceID(id string) bool {
+	return id != "" && !strings.Contains(id, "/") && !strings.Contains(id, "\\") && !strings.Contains(id, "..")
+}
+
+func readFileString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,115 @@
+package fpf
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestListResources(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if _, err := tools.RecordContext("API: public surface.", "1. No downtime."); err != nil {
+		t.Fatalf("RecordContext failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis("Use Redis", "Cache with Redis", "backend", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.VerifyHypothesis("use-redis", "{}", "PASS"); err != nil {
+		t.Fatalf("VerifyHypothesis failed: %v", err)
+	}
+	if _, err := tools.ManageEvidence(PhaseInduction, "add", "use-redis", "internal", "bench ok", "PASS", "L2", "test-runner", ""); err != nil {
+		t.Fatalf("ManageEvidence failed: %v", err)
+	}
+
+	resources, err := tools.ListResources()
+	if err != nil {
+		t.Fatalf("ListResources failed: %v", err)
+	}
+
+	uris := make(map[string]bool)
+	for _, r := range resources {
+		uris[r.URI] = true
+	}
+	if !uris[ContextResourceURI] {
+		t.Error("Expected context resource")
+	}
+	if !uris["quint://holon/use-redis"] {
+		t.Error("Expected holon resource for use-redis")
+	}
+
+	hasEvidence := false
+	for uri := range uris {
+		if strings.HasPrefix(uri, "quint://evidence/") && strings.Contains(uri, "use-redis") {
+			hasEvidence = true
+		}
+	}
+	if !hasEvidence {
+		t.Errorf("Expected evidence resource, got %v", uris)
+	}
+}
+
+func TestReadResource(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if _, err := tools.ProposeHypothesis("Use Redis", "Cache with Redis", "backend", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	contents, err := tools.ReadResource("quint://holon/use-redis")
+	if err != nil {
+		t.Fatalf("ReadResource failed: %v", err)
+	}
+	if !strings.Contains(contents.Text, "# Hypothesis: Use Redis") {
+		t.Errorf("Expected hypothesis markdown, got %q", contents.Text)
+	}
+	if contents.MimeType != "text/markdown" {
+		t.Errorf("Expected text/markdown, got %s", contents.MimeType)
+	}
+
+	for _, uri := range []string{
+		"quint://holon/missing",
+		"quint://evidence/../quint.db",
+		"quint://unknown/x",
+	} {
+		if _, err := tools.ReadResource(uri); err == nil {
+			t.Errorf("Expected error reading %s", uri)
+		}
+	}
+}
+
+func TestResourceSubscription_NotifiesOnMove(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	var received []JSONRPCNotification
+	sess := &Session{ID: "test", notify: func(n JSONRPCNotification) { received = append(received, n) }}
+
+	if _, err := tools.ProposeHypothesis("Use Redis", "Cache with Redis", "backend", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	params, _ := json.Marshal(map[string]string{"uri": "quint://holon/use-redis"})
+	resp := server.Handle(sess, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "resources/subscribe", Params: params})
+	if resp == nil || resp.Error != nil {
+		t.Fatalf("resources/subscribe failed: %+v", resp)
+	}
+
+	if _, err := tools.VerifyHypothesis("use-redis", "{}", "PASS"); err != nil {
+		t.Fatalf("VerifyHypothesis failed: %v", err)
+	}
+
+	if len(received) == 0 {
+		t.Fatal("Expected notifications/resources/updated after verification")
+	}
+	if received[0].Method != "notifications/resources/updated" {
+		t.Errorf("Unexpected notification method %s", received[0].Method)
+	}
+
+	count := len(received)
+	server.Handle(sess, JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "resources/unsubscribe", Params: params})
+	tools.notifyHolonChanged("use-redis")
+	if len(received) != count {
+		t.Error("Expected no notifications after unsubscribe")
+	}
+}
@@ -0,0 +1,215 @@
+package fpf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// toolTargetPhases maps phase-moving tools to the phase they enter.
+var toolTargetPhases = map[string]Phase{
+	"quint_propose":        PhaseAbduction,
+	"quint_verify":         PhaseDeduction,
+	"quint_test":           PhaseInduction,
+	"quint_ingest_results": PhaseInduction,
+	"quint_audit":          PhaseAudit,
+	"quint_decide":         PhaseDecision,
+}
+
+// toolContextID returns the bounded context a tool call acts on: the context
+// quint_propose files into, the context of the holon other tools target, or
+// the active context.
+func (t *Tools) toolContextID(toolName string, args map[string]string) string {
+	var holonID string
+	switch toolName {
+	case "quint_propose":
+		if args["context"] != "" {
+			return args["context"]
+		}
+	case "quint_verify", "quint_test", "quint_ingest_results", "quint_audit", "quint_characterize":
+		holonID = args["hypothesis_id"]
+	case "quint_decide":
+		holonID = args["winner_id"]
+	case "quint_drr_status":
+		holonID = args["drr_id"]
+	case "quint_check_decay":
+		holonID = args["deprecate"]
+	}
+	if holonID != "" {
+		return t.holonContextID(holonID)
+	}
+	return t.ContextID()
+}
+
+// roleAwareTools accept role, session_id and evidence arguments.
+var roleAwareTools = map[string]bool{
+	"quint_propose":        true,
+	"quint_verify":         true,
+	"quint_test":           true,
+	"quint_ingest_results": true,
+	"quint_audit":          true,
+	"quint_decide":         true,
+	"quint_record_context": true,
+	"quint_actualize":      true,
+	"quint_context":        true,
+	"quint_check_decay":    true,
+	"quint_drr_status":     true,
+	"quint_characterize":   true,
+}
+
+var knownRoles = []Role{RoleAbductor, RoleDeductor, RoleInductor, RoleAuditor, RoleDecider}
+
+// TransitionError is returned when strict mode rejects a tool call.
+type TransitionError struct {
+	Tool       string `json:"tool"`
+	Role       Role   `json:"role,omitempty"`
+	SessionID  string `json:"session_id,omitempty"`
+	From       Phase  `json:"from"`
+	To         Phase  `json:"to"`
+	Reason     string `json:"reason"`
+	Suggestion string `json:"suggestion"`
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("Transition denied for %s (%s -> %s): %s. Suggestion: %s", e.Tool, e.From, e.To, e.Reason, e.Suggestion)
+}
+
+// IsMutatingTool reports whether a tool call changes the knowledge base or
+// FSM state. quint_init is excluded: it bootstraps the database that holds
+// the strict mode flag.
+func IsMutatingTool(name string, args map[string]string) bool {
+	if _, ok := toolTargetPhases[name]; ok {
+		return true
+	}
+	switch name {
+	case "quint_record_context", "quint_actualize", "quint_drr_status", "quint_characterize":
+		return true
+	case "quint_context":
+		return args["action"] == "create" || args["action"] == "switch"
+	case "quint_check_decay":
+		return args["deprecate"] != "" || args["waive_id"] != ""
+	}
+	return false
+}
+
+// AuthorizeToolCall enforces strict mode for one tool call. It returns nil
+// when strict mode is off or the call does not mutate state. On success the
+// assignment is recorded as the context's active role.
+func (t *Tools) AuthorizeToolCall(toolName string, args map[string]string, assignment RoleAssignment, evidence *EvidenceStub) error {
+	if !IsMutatingTool(toolName, args) {
+		return nil
+	}
+	contextID := t.toolContextID(toolName, args)
+	fsm, err := t.contextFSM(contextID)
+	if err != nil {
+		return err
+	}
+	if !fsm.State.StrictMode {
+		return nil
+	}
+
+	from := t.startPhase(contextID, toolName)
+	to, ok := toolTargetPhases[toolName]
+	if !ok {
+		to = from
+	}
+
+	deny := func(reason, suggestion string) error {
+		return &TransitionError{
+			Tool:       toolName,
+			Role:       assignment.Role,
+			SessionID:  assignment.SessionID,
+			From:       from,
+			To:         to,
+			Reason:     reason,
+			Suggestion: suggestion,
+		}
+	}
+
+	role, valid := expectedRole(from, to)
+	if !valid {
+		return deny(fmt.Sprintf("no role may move the context from %s to %s", from, to),
+			"Check the current phase with quint_status and follow the FPF cycle")
+	}
+
+	if assignment.Role == "" || assignment.SessionID == "" {
+		return deny("role and session_id are required in strict mode",
+			fmt.Sprintf("Declare the role you act in (%s) and your session_id", role))
+	}
+	if !isKnownRole(assignment.Role) {
+		return deny(fmt.Sprintf("unknown role %q", assignment.Role),
+			fmt.Sprintf("Use one of %v", knownRoles))
+	}
+
+	if evidence != nil && evidence.URI != "" && !filepath.IsAbs(evidence.URI) {
+		anchored := *evidence
+		anchored.URI = filepath.Join(t.RootDir, evidence.URI)
+		evidence = &anchored
+	}
+
+	if ok, reason := fsm.CanTransitionFrom(from, to, assignment, evidence); !ok {
+		suggestion := fmt.Sprintf("Act as %s", role)
+		if from != to {
+			suggestion += fmt.Sprintf(" and attach an evidence anchor for %s", to)
+		}
+		return deny(reason, suggestion)
+	}
+
+	fsm.State.ActiveRole = assignment
+	if t.DB != nil {
+		if err := fsm.SaveState(contextID); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save active role: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// actor is the audit_log actor for the current tool call.
+func (t *Tools) actor() string {
+	switch {
+	case t.Caller.Role == "":
+		return "agent"
+	case t.Caller.SessionID == "":
+		return string(t.Caller.Role)
+	default:
+		return fmt.Sprintf("%s@%s", t.Caller.Role, t.Caller.SessionID)
+	}
+}
+
+// expectedRole names the role that may move from one phase to another,
+// reporting false when no transition rule connects them.
+func expectedRole(from, to Phase) (string, bool) {
+	if from == to {
+		var roles []string
+		for _, r := range knownRoles {
+			if isValidRoleForPhase(from, r) {
+				roles = append(roles, string(r))
+			}
+		}
+		if len(roles) == 1 {
+			return roles[0], true
+		}
+		return fmt.Sprintf("one of %v", roles), true
+	}
+	for _, rule := range transitionRules {
+		if rule.From == from && rule.To == to {
+			return string(rule.Role), true
+		}
+	}
+	return "", false
+}
+
+func isKnownRole(role Role) bool {
+	for _, r := range knownRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRoleAwareTool reports whether a tool accepts role, session_id and
+// evidence arguments.
+func IsRoleAwareTool(name string) bool {
+	return roleAwareTools[name]
+}
@@ -0,0 +1,184 @@
+package fpf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsMutatingTool(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]string
+		want bool
+	}{
+		{"quint_propose", nil, true},
+		{"quint_decide", nil, true},
+		{"quint_record_context", nil, true},
+		{"quint_status", nil, false},
+		{"quint_query", nil, false},
+		{"quint_init", nil, false},
+		{"quint_context", map[string]string{"action": "list"}, false},
+		{"quint_context", map[string]string{"action": "switch"}, true},
+		{"quint_check_decay", map[string]string{}, false},
+		{"quint_check_decay", map[string]string{"waive_id": "e1"}, true},
+		{"quint_characterize", nil, true},
+		{"quint_compare", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsMutatingTool(tt.name, tt.args); got != tt.want {
+			t.Errorf("IsMutatingTool(%s, %v) = %t, want %t", tt.name, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorizeToolCall_StrictModeOff(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if err := tools.AuthorizeToolCall("quint_verify", nil, RoleAssignment{}, nil); err != nil {
+		t.Errorf("Expected no enforcement outside strict mode, got %v", err)
+	}
+}
+
+func TestAuthorizeToolCall_StrictMode(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	fsm.State.StrictMode = true
+	session := func(r Role) RoleAssignment {
+		return RoleAssignment{Role: r, SessionID: "s1", Context: "default"}
+	}
+
+	var te *TransitionError
+	err := tools.AuthorizeToolCall("quint_propose", nil, RoleAssignment{Role: RoleAbductor}, nil)
+	if !errors.As(err, &te) || !strings.Contains(te.Reason, "session_id") {
+		t.Fatalf("Expected missing session error, got %v", err)
+	}
+
+	err = tools.AuthorizeToolCall("quint_propose", nil, session("Oracle"), nil)
+	if !errors.As(err, &te) || !strings.Contains(te.Reason, "unknown role") {
+		t.Fatalf("Expected unknown role error, got %v", err)
+	}
+
+	if err := tools.AuthorizeToolCall("quint_verify", nil, session(RoleDeductor), nil); !errors.As(err, &te) {
+		t.Fatalf("Expected IDLE -> DEDUCTION to be denied, got %v", err)
+	}
+	if te.From != PhaseIdle || te.To != PhaseDeduction {
+		t.Errorf("Unexpected transition in error: %s -> %s", te.From, te.To)
+	}
+
+	if err := tools.AuthorizeToolCall("quint_status", nil, RoleAssignment{}, nil); err != nil {
+		t.Errorf("Read-only tools must not be enforced, got %v", err)
+	}
+
+	if err := tools.AuthorizeToolCall("quint_propose", nil, session(RoleAbductor), nil); err != nil {
+		t.Fatalf("Expected Abductor to propose from IDLE, got %v", err)
+	}
+	if _, err := tools.ProposeHypothesis("Strict Idea", "Content", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	if err := tools.AuthorizeToolCall("quint_verify", nil, session(RoleDeductor), nil); err == nil {
+		t.Error("Expected DEDUCTION without an evidence anchor to be denied")
+	}
+	anchor := &EvidenceStub{Type: "directory", URI: filepath.Join(".quint", "knowledge", "L0")}
+	if err := tools.AuthorizeToolCall("quint_verify", nil, session(RoleDeductor), anchor); err != nil {
+		t.Errorf("Expected relative evidence anchor to be accepted, got %v", err)
+	}
+
+	reloaded, err := LoadState("default", tools.DB)
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if reloaded.State.ActiveRole.Role != RoleDeductor || reloaded.State.ActiveRole.SessionID != "s1" {
+		t.Errorf("Expected active role Deductor@s1, got %+v", reloaded.State.ActiveRole)
+	}
+	if !reloaded.State.StrictMode {
+		t.Error("Expected strict mode to persist")
+	}
+}
+
+func TestHandleToolsCall_StrictModeRejectsAndAudits(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	fsm.State.StrictMode = true
+	server := NewServer(tools)
+
+	call := func(args string) CallToolResult {
+		params := json.RawMessage(`{"name":"quint_propose","arguments":` + args + `}`)
+		resp := server.Handle(nil, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: params})
+		result, ok := resp.Result.(CallToolResult)
+		if !ok {
+			t.Fatalf("Unexpected result type %T", resp.Result)
+		}
+		return result
+	}
+
+	result := call(`{"title":"Denied","content":"c","kind":"system","scope":"global","rationale":"{}"}`)
+	if !result.IsError {
+		t.Fatal("Expected strict mode to reject a call without role")
+	}
+	if _, ok := result.StructuredContent.(*TransitionError); !ok {
+		t.Errorf("Expected structured TransitionError, got %T", result.StructuredContent)
+	}
+
+	result = call(`{"title":"Allowed","content":"c","kind":"system","scope":"global","rationale":"{}","role":"Abductor","session_id":"s1"}`)
+	if result.IsError {
+		t.Fatalf("Expected call to succeed, got %s", result.Content[0].Text)
+	}
+
+	entries, err := tools.DB.GetAuditLogByTarget(context.Background(), "allowed")
+	if err != nil || len(entries) == 0 {
+		t.Fatalf("Expected audit entries for allowed, got %v (%v)", entries, err)
+	}
+	if entries[0].Actor != "Abductor@s1" {
+		t.Errorf("Expected actor Abductor@s1, got %s", entries[0].Actor)
+	}
+	if tools.Caller != (RoleAssignment{}) {
+		t.Errorf("Expected caller to be reset after the call, got %+v", tools.Caller)
+	}
+}
+
+func TestHandleToolsCall_ProposeAuthorizesTargetContext(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	if _, err := tools.ManageContext("create", "payments", ""); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	payments, err := LoadState("payments", tools.DB)
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	payments.State.StrictMode = true
+	if err := payments.SaveState("payments"); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	result := server.CallTool("quint_propose", map[string]interface{}{
+		"title": "Denied", "content": "c", "kind": "system", "scope": "global", "rationale": "{}", "context": "payments",
+	})
+	if !result.IsError {
+		t.Fatal("Expected strict mode of payments to reject a call without role")
+	}
+
+	result = server.CallTool("quint_propose", map[string]interface{}{
+		"title": "Stripe", "content": "c", "kind": "system", "scope": "global", "rationale": "{}", "context": "payments",
+		"role": "Abductor", "session_id": "s1",
+	})
+	if result.IsError {
+		t.Fatalf("Expected call to succeed, got %s", result.Content[0].Text)
+	}
+
+	payments, err = LoadState("payments", tools.DB)
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if payments.State.ActiveRole.Role != RoleAbductor || !payments.State.StrictMode {
+		t.Errorf("Expected payments state to keep strict mode and record Abductor, got %+v", payments.State)
+	}
+	if tools.FSM.State.ActiveRole.Role != "" || tools.FSM.State.StrictMode {
+		t.Errorf("Expected default state untouched, got %+v", tools.FSM.State)
+	}
+}
@@ -0,0 +1,126 @@
+package fpf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// QueryFilters narrows a quint_query search. Zero values mean "any"; a nil
+// MaxR is no upper bound.
+type QueryFilters struct {
+	Layer           string
+	Kind            string
+	Scope           string
+	DecisionContext string
+	MinR            float64
+	MaxR            *float64
+	Limit           int
+}
+
+// QueryResult is a ranked search hit with the live R_eff of its holon.
+type QueryResult struct {
+	db.SearchResult
+	REff float64
+}
+
+// SearchKnowledge runs a full-text query and applies the R_eff range against
+// freshly calculated scores rather than the cached column. The scores are
+// calculated over an overlay, so searching never writes to the database.
+func (t *Tools) SearchKnowledge(query string, filters QueryFilters) ([]QueryResult, error) {
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	maxR := 1.0
+	if filters.MaxR != nil {
+		maxR = *filters.MaxR
+	}
+
+	ctx := context.Background()
+	// Over-fetch so that the R_eff filter below still leaves enough hits.
+	hits, err := t.DB.Search(ctx, db.SearchParams{
+		Query:           query,
+		Layer:           filters.Layer,
+		Kind:            filters.Kind,
+		Scope:           filters.Scope,
+		DecisionContext: filters.DecisionContext,
+		Limit:           limit * 5,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	calc := t.FSM.NewCalculator(assurance.NewOverlay(t.DB))
+	scores := make(map[string]float64)
+
+	var results []QueryResult
+	for _, hit := range hits {
+		r, ok := scores[hit.HolonID]
+		if !ok {
+			report, err := calc.CalculateReliability(ctx, hit.HolonID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate R_eff for %s: %w", hit.HolonID, err)
+			}
+			r = report.FinalScore
+			scores[hit.HolonID] = r
+		}
+		if r < filters.MinR || r > maxR {
+			continue
+		}
+		results = append(results, QueryResult{SearchResult: hit, REff: r})
+		if len(results) == limit {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+func (t *Tools) Query(query string, filters QueryFilters) (string, error) {
+	defer t.RecordWork("Query", time.Now())
+
+	results, err := t.SearchKnowledge(query, filters)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("## Search Results: %q\n\n", query))
+	if len(results) == 0 {
+		out.WriteString("No matching holons, evidence or decisions found.\n")
+		return out.String(), nil
+	}
+
+	for i, r := range results {
+		label := r.HolonID
+		if r.DocType == db.DocTypeEvidence {
+			label = fmt.Sprintf("%s → %s", r.DocID, r.HolonID)
+		}
+		out.WriteString(fmt.Sprintf("%d. [%s] **%s** — %s\n", i+1, r.DocType, label, r.Title))
+
+		meta := []string{r.Layer}
+		if r.Kind != "" {
+			meta = append(meta, r.Kind)
+		}
+		if r.Scope != "" {
+			meta = append(meta, "scope: "+r.Scope)
+		}
+		meta = append(meta, fmt.Sprintf("R_eff: %.2f", r.REff))
+		out.WriteString(fmt.Sprintf("   %s\n", strings.Join(meta, " | ")))
+
+		if snippet := strings.Join(strings.Fields(r.Snippet), " "); snippet != "" {
+			out.WriteString(fmt.Sprintf("   > %s\n", snippet))
+		}
+	}
+
+	return out.String(), nil
+}
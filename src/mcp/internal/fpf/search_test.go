@@ -0,0 +1,85 @@
+package fpf
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if _, err := tools.ProposeHypothesis("Redis caching", "Cache hot reads in Redis", "backend", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis("CDN caching", "Serve assets from the edge", "frontend", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.ManageEvidence(PhaseInduction, "add", "redis-caching", "benchmark", "Redis p99 latency 2ms", "PASS", "L1", "bench", ""); err != nil {
+		t.Fatalf("ManageEvidence failed: %v", err)
+	}
+
+	output, err := tools.Query("caching", QueryFilters{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if !strings.Contains(output, "redis-caching") || !strings.Contains(output, "cdn-caching") {
+		t.Errorf("Expected both hypotheses in results:\n%s", output)
+	}
+
+	output, err = tools.Query("latency", QueryFilters{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if !strings.Contains(output, "[evidence]") || !strings.Contains(output, "→ redis-caching") {
+		t.Errorf("Expected evidence hit pointing at redis-caching:\n%s", output)
+	}
+
+	// redis-caching has passing evidence (R=1.0); cdn-caching has none (R=0.0).
+	results, err := tools.SearchKnowledge("caching", QueryFilters{MinR: 0.5})
+	if err != nil {
+		t.Fatalf("SearchKnowledge failed: %v", err)
+	}
+	for _, r := range results {
+		if r.HolonID == "cdn-caching" {
+			t.Errorf("cdn-caching should be excluded by min_r, got %+v", r)
+		}
+	}
+	if len(results) == 0 {
+		t.Error("Expected redis-caching to pass min_r filter")
+	}
+
+	// An explicit max_r of 0 keeps only unsupported holons.
+	zero := 0.0
+	results, err = tools.SearchKnowledge("caching", QueryFilters{MaxR: &zero})
+	if err != nil {
+		t.Fatalf("SearchKnowledge failed: %v", err)
+	}
+	if len(results) != 1 || results[0].HolonID != "cdn-caching" {
+		t.Errorf("Expected only cdn-caching with max_r=0, got %+v", results)
+	}
+
+	// Searching must not refresh the cached score.
+	if holon, _ := tools.DB.GetHolon(context.Background(), "redis-caching"); holon.CachedRScore.Float64 != 0 {
+		t.Errorf("Expected search to leave cached_r_score alone, got %v", holon.CachedRScore.Float64)
+	}
+
+	output, err = tools.Query("nonexistentterm", QueryFilters{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if !strings.Contains(output, "No matching") {
+		t.Errorf("Expected empty result message, got:\n%s", output)
+	}
+}
+
+func TestCheckPreconditions_Query(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	if err := tools.CheckPreconditions("quint_query", map[string]string{}); err == nil {
+		t.Error("Expected precondition error without query")
+	}
+	if err := tools.CheckPreconditions("quint_query", map[string]string{"query": "x"}); err != nil {
+		t.Errorf("Unexpected precondition error: %v", err)
+	}
+}
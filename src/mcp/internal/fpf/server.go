@@ -4,7 +4,11 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sync"
+
+	"github.com/m0n0x41d/quint-code/assurance"
 )
 
 type JSONRPCRequest struct {
@@ -33,8 +37,9 @@ type Tool struct {
 }
 
 type CallToolResult struct {
-	Content []ContentItem `json:"content"`
-	IsError bool          `json:"isError,omitempty"`
+	Content           []ContentItem `json:"content"`
+	StructuredContent interface{}   `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError,omitempty"`
 }
 
 type ContentItem struct {
@@ -42,84 +47,314 @@ type ContentItem struct {
 	Text string `json:"text"`
 }
 
+// JSONRPCNotification is a server-initiated message that expects no reply.
+type JSONRPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// Session is one connected MCP client. The stdio transport has exactly one;
+// the HTTP transport creates one per Mcp-Session-Id.
+type Session struct {
+	ID     string
+	notify func(JSONRPCNotification)
+}
+
+// Notify delivers a server-initiated notification to the client, if the
+// transport has a channel for it.
+func (sess *Session) Notify(method string, params interface{}) {
+	if sess == nil || sess.notify == nil {
+		return
+	}
+	sess.notify(JSONRPCNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+var supportedProtocolVersions = []string{"2025-03-26", "2024-11-05"}
+
 type Server struct {
 	tools *Tools
+	// mu serializes dispatch: Tools, the FSM and the markdown projection are
+	// not safe for concurrent use, and several HTTP clients may share them.
+	mu sync.Mutex
+
+	subsMu        sync.Mutex
+	subscriptions map[string]map[*Session]bool
+
+	prompts *PromptSet
 }
 
 func NewServer(t *Tools) *Server {
-	return &Server{tools: t}
+	s := &Server{
+		tools:         t,
+		subscriptions: make(map[string]map[*Session]bool),
+	}
+	if t != nil {
+		t.OnHolonChanged = func(holonID string) {
+			s.publishResourceUpdated(HolonResourceURI(holonID))
+		}
+	}
+	return s
+}
+
+// SetPrompts enables the prompts capability, serving the given command set.
+func (s *Server) SetPrompts(ps *PromptSet) {
+	s.prompts = ps
 }
 
+// Start serves the stdio transport: newline-delimited JSON-RPC on stdin/stdout.
 func (s *Server) Start() {
-	scanner := bufio.NewScanner(os.Stdin)
+	s.Serve(os.Stdin, os.Stdout)
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes replies
+// and notifications to w until r is exhausted.
+func (s *Server) Serve(r io.Reader, w io.Writer) {
+	var writeMu sync.Mutex
+	write := func(msg interface{}) {
+		bytes, err := json.Marshal(msg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to marshal JSON-RPC message: %v\n", err)
+			return
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if _, err := fmt.Fprintf(w, "%s\n", bytes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to write JSON-RPC message: %v\n", err)
+		}
+	}
+
+	sess := &Session{
+		ID:     "stdio",
+		notify: func(n JSONRPCNotification) { write(n) },
+	}
+
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Bytes()
 		if len(line) == 0 {
 			continue
 		}
-
-		var req JSONRPCRequest
-		if err := json.Unmarshal(line, &req); err != nil {
-			s.sendError(nil, -32700, "Parse error")
-			continue
-		}
-
-		switch req.Method {
-		case "initialize":
-			s.handleInitialize(req)
-		case "tools/list":
-			s.handleToolsList(req)
-		case "tools/call":
-			s.handleToolsCall(req)
-		case "notifications/initialized":
-			// No-op
-		default:
-			if req.ID != nil {
-				s.sendError(req.ID, -32601, "Method not found")
-			}
+		if resp := s.HandleMessage(sess, line); resp != nil {
+			write(resp)
 		}
 	}
 }
 
-func (s *Server) send(resp JSONRPCResponse) {
-	bytes, err := json.Marshal(resp)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to marshal JSON-RPC response: %v\n", err)
-		return
+// HandleMessage decodes and dispatches a single JSON-RPC message. It returns
+// nil for notifications, which get no reply.
+func (s *Server) HandleMessage(sess *Session, data []byte) *JSONRPCResponse {
+	var req JSONRPCRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return errorResponse(nil, -32700, "Parse error")
 	}
-	fmt.Printf("%s\n", string(bytes))
+	return s.Handle(sess, req)
 }
 
-func (s *Server) sendResult(id interface{}, result interface{}) {
-	s.send(JSONRPCResponse{
-		JSONRPC: "2.0",
-		ID:      id,
-		Result:  result,
-	})
+// Handle dispatches a decoded JSON-RPC request on behalf of sess.
+func (s *Server) Handle(sess *Session, req JSONRPCRequest) *JSONRPCResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result interface{}
+	var rpcErr *RPCError
+
+	switch req.Method {
+	case "initialize":
+		result = s.handleInitialize(req)
+	case "tools/list":
+		result = s.handleToolsList()
+	case "tools/call":
+		result, rpcErr = s.handleToolsCall(req)
+	case "prompts/list":
+		result, rpcErr = s.handlePromptsList()
+	case "prompts/get":
+		result, rpcErr = s.handlePromptsGet(req)
+	case "resources/list":
+		result, rpcErr = s.handleResourcesList()
+	case "resources/templates/list":
+		result = map[string]interface{}{"resourceTemplates": ResourceTemplates()}
+	case "resources/read":
+		result, rpcErr = s.handleResourcesRead(req)
+	case "resources/subscribe":
+		result, rpcErr = s.handleResourcesSubscribe(sess, req, true)
+	case "resources/unsubscribe":
+		result, rpcErr = s.handleResourcesSubscribe(sess, req, false)
+	case "notifications/initialized":
+		// No-op
+		return nil
+	default:
+		if req.ID == nil {
+			return nil
+		}
+		rpcErr = &RPCError{Code: -32601, Message: "Method not found"}
+	}
+
+	if req.ID == nil {
+		return nil
+	}
+	if rpcErr != nil {
+		return &JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return &JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
 }
 
-func (s *Server) sendError(id interface{}, code int, message string) {
-	s.send(JSONRPCResponse{
+func errorResponse(id interface{}, code int, message string) *JSONRPCResponse {
+	return &JSONRPCResponse{
 		JSONRPC: "2.0",
 		ID:      id,
 		Error:   &RPCError{Code: code, Message: message},
-	})
+	}
 }
 
-func (s *Server) handleInitialize(req JSONRPCRequest) {
-	s.sendResult(req.ID, map[string]interface{}{
-		"protocolVersion": "2024-11-05",
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
+func (s *Server) handleInitialize(req JSONRPCRequest) interface{} {
+	var params struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	_ = json.Unmarshal(req.Params, &params)
+
+	version := "2024-11-05"
+	for _, v := range supportedProtocolVersions {
+		if v == params.ProtocolVersion {
+			version = v
+			break
+		}
+	}
+
+	capabilities := map[string]interface{}{
+		"tools": map[string]interface{}{},
+		"resources": map[string]interface{}{
+			"subscribe":   true,
+			"listChanged": false,
 		},
+	}
+	if s.prompts != nil {
+		capabilities["prompts"] = map[string]interface{}{
+			"listChanged": false,
+		}
+	}
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities":    capabilities,
 		"serverInfo": map[string]string{
 			"name":    "quint-code",
 			"version": "4.0.0",
 		},
-	})
+	}
 }
 
-func (s *Server) handleToolsList(req JSONRPCRequest) {
+func (s *Server) handlePromptsList() (interface{}, *RPCError) {
+	if s.prompts == nil {
+		return nil, &RPCError{Code: -32601, Message: "Method not found"}
+	}
+	return map[string]interface{}{"prompts": s.prompts.List()}, nil
+}
+
+func (s *Server) handlePromptsGet(req JSONRPCRequest) (interface{}, *RPCError) {
+	if s.prompts == nil {
+		return nil, &RPCError{Code: -32601, Message: "Method not found"}
+	}
+
+	var params struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+		return nil, &RPCError{Code: -32602, Message: "Invalid params: name is required"}
+	}
+
+	description, messages, err := s.prompts.Get(params.Name, params.Arguments)
+	if err != nil {
+		return nil, &RPCError{Code: -32602, Message: err.Error()}
+	}
+	return map[string]interface{}{
+		"description": description,
+		"messages":    messages,
+	}, nil
+}
+
+func (s *Server) handleResourcesList() (interface{}, *RPCError) {
+	resources, err := s.tools.ListResources()
+	if err != nil {
+		return nil, &RPCError{Code: -32603, Message: err.Error()}
+	}
+	if resources == nil {
+		resources = []Resource{}
+	}
+	return map[string]interface{}{"resources": resources}, nil
+}
+
+func (s *Server) handleResourcesRead(req JSONRPCRequest) (interface{}, *RPCError) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		return nil, &RPCError{Code: -32602, Message: "Invalid params: uri is required"}
+	}
+
+	contents, err := s.tools.ReadResource(params.URI)
+	if err != nil {
+		return nil, &RPCError{Code: -32002, Message: fmt.Sprintf("Resource not found: %v", err)}
+	}
+	return map[string]interface{}{"contents": []ResourceContents{contents}}, nil
+}
+
+func (s *Server) handleResourcesSubscribe(sess *Session, req JSONRPCRequest, subscribe bool) (interface{}, *RPCError) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		return nil, &RPCError{Code: -32602, Message: "Invalid params: uri is required"}
+	}
+	if sess == nil {
+		return nil, &RPCError{Code: -32603, Message: "Subscriptions require a session"}
+	}
+
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	if subscribe {
+		if s.subscriptions[params.URI] == nil {
+			s.subscriptions[params.URI] = make(map[*Session]bool)
+		}
+		s.subscriptions[params.URI][sess] = true
+	} else {
+		delete(s.subscriptions[params.URI], sess)
+		if len(s.subscriptions[params.URI]) == 0 {
+			delete(s.subscriptions, params.URI)
+		}
+	}
+	return map[string]interface{}{}, nil
+}
+
+// publishResourceUpdated sends notifications/resources/updated to every
+// session subscribed to uri.
+func (s *Server) publishResourceUpdated(uri string) {
+	s.subsMu.Lock()
+	var targets []*Session
+	for sess := range s.subscriptions[uri] {
+		targets = append(targets, sess)
+	}
+	s.subsMu.Unlock()
+
+	for _, sess := range targets {
+		sess.Notify("notifications/resources/updated", map[string]string{"uri": uri})
+	}
+}
+
+// DropSession forgets all subscriptions held by sess.
+func (s *Server) DropSession(sess *Session) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for uri, subs := range s.subscriptions {
+		delete(subs, sess)
+		if len(subs) == 0 {
+			delete(s.subscriptions, uri)
+		}
+	}
+}
+
+func (s *Server) handleToolsList() interface{} {
 	tools := []Tool{
 		{
 			Name:        "quint_status",
@@ -149,6 +384,18 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 				"required": []string{"vocabulary", "invariants"},
 			},
 		},
+		{
+			Name:        "quint_context",
+			Description: "List, create or switch bounded contexts. Each context has its own phase, thresholds, context.md and knowledge tree (e.g. 'payments' and 'search' in a monorepo).",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"action":      map[string]interface{}{"type": "string", "enum": []string{"list", "create", "switch"}, "description": "list (default), create, or switch"},
+					"context_id":  map[string]string{"type": "string", "description": "Context ID (lowercase, digits, '-', '_'). Required for create and switch."},
+					"description": map[string]string{"type": "string", "description": "What this bounded context covers (create only)"},
+				},
+			},
+		},
 		{
 			Name:        "quint_propose",
 			Description: "Propose a new hypothesis (L0). IMPORTANT: Consider depends_on for dependencies and decision_context for grouping alternatives.",
@@ -174,7 +421,22 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 						"minimum":     1,
 						"maximum":     3,
 						"default":     3,
-						"description": "Congruence level for dependencies. CL3=same context (no penalty), CL2=similar (10% penalty), CL1=different (30% penalty).",
+						"description": "Congruence level for dependencies. CL3=same context, CL2=similar, CL1=different. The R penalty per level is the project's Φ(CL) profile (default FPF B.1.3: CL3 0%, CL2 10%, CL1 40%).",
+					},
+					"formality": map[string]interface{}{
+						"type":        "integer",
+						"minimum":     0,
+						"maximum":     9,
+						"default":     0,
+						"description": "Formality (F) of the claim: 0=informal prose, 3=structured argument, 6=formal spec, 9=machine-checked proof. The effective F is the weakest across dependencies.",
+					},
+					"context": map[string]string{
+						"type":        "string",
+						"description": "Bounded context to propose into (default: active context). Dependencies on holons from another context are capped at CL1.",
+					},
+					"claim_scope": map[string]string{
+						"type":        "string",
+						"description": "Structured claim scope (G): conditions under which the claim holds, as 'dimension=value,value; dimension=value' (e.g. 'env=prod; db=postgres,mysql') or a JSON object. Empty = holds everywhere. Narrowed by intersection across dependencies.",
 					},
 				},
 				"required": []string{"title", "content", "scope", "kind", "rationale"},
@@ -207,6 +469,23 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 				"required": []string{"hypothesis_id", "test_type", "result", "verdict"},
 			},
 		},
+		{
+			Name:        "quint_ingest_results",
+			Description: "Record validation evidence from a real test report (go test -json or JUnit XML). Tests are mapped to hypotheses by regex; verdicts are derived from outcomes (all pass → PASS, all fail → FAIL, mixed → degrade). The report path and SHA-256 are stored as carrier_ref.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path":          map[string]string{"type": "string", "description": "Report file, relative to the project root or absolute"},
+					"format":        map[string]interface{}{"type": "string", "enum": []string{ReportFormatAuto, ReportFormatGoJSON, ReportFormatJUnit}, "description": "Report format (default: auto-detect)"},
+					"hypothesis_id": map[string]string{"type": "string", "description": "Attribute every test in the report to this hypothesis"},
+					"mapping": map[string]string{
+						"type":        "string",
+						"description": "Hypothesis → test name regex, e.g. 'use-redis=^TestRedis; use-lru=TestLRU' or a JSON object",
+					},
+				},
+				"required": []string{"path"},
+			},
+		},
 		{
 			Name:        "quint_audit",
 			Description: "Record audit/trust score (R_eff).",
@@ -237,10 +516,97 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 					"rationale":       map[string]string{"type": "string"},
 					"consequences":    map[string]string{"type": "string"},
 					"characteristics": map[string]string{"type": "string"},
+					"status":          map[string]interface{}{"type": "string", "enum": []string{"proposed", "accepted"}, "description": "DRR status (default: accepted)"},
+					"supersedes": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "IDs of earlier DRRs this decision replaces; they are marked superseded",
+					},
 				},
 				"required": []string{"title", "winner_id", "context", "decision", "rationale", "consequences"},
 			},
 		},
+		{
+			Name:        "quint_drr_status",
+			Description: "Move a DRR along its lifecycle: accept a proposed DRR, or deprecate one that no longer holds. To supersede a DRR, call quint_decide with supersedes.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"drr_id": map[string]string{"type": "string"},
+					"status": map[string]interface{}{"type": "string", "enum": []string{"accepted", "deprecated"}},
+					"reason": map[string]string{"type": "string", "description": "Why the status changed"},
+				},
+				"required": []string{"drr_id", "status"},
+			},
+		},
+		{
+			Name:        "quint_characterize",
+			Description: "Record a measurable characteristic of a hypothesis (C.16), e.g. latency or cost. Recording the same name again replaces the value.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"hypothesis_id": map[string]string{"type": "string"},
+					"name":          map[string]string{"type": "string", "description": "Characteristic name, e.g. p99_latency"},
+					"scale":         map[string]interface{}{"type": "string", "enum": []string{"ordinal", "interval", "ratio"}, "description": "Measurement scale; ordinal values are ranks"},
+					"value":         map[string]string{"type": "string", "description": "Numeric value"},
+					"unit":          map[string]string{"type": "string", "description": "Unit, e.g. ms"},
+					"direction":     map[string]interface{}{"type": "string", "enum": []string{"higher", "lower"}, "description": "Whether higher or lower values are better (default: higher)"},
+				},
+				"required": []string{"hypothesis_id", "name", "scale", "value"},
+			},
+		},
+		{
+			Name:        "quint_simulate",
+			Description: "What-if: show how R_eff of a holon and everything relying on it would change under hypothetical evidence, CLs, expiries or dropped dependencies. Nothing is saved.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"holon_id": map[string]string{"type": "string"},
+					"add_evidence": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Hypothetical evidence as holon:verdict[:valid_until], e.g. redis-cache:pass",
+					},
+					"set_cl": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Changed congruence levels as holon:dependency:cl, e.g. api:redis-cache:3",
+					},
+					"expire": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Evidence to expire as evidence_id[:date] (default: now)",
+					},
+					"remove_dependency": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Dependencies to drop as holon:dependency",
+					},
+					"at": map[string]string{"type": "string", "description": "Evaluate both sides as of this date, no earlier than any expiry (default: the latest expiry, else now)"},
+				},
+				"required": []string{"holon_id"},
+			},
+		},
+		{
+			Name:        "quint_compare",
+			Description: "Compare the options of a decision on their characteristics: dominance, Pareto front and weighted scores.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"decision_context": map[string]string{"type": "string", "description": "Decision context whose members are compared"},
+					"hypothesis_ids": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Hypotheses to compare, in addition to the decision context's members",
+					},
+					"weights": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Weights as name=weight, e.g. latency=2; unlisted characteristics weigh 1",
+					},
+				},
+			},
+		},
 		{
 			Name:        "quint_actualize",
 			Description: "Reconcile the project's FPF state with recent repository changes.",
@@ -251,7 +617,7 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 		},
 		{
 			Name:        "quint_audit_tree",
-			Description: "Visualize the assurance tree for a holon, showing R scores, dependencies, and CL penalties.",
+			Description: "Visualize the assurance tree for a holon, showing the ⟨F,G,R⟩ tuple, dependencies, and CL penalties.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -262,7 +628,7 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 		},
 		{
 			Name:        "quint_calculate_r",
-			Description: "Calculate the effective reliability (R_eff) for a holon with detailed breakdown.",
+			Description: "Calculate the effective assurance tuple ⟨F,G,R⟩ for a holon: formality, claim scope and reliability (R_eff) with detailed breakdown, the critical path down to the bounding evidence, and the next validation steps ranked by how much they would raise R_eff. Returns markdown plus the report as structured content.",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -271,6 +637,54 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 				"required": []string{"holon_id"},
 			},
 		},
+		{
+			Name:        "quint_query",
+			Description: "Full-text search over hypotheses, evidence and DRRs. Returns ranked matches with snippets and live R_eff.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query":            map[string]string{"type": "string", "description": "Search terms (all must match, prefix matching)"},
+					"layer":            map[string]interface{}{"type": "string", "enum": []interface{}{"L0", "L1", "L2", "invalid", "DRR"}, "description": "Only holons in this layer"},
+					"kind":             map[string]interface{}{"type": "string", "enum": []interface{}{"system", "episteme"}, "description": "Only holons of this kind"},
+					"scope":            map[string]string{"type": "string", "description": "Only holons whose scope contains this text"},
+					"decision_context": map[string]string{"type": "string", "description": "Only members of this decision context"},
+					"min_r":            map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1, "description": "Minimum R_eff"},
+					"max_r":            map[string]interface{}{"type": "number", "minimum": 0, "maximum": 1, "description": "Maximum R_eff"},
+					"limit":            map[string]interface{}{"type": "integer", "minimum": 1, "default": 10},
+				},
+				"required": []string{"query"},
+			},
+		},
+		{
+			Name:        "quint_history",
+			Description: "Show the persisted phase transition history of a bounded context: who moved it through ABDUCTION → DEDUCTION → INDUCTION → DECISION, with which evidence, including loopbacks.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"context": map[string]string{"type": "string", "description": "Bounded context (default: active context)"},
+					"limit":   map[string]string{"type": "number", "description": "Only show the most recent N transitions"},
+				},
+			},
+		},
+		{
+			Name:        "quint_audit_log",
+			Description: "Query the audit log: every tool call with its actor, target and result. Filter by tool, operation, actor, target, result and time range; render as a table, JSONL or CSV.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"tool":      map[string]string{"type": "string", "description": "Tool name, e.g. quint_verify"},
+					"operation": map[string]string{"type": "string", "description": "Operation, e.g. verify_hypothesis"},
+					"actor":     map[string]string{"type": "string", "description": "Actor (Deductor@session-1), or a role to match all its sessions"},
+					"target_id": map[string]string{"type": "string", "description": "Holon the call acted on, e.g. a hypothesis ID"},
+					"result":    map[string]string{"type": "string", "description": "SUCCESS or ERROR"},
+					"context":   map[string]string{"type": "string", "description": "Bounded context (default: all contexts)"},
+					"since":     map[string]string{"type": "string", "description": "Start: date (2006-01-02), RFC 3339 time or duration back from now (24h, 7d)"},
+					"until":     map[string]string{"type": "string", "description": "End (exclusive; a date includes that day), same forms as since"},
+					"limit":     map[string]string{"type": "number", "description": "Only show the most recent N matches"},
+					"format":    map[string]interface{}{"type": "string", "enum": []string{"table", "jsonl", "csv"}, "description": "Output format (default: table)"},
+				},
+			},
+		},
 		{
 			Name:        "quint_check_decay",
 			Description: "Check evidence freshness and manage stale decisions. Without parameters: shows freshness report. With deprecate: downgrades hypothesis. With waive: records temporary risk acceptance.",
@@ -298,51 +712,146 @@ func (s *Server) handleToolsList(req JSONRPCRequest) {
 		},
 	}
 
-	s.sendResult(req.ID, map[string]interface{}{
+	for _, tool := range tools {
+		if !roleAwareTools[tool.Name] {
+			continue
+		}
+		schema, _ := tool.InputSchema.(map[string]interface{})
+		props, ok := schema["properties"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name, prop := range roleProperties() {
+			props[name] = prop
+		}
+	}
+
+	return map[string]interface{}{
 		"tools": tools,
-	})
+	}
+}
+
+// roleProperties are the arguments a role-aware tool accepts for strict mode.
+func roleProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"role": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{string(RoleAbductor), string(RoleDeductor), string(RoleInductor), string(RoleAuditor), string(RoleDecider)},
+			"description": "FPF role this call acts in. Required in strict mode; recorded as the audit actor.",
+		},
+		"session_id": map[string]string{
+			"type":        "string",
+			"description": "Session holding the role. Required in strict mode.",
+		},
+		"evidence": map[string]interface{}{
+			"type":        "object",
+			"description": "Evidence anchor (A.10) for a phase transition in strict mode. uri may be relative to the project root: knowledge/L0 directory for DEDUCTION, an L1 file for INDUCTION, an L2 file for AUDIT and DECISION.",
+			"properties": map[string]interface{}{
+				"type":        map[string]string{"type": "string"},
+				"uri":         map[string]string{"type": "string"},
+				"description": map[string]string{"type": "string"},
+				"holon_id":    map[string]string{"type": "string"},
+			},
+		},
+	}
 }
 
-func (s *Server) handleToolsCall(req JSONRPCRequest) {
+// evidenceStubArg decodes the optional evidence argument of a tool call.
+func evidenceStubArg(arguments map[string]interface{}) *EvidenceStub {
+	raw, ok := arguments["evidence"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	field := func(k string) string {
+		v, _ := raw[k].(string)
+		return v
+	}
+	return &EvidenceStub{
+		Type:        field("type"),
+		URI:         field("uri"),
+		Description: field("description"),
+		HolonID:     field("holon_id"),
+	}
+}
+
+func (s *Server) handleToolsCall(req JSONRPCRequest) (interface{}, *RPCError) {
 	var params struct {
 		Name      string                 `json:"name"`
 		Arguments map[string]interface{} `json:"arguments"`
 	}
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		s.sendError(req.ID, -32700, "Invalid params")
-		return
+		return nil, &RPCError{Code: -32700, Message: "Invalid params"}
 	}
 
+	return s.callTool(params.Name, params.Arguments), nil
+}
+
+// CallTool runs a tool outside of a JSON-RPC request, e.g. from the CLI. It
+// goes through the same preconditions, strict mode checks and history
+// recording as tools/call.
+func (s *Server) CallTool(name string, arguments map[string]interface{}) CallToolResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.callTool(name, arguments)
+}
+
+func (s *Server) callTool(name string, arguments map[string]interface{}) CallToolResult {
 	arg := func(k string) string {
-		if v, ok := params.Arguments[k].(string); ok {
+		if v, ok := arguments[k].(string); ok {
 			return v
 		}
 		return ""
 	}
 
 	args := make(map[string]string)
-	for k, v := range params.Arguments {
+	for k, v := range arguments {
 		if s, ok := v.(string); ok {
 			args[k] = s
 		}
 	}
 
-	if precondErr := s.tools.CheckPreconditions(params.Name, args); precondErr != nil {
-		s.tools.AuditLog(params.Name, "precondition_failed", "agent", "", "BLOCKED", args, precondErr.Error())
-		s.sendResult(req.ID, CallToolResult{
-			Content: []ContentItem{{Type: "text", Text: precondErr.Error()}},
-			IsError: true,
-		})
-		return
+	s.tools.Caller = RoleAssignment{
+		Role:      Role(arg("role")),
+		SessionID: arg("session_id"),
+		Context:   s.tools.ContextID(),
+	}
+	defer func() { s.tools.Caller = RoleAssignment{} }()
+
+	if precondErr := s.tools.CheckPreconditions(name, args); precondErr != nil {
+		s.tools.AuditLog(name, "precondition_failed", s.tools.actor(), "", "BLOCKED", args, precondErr.Error())
+		return CallToolResult{
+			Content:           []ContentItem{{Type: "text", Text: precondErr.Error()}},
+			StructuredContent: precondErr,
+			IsError:           true,
+		}
+	}
+
+	evidence := evidenceStubArg(arguments)
+	if authErr := s.tools.AuthorizeToolCall(name, args, s.tools.Caller, evidence); authErr != nil {
+		s.tools.AuditLog(name, "transition_denied", s.tools.actor(), "", "BLOCKED", args, authErr.Error())
+		return CallToolResult{
+			Content:           []ContentItem{{Type: "text", Text: authErr.Error()}},
+			StructuredContent: authErr,
+			IsError:           true,
+		}
+	}
+
+	mutating := IsMutatingTool(name, args)
+	contextBefore := s.tools.ContextID()
+	target := s.tools.toolContextID(name, args)
+	var phaseBefore, derivedBefore Phase
+	if mutating {
+		phaseBefore = s.tools.startPhase(target, name)
+		derivedBefore = s.tools.FSM.DerivePhase(target)
 	}
 
 	var output string
+	var structured interface{}
 	var err error
 
-	switch params.Name {
+	switch name {
 	case "quint_status":
-		st := s.tools.FSM.State.Phase
-		output = string(st)
+		output = string(s.tools.currentPhase())
 
 	case "quint_init":
 		res := s.tools.InitProject()
@@ -350,7 +859,7 @@ func (s *Server) handleToolsCall(req JSONRPCRequest) {
 			err = res
 		} else {
 			s.tools.FSM.State.Phase = PhaseAbduction
-			if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
+			if saveErr := s.tools.FSM.SaveState(s.tools.ContextID()); saveErr != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
 			}
 			output = "Initialized. Phase: ABDUCTION"
@@ -362,14 +871,25 @@ func (s *Server) handleToolsCall(req JSONRPCRequest) {
 	case "quint_record_context":
 		output, err = s.tools.RecordContext(arg("vocabulary"), arg("invariants"))
 
+	case "quint_context":
+		output, err = s.tools.ManageContext(arg("action"), arg("context_id"), arg("description"))
+
 	case "quint_propose":
-		s.tools.FSM.State.Phase = PhaseAbduction
-		if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
+		var contextID string
+		var fsm *FSM
+		if contextID, err = s.tools.ensureContext(arg("context")); err != nil {
+			break
+		}
+		if fsm, err = s.tools.contextFSM(contextID); err != nil {
+			break
+		}
+		fsm.State.Phase = PhaseAbduction
+		if saveErr := fsm.SaveState(contextID); saveErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
 		}
 		decisionContext := arg("decision_context")
 		var dependsOn []string
-		if deps, ok := params.Arguments["depends_on"].([]interface{}); ok {
+		if deps, ok := arguments["depends_on"].([]interface{}); ok {
 			for _, d := range deps {
 				if s, ok := d.(string); ok {
 					dependsOn = append(dependsOn, s)
@@ -377,21 +897,25 @@ func (s *Server) handleToolsCall(req JSONRPCRequest) {
 			}
 		}
 		dependencyCL := 3
-		if cl, ok := params.Arguments["dependency_cl"].(float64); ok {
+		if cl, ok := arguments["dependency_cl"].(float64); ok {
 			dependencyCL = int(cl)
 		}
-		output, err = s.tools.ProposeHypothesis(arg("title"), arg("content"), arg("scope"), arg("kind"), arg("rationale"), decisionContext, dependsOn, dependencyCL)
+		formality := 0
+		if f, ok := arguments["formality"].(float64); ok {
+			formality = int(f)
+		}
+		output, err = s.tools.ProposeHypothesis(arg("title"), arg("content"), arg("scope"), arg("kind"), arg("rationale"), decisionContext, dependsOn, dependencyCL, formality, arg("claim_scope"), contextID)
 
 	case "quint_verify":
 		s.tools.FSM.State.Phase = PhaseDeduction
-		if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
+		if saveErr := s.tools.FSM.SaveState(s.tools.ContextID()); saveErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
 		}
 		output, err = s.tools.VerifyHypothesis(arg("hypothesis_id"), arg("checks_json"), arg("verdict"))
 
 	case "quint_test":
 		s.tools.FSM.State.Phase = PhaseInduction
-		if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
+		if saveErr := s.tools.FSM.SaveState(s.tools.ContextID()); saveErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
 		}
 
@@ -402,48 +926,158 @@ func (s *Server) handleToolsCall(req JSONRPCRequest) {
 
 		output, err = s.tools.ManageEvidence(PhaseInduction, "add", arg("hypothesis_id"), arg("test_type"), arg("result"), arg("verdict"), assLevel, "test-runner", "")
 
+	case "quint_ingest_results":
+		mapping := arg("mapping")
+		if m, ok := arguments["mapping"].(map[string]interface{}); ok {
+			data, _ := json.Marshal(m)
+			mapping = string(data)
+		}
+		output, err = s.tools.IngestResults(arg("path"), arg("format"), arg("hypothesis_id"), mapping)
+
 	case "quint_audit":
 		output, err = s.tools.AuditEvidence(arg("hypothesis_id"), arg("risks"))
 
 	case "quint_decide":
 		s.tools.FSM.State.Phase = PhaseDecision
-		var rejectedIDs []string
-		if rids, ok := params.Arguments["rejected_ids"].([]interface{}); ok {
+		var rejectedIDs, supersedes []string
+		if rids, ok := arguments["rejected_ids"].([]interface{}); ok {
 			for _, r := range rids {
 				if s, ok := r.(string); ok {
 					rejectedIDs = append(rejectedIDs, s)
 				}
 			}
 		}
-		output, err = s.tools.FinalizeDecision(arg("title"), arg("winner_id"), rejectedIDs, arg("context"), arg("decision"), arg("rationale"), arg("consequences"), arg("characteristics"))
+		if sids, ok := arguments["supersedes"].([]interface{}); ok {
+			for _, r := range sids {
+				if s, ok := r.(string); ok {
+					supersedes = append(supersedes, s)
+				}
+			}
+		}
+		output, err = s.tools.FinalizeDecision(arg("title"), arg("winner_id"), rejectedIDs, arg("context"), arg("decision"), arg("rationale"), arg("consequences"), arg("characteristics"), arg("status"), supersedes)
 		if err == nil {
 			s.tools.FSM.State.Phase = PhaseIdle
-			if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
+			if saveErr := s.tools.FSM.SaveState(s.tools.ContextID()); saveErr != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
 			}
 		}
 
+	case "quint_drr_status":
+		output, err = s.tools.SetDRRStatus(arg("drr_id"), arg("status"), arg("reason"))
+
+	case "quint_characterize":
+		output, err = s.tools.Characterize(arg("hypothesis_id"), arg("name"), arg("scale"), arg("value"), arg("unit"), arg("direction"))
+
+	case "quint_compare":
+		var hypothesisIDs, weights []string
+		if ids, ok := arguments["hypothesis_ids"].([]interface{}); ok {
+			for _, id := range ids {
+				if s, ok := id.(string); ok {
+					hypothesisIDs = append(hypothesisIDs, s)
+				}
+			}
+		}
+		if ws, ok := arguments["weights"].([]interface{}); ok {
+			for _, w := range ws {
+				if s, ok := w.(string); ok {
+					weights = append(weights, s)
+				}
+			}
+		}
+		output, err = s.tools.Compare(arg("decision_context"), hypothesisIDs, weights)
+
+	case "quint_simulate":
+		list := func(key string) []string {
+			var items []string
+			if vs, ok := arguments[key].([]interface{}); ok {
+				for _, v := range vs {
+					if s, ok := v.(string); ok {
+						items = append(items, s)
+					}
+				}
+			}
+			return items
+		}
+		output, err = s.tools.Simulate(WhatIf{
+			HolonID:          arg("holon_id"),
+			AddEvidence:      list("add_evidence"),
+			SetCL:            list("set_cl"),
+			Expire:           list("expire"),
+			RemoveDependency: list("remove_dependency"),
+			At:               arg("at"),
+		})
+
 	case "quint_audit_tree":
 		output, err = s.tools.VisualizeAudit(arg("holon_id"))
 
 	case "quint_calculate_r":
-		output, err = s.tools.CalculateR(arg("holon_id"))
+		var report *assurance.AssuranceReport
+		if output, report, err = s.tools.ExplainR(arg("holon_id")); err == nil {
+			structured = report
+		}
+
+	case "quint_query":
+		filters := QueryFilters{
+			Layer:           arg("layer"),
+			Kind:            arg("kind"),
+			Scope:           arg("scope"),
+			DecisionContext: arg("decision_context"),
+		}
+		if v, ok := arguments["min_r"].(float64); ok {
+			filters.MinR = v
+		}
+		if v, ok := arguments["max_r"].(float64); ok {
+			filters.MaxR = &v
+		}
+		if v, ok := arguments["limit"].(float64); ok {
+			filters.Limit = int(v)
+		}
+		output, err = s.tools.Query(arg("query"), filters)
 
 	case "quint_check_decay":
 		output, err = s.tools.CheckDecay(arg("deprecate"), arg("waive_id"), arg("waive_until"), arg("waive_rationale"))
 
+	case "quint_history":
+		limit := 0
+		if v, ok := arguments["limit"].(float64); ok {
+			limit = int(v)
+		}
+		output, err = s.tools.History(arg("context"), limit)
+
+	case "quint_audit_log":
+		limit := 0
+		if v, ok := arguments["limit"].(float64); ok {
+			limit = int(v)
+		}
+		output, err = s.tools.QueryAuditLog(AuditLogQuery{
+			Tool:      arg("tool"),
+			Operation: arg("operation"),
+			Actor:     arg("actor"),
+			Target:    arg("target_id"),
+			Result:    arg("result"),
+			Context:   arg("context"),
+			Since:     arg("since"),
+			Until:     arg("until"),
+			Limit:     limit,
+			Format:    arg("format"),
+		})
+
 	default:
-		err = fmt.Errorf("unknown tool: %s", params.Name)
+		err = fmt.Errorf("unknown tool: %s", name)
+	}
+
+	if err == nil && mutating && s.tools.ContextID() == contextBefore {
+		s.tools.recordToolTransition(target, name, args, phaseBefore, derivedBefore, evidence)
 	}
 
 	if err != nil {
-		s.sendResult(req.ID, CallToolResult{
+		return CallToolResult{
 			Content: []ContentItem{{Type: "text", Text: err.Error()}},
 			IsError: true,
-		})
-	} else {
-		s.sendResult(req.ID, CallToolResult{
-			Content: []ContentItem{{Type: "text", Text: output}},
-		})
+		}
+	}
+	return CallToolResult{
+		Content:           []ContentItem{{Type: "text", Text: output}},
+		StructuredContent: structured,
 	}
 }
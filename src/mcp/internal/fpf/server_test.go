@@ -0,0 +1,96 @@
+package fpf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+)
+
+func TestServer_CallTool(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	result := server.CallTool("quint_verify", map[string]interface{}{"hypothesis_id": "missing", "verdict": "PASS"})
+	if !result.IsError {
+		t.Fatal("Expected precondition failure for a missing hypothesis")
+	}
+	if _, ok := result.StructuredContent.(*PreconditionError); !ok {
+		t.Errorf("Expected structured PreconditionError, got %T", result.StructuredContent)
+	}
+
+	result = server.CallTool("quint_propose", map[string]interface{}{
+		"title":      "CLI Hypothesis",
+		"content":    "Proposed without an MCP client",
+		"kind":       "system",
+		"scope":      "global",
+		"rationale":  "{}",
+		"depends_on": []interface{}{},
+		"formality":  float64(2),
+	})
+	if result.IsError {
+		t.Fatalf("CallTool failed: %s", result.Content[0].Text)
+	}
+	if !strings.HasSuffix(result.Content[0].Text, "cli-hypothesis.md") {
+		t.Errorf("Unexpected output: %s", result.Content[0].Text)
+	}
+
+	holon, err := tools.DB.GetHolon(context.Background(), "cli-hypothesis")
+	if err != nil {
+		t.Fatalf("GetHolon failed: %v", err)
+	}
+	if holon.Formality.Int64 != 2 {
+		t.Errorf("Expected formality F2, got F%d", holon.Formality.Int64)
+	}
+
+	if phase := server.CallTool("quint_status", nil).Content[0].Text; phase != string(PhaseAbduction) {
+		t.Errorf("Expected ABDUCTION after propose, got %s", phase)
+	}
+}
+
+func TestServer_CalculateRSensitivity(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+	ctx := context.Background()
+
+	if _, err := tools.ProposeHypothesis("Use Redis", "Redis cache", "backend", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis("Cache Layer", "Cache layer on Redis", "backend", "system", "{}", "", []string{"use-redis"}, 2, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	for _, id := range []string{"use-redis", "cache-layer"} {
+		if _, err := tools.VerifyHypothesis(id, "{}", "PASS"); err != nil {
+			t.Fatalf("VerifyHypothesis failed: %v", err)
+		}
+	}
+	if err := tools.DB.AddEvidence(ctx, "redis-bench", "use-redis", "benchmark", "p99 over budget", "degrade", "L2", "", ""); err != nil {
+		t.Fatalf("AddEvidence failed: %v", err)
+	}
+
+	result := server.CallTool("quint_calculate_r", map[string]interface{}{"holon_id": "cache-layer"})
+	if result.IsError {
+		t.Fatalf("CallTool failed: %s", result.Content[0].Text)
+	}
+	out := result.Content[0].Text
+	for _, want := range []string{
+		"**R_eff: 0.65**",
+		"**Critical path:** cache-layer → use-redis",
+		"- Bounded by evidence: redis-bench",
+		"| use-redis | CL2 | 0.75 | 0.10 | 0.65 |",
+		"| 1 | Refresh degrade evidence redis-bench of use-redis | 0.90 | +0.25 |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	report, ok := result.StructuredContent.(*assurance.AssuranceReport)
+	if !ok {
+		t.Fatalf("Expected a structured AssuranceReport, got %T", result.StructuredContent)
+	}
+	if report.WeakestLink != "use-redis" || len(report.Actions) != 1 || report.Actions[0].EvidenceIDs[0] != "redis-bench" {
+		t.Errorf("Unexpected structured report: %+v", report)
+	}
+}
@@ -0,0 +1,226 @@
+package fpf
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+)
+
+// WhatIf describes the hypothetical changes of one simulation. Each change
+// is a colon-separated string, so it reads the same over MCP and the CLI.
+type WhatIf struct {
+	HolonID          string
+	AddEvidence      []string // holon:verdict[:valid_until]
+	SetCL            []string // holon:dependency:cl
+	Expire           []string // evidence_id[:date], expired from that date (default: now)
+	RemoveDependency []string // holon:dependency
+	At               string   // evaluate as of this date (default: the latest expiry, else now)
+}
+
+// Simulate reports R_eff as is and under the what-if for a holon and every
+// holon that relies on it, both evaluated at the same time. Nothing is saved.
+func (t *Tools) Simulate(w WhatIf) (string, error) {
+	defer t.RecordWork("Simulate", time.Now())
+	if t.DB == nil {
+		return "", fmt.Errorf("DB not initialized")
+	}
+	ctx := context.Background()
+	if _, err := t.DB.GetHolon(ctx, w.HolonID); errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("holon not found: %s", w.HolonID)
+	} else if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	overlay := assurance.NewOverlay(t.DB)
+	changes, at, err := t.applyWhatIf(ctx, overlay, w, now)
+	if err != nil {
+		return "", err
+	}
+	if len(changes) == 0 {
+		return "", fmt.Errorf("no changes to simulate: give add_evidence, set_cl, expire or remove_dependency")
+	}
+
+	holons, drrs, err := t.ImpactOf([]string{w.HolonID})
+	if err != nil {
+		return "", err
+	}
+	ids := []string{w.HolonID}
+	for _, h := range holons {
+		if h.ID != w.HolonID {
+			ids = append(ids, h.ID)
+		}
+	}
+
+	// Both sides run over an overlay, so neither caches a score, and at the
+	// same instant, so the difference is the what-if alone.
+	before := t.FSM.NewCalculator(assurance.NewOverlay(t.DB))
+	after := t.FSM.NewCalculator(overlay)
+	before.Now = func() time.Time { return at }
+	after.Now = before.Now
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("## What-if: %s\n\n", w.HolonID))
+	for _, c := range changes {
+		out.WriteString(fmt.Sprintf("- %s\n", c))
+	}
+	if !at.Equal(now) {
+		out.WriteString(fmt.Sprintf("\nEvaluated as of %s.\n", at.UTC().Format("2006-01-02 15:04 MST")))
+	}
+	out.WriteString("\n| Holon | R_eff as is | R_eff what-if | Δ | Weakest link |\n")
+	out.WriteString("|-------|-------------|---------------|---|--------------|\n")
+	for _, id := range ids {
+		b, err := before.CalculateReliability(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		a, err := after.CalculateReliability(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		name := id
+		if id == w.HolonID {
+			name += " (target)"
+		}
+		out.WriteString(fmt.Sprintf("| %s | %.2f | %.2f | %+.2f | %s |\n", name, b.FinalScore, a.FinalScore, a.FinalScore-b.FinalScore, orDash(a.WeakestLink)))
+	}
+	if len(drrs) > 0 {
+		var names []string
+		for _, d := range drrs {
+			names = append(names, d.ID)
+		}
+		out.WriteString(fmt.Sprintf("\n**Decisions relying on these:** %s\n", strings.Join(names, ", ")))
+	}
+	out.WriteString("\nNothing was saved.\n")
+	return out.String(), nil
+}
+
+// applyWhatIf validates each change, applies it to the overlay and describes
+// it. It returns the time to evaluate the what-if at.
+func (t *Tools) applyWhatIf(ctx context.Context, o *assurance.Overlay, w WhatIf, now time.Time) ([]string, time.Time, error) {
+	var changes []string
+	at := now
+	var lastExpired string
+
+	for _, spec := range w.AddEvidence {
+		parts := strings.SplitN(spec, ":", 3)
+		if len(parts) < 2 {
+			return nil, at, fmt.Errorf("invalid add_evidence %q: use holon:verdict[:valid_until]", spec)
+		}
+		holonID, verdict := parts[0], strings.ToLower(parts[1])
+		if verdict != "pass" && verdict != "degrade" && verdict != "fail" {
+			return nil, at, fmt.Errorf("invalid verdict %q in %q: use pass, degrade or fail", parts[1], spec)
+		}
+		if _, err := t.DB.GetHolon(ctx, holonID); err != nil {
+			return nil, at, fmt.Errorf("holon not found: %s", holonID)
+		}
+		var validUntil time.Time
+		if len(parts) == 3 {
+			var err error
+			if validUntil, err = parseWhatIfDate(parts[2]); err != nil {
+				return nil, at, err
+			}
+		}
+		id := o.AddEvidence(holonID, verdict, validUntil)
+		changes = append(changes, fmt.Sprintf("Add %s evidence to %s (%s)", verdict, holonID, id))
+	}
+
+	for _, spec := range w.SetCL {
+		parts := strings.Split(spec, ":")
+		if len(parts) != 3 {
+			return nil, at, fmt.Errorf("invalid set_cl %q: use holon:dependency:cl", spec)
+		}
+		cl, err := strconv.Atoi(parts[2])
+		if err != nil || cl < 0 || cl > 3 {
+			return nil, at, fmt.Errorf("invalid CL %q in %q: use 0 to 3", parts[2], spec)
+		}
+		if err := t.checkDependency(ctx, parts[0], parts[1]); err != nil {
+			return nil, at, err
+		}
+		o.SetCongruence(parts[0], parts[1], cl)
+		changes = append(changes, fmt.Sprintf("Set CL of %s → %s to CL%d", parts[0], parts[1], cl))
+	}
+
+	for _, spec := range w.Expire {
+		id, date, hasDate := strings.Cut(spec, ":")
+		if _, err := t.DB.GetEvidenceByID(ctx, id); err != nil {
+			return nil, at, fmt.Errorf("evidence not found: %s", id)
+		}
+		expiry := now
+		if hasDate {
+			var err error
+			if expiry, err = parseWhatIfDate(date); err != nil {
+				return nil, at, err
+			}
+		}
+		o.ExpireEvidence(id, expiry)
+		if expiry.After(at) {
+			at, lastExpired = expiry, id
+		}
+		changes = append(changes, fmt.Sprintf("Expire %s on %s", id, expiry.UTC().Format("2006-01-02")))
+	}
+
+	for _, spec := range w.RemoveDependency {
+		holonID, dep, ok := strings.Cut(spec, ":")
+		if !ok {
+			return nil, at, fmt.Errorf("invalid remove_dependency %q: use holon:dependency", spec)
+		}
+		if err := t.checkDependency(ctx, holonID, dep); err != nil {
+			return nil, at, err
+		}
+		o.RemoveDependency(holonID, dep)
+		changes = append(changes, fmt.Sprintf("Remove dependency %s → %s", holonID, dep))
+	}
+
+	if w.At != "" {
+		explicit, err := parseWhatIfDate(w.At)
+		if err != nil {
+			return nil, at, err
+		}
+		if explicit.Before(at) {
+			return nil, at, fmt.Errorf("at %s is before %s expires on %s: the expiry would not show", w.At, lastExpired, at.UTC().Format("2006-01-02"))
+		}
+		at = explicit
+	}
+	return changes, at, nil
+}
+
+// checkDependency fails unless holonID relies on dependencyID through
+// componentOf or dependsOn.
+func (t *Tools) checkDependency(ctx context.Context, holonID, dependencyID string) error {
+	parts, err := t.DB.GetComponentsOf(ctx, holonID)
+	if err != nil {
+		return err
+	}
+	for _, p := range parts {
+		if p.SourceID == dependencyID {
+			return nil
+		}
+	}
+	deps, err := t.DB.GetRelationsBySource(ctx, holonID, "dependsOn")
+	if err != nil {
+		return err
+	}
+	for _, d := range deps {
+		if d.TargetID == dependencyID {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s does not depend on %s", holonID, dependencyID)
+}
+
+func parseWhatIfDate(s string) (time.Time, error) {
+	if d, err := time.Parse("2006-01-02", s); err == nil {
+		return d, nil
+	}
+	if d, err := time.Parse(time.RFC3339, s); err == nil {
+		return d, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD or RFC3339)", s)
+}
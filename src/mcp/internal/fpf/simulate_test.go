@@ -0,0 +1,96 @@
+package fpf
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimulate(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	if _, err := tools.ProposeHypothesis("Use Redis", "Redis cache", "backend", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis("Cache Layer", "Cache layer on Redis", "backend", "system", "{}", "", []string{"use-redis"}, 2, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	for _, id := range []string{"use-redis", "cache-layer"} {
+		if _, err := tools.VerifyHypothesis(id, "{}", "PASS"); err != nil {
+			t.Fatalf("VerifyHypothesis failed: %v", err)
+		}
+	}
+	evidence, _ := tools.DB.GetEvidence(ctx, "use-redis")
+	if len(evidence) != 1 {
+		t.Fatalf("Expected one evidence record, got %d", len(evidence))
+	}
+
+	out, err := tools.Simulate(WhatIf{HolonID: "use-redis", AddEvidence: []string{"use-redis:fail"}, SetCL: []string{"cache-layer:use-redis:3"}})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	for _, want := range []string{
+		"- Add fail evidence to use-redis (what-if-1)",
+		"- Set CL of cache-layer → use-redis to CL3",
+		"| use-redis (target) | 1.00 | 0.50 | -0.50 | - |",
+		"| cache-layer | 0.90 | 0.50 | -0.40 | use-redis |",
+		"Nothing was saved.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the simulation to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	// Both sides are evaluated on the expiry date, while the rest of the
+	// evidence is still valid.
+	expiry := time.Now().AddDate(0, 0, 30).Format("2006-01-02")
+	out, err = tools.Simulate(WhatIf{HolonID: "cache-layer", Expire: []string{evidence[0].ID + ":" + expiry}})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if !strings.Contains(out, "Evaluated as of "+expiry) || !strings.Contains(out, "| cache-layer (target) | 0.90 | 0.00 |") {
+		t.Errorf("Expected the expiry to drag cache-layer down, got:\n%s", out)
+	}
+
+	// Far enough out, the evidence has expired either way.
+	out, err = tools.Simulate(WhatIf{HolonID: "cache-layer", Expire: []string{evidence[0].ID + ":2099-01-01"}})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if !strings.Contains(out, "| cache-layer (target) | 0.00 | 0.00 | +0.00 |") {
+		t.Errorf("Expected no difference once all evidence has expired, got:\n%s", out)
+	}
+
+	out, err = tools.Simulate(WhatIf{HolonID: "cache-layer", AddEvidence: []string{"use-redis:fail"}, RemoveDependency: []string{"cache-layer:use-redis"}})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if !strings.Contains(out, "| cache-layer (target) | 0.90 | 1.00 | +0.10 | - |") {
+		t.Errorf("Expected cache-layer to stand alone, got:\n%s", out)
+	}
+
+	// Nothing was saved.
+	if ev, _ := tools.DB.GetEvidence(ctx, "use-redis"); len(ev) != 1 {
+		t.Errorf("Expected no what-if evidence in the database, got %d records", len(ev))
+	}
+	if report, _ := tools.CalculateR("cache-layer"); !strings.Contains(report, "R_eff: 0.90") {
+		t.Errorf("Expected cache-layer unchanged, got:\n%s", report)
+	}
+
+	for _, bad := range []WhatIf{
+		{HolonID: "use-redis"},
+		{HolonID: "missing", AddEvidence: []string{"use-redis:pass"}},
+		{HolonID: "use-redis", AddEvidence: []string{"use-redis:maybe"}},
+		{HolonID: "use-redis", SetCL: []string{"use-redis:cache-layer:2"}},
+		{HolonID: "use-redis", SetCL: []string{"cache-layer:use-redis:5"}},
+		{HolonID: "use-redis", Expire: []string{"no-such-evidence"}},
+		{HolonID: "use-redis", Expire: []string{evidence[0].ID + ":soon"}},
+		{HolonID: "use-redis", Expire: []string{evidence[0].ID + ":2099-01-01"}, At: "2098-01-01"},
+	} {
+		if _, err := tools.Simulate(bad); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+}
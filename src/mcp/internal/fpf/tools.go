@@ -3,13 +3,18 @@ package fpf
 import (
 	"context"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,16 +29,38 @@ var slugifyRegex = regexp.MustCompile("[^a-zA-Z0-9]+")
 type Tools struct {
 	FSM     *FSM
 	RootDir string
-	DB      *db.Store
+	DB      db.Repository
+
+	// OnHolonChanged, if set, is called after a holon's layer or evidence changes.
+	OnHolonChanged func(holonID string)
+
+	// Caller is the role and session declared by the current tool call.
+	Caller RoleAssignment
+
+	// uow is set on the copy of Tools that runs inside inUnit.
+	uow *unitOfWork
 }
 
-func NewTools(fsm *FSM, rootDir string, database *db.Store) *Tools {
+// NewTools binds the tools to a project. A nil database opens the project's
+// quint.db; if that fails the tools run without a database. If the project
+// has an audit key, the database signs every audit entry with it.
+func NewTools(fsm *FSM, rootDir string, database db.Repository) *Tools {
 	if database == nil {
 		dbPath := filepath.Join(rootDir, ".quint", "quint.db")
-		var err error
-		database, err = db.NewStore(dbPath)
+		store, err := db.NewStore(dbPath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to open database in NewTools: %v\n", err)
+		} else {
+			database = store
+		}
+	}
+
+	if database != nil {
+		key, err := loadAuditKey(filepath.Join(rootDir, ".quint"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: audit entries will not be signed: %v\n", err)
+		} else if key != nil {
+			database.SetAuditKey(key)
 		}
 	}
 
@@ -58,66 +85,75 @@ func (t *Tools) AuditLog(toolName, operation, actor, targetID, result string, in
 		data, err := json.Marshal(input)
 		if err == nil {
 			hash := sha256.Sum256(data)
-			inputHash = hex.EncodeToString(hash[:8])
+			inputHash = hex.EncodeToString(hash[:])
 		}
 	}
 
 	id := uuid.New().String()
 	ctx := context.Background()
-	if err := t.DB.InsertAuditLog(ctx, id, toolName, operation, actor, targetID, inputHash, result, details, "default"); err != nil {
+	if err := t.DB.InsertAuditLog(ctx, id, toolName, operation, actor, targetID, inputHash, result, details, t.ContextID()); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to insert audit log: %v\n", err)
 	}
 }
 
+func (t *Tools) notifyHolonChanged(holonID string) {
+	if t.uow != nil {
+		t.uow.holonChanged(holonID)
+		return
+	}
+	if t.OnHolonChanged != nil {
+		t.OnHolonChanged(holonID)
+	}
+}
+
 func (t *Tools) Slugify(title string) string {
 	slug := slugifyRegex.ReplaceAllString(strings.ToLower(title), "-")
 	return strings.Trim(slug, "-")
 }
 
 func (t *Tools) MoveHypothesis(hypothesisID, sourceLevel, destLevel string) (string, error) {
-	srcPath := filepath.Join(t.GetFPFDir(), "knowledge", sourceLevel, hypothesisID+".md")
-	destPath := filepath.Join(t.GetFPFDir(), "knowledge", destLevel, hypothesisID+".md")
+	dir := t.holonContextDir(hypothesisID)
+	srcPath := filepath.Join(dir, "knowledge", sourceLevel, hypothesisID+".md")
+	destPath := filepath.Join(dir, "knowledge", destLevel, hypothesisID+".md")
+
+	input := map[string]string{"from": sourceLevel, "to": destLevel}
 
 	if _, err := os.Stat(srcPath); os.IsNotExist(err) {
-		t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"from": sourceLevel, "to": destLevel}, "not found")
+		t.AuditLog("quint_move", "move_hypothesis", t.actor(), hypothesisID, "ERROR", input, "not found")
 		return "", fmt.Errorf("hypothesis %s not found in %s", hypothesisID, sourceLevel)
 	}
 
-	if err := os.Rename(srcPath, destPath); err != nil {
-		t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"from": sourceLevel, "to": destLevel}, err.Error())
-		return "", fmt.Errorf("failed to move hypothesis from %s to %s: %v", sourceLevel, destLevel, err)
-	}
-
-	if t.DB != nil {
-		if err := t.DB.UpdateHolonLayer(context.Background(), hypothesisID, destLevel); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to update holon layer in DB: %v\n", err)
+	err := t.inUnit(func(tx *Tools) error {
+		if err := tx.uow.rename(srcPath, destPath); err != nil {
+			return fmt.Errorf("failed to move hypothesis from %s to %s: %v", sourceLevel, destLevel, err)
+		}
+		if tx.DB != nil {
+			if err := tx.DB.UpdateHolonLayer(context.Background(), hypothesisID, destLevel); err != nil {
+				return fmt.Errorf("failed to update holon layer in DB: %v", err)
+			}
 		}
+		tx.AuditLog("quint_move", "move_hypothesis", tx.actor(), hypothesisID, "SUCCESS", input, "")
+		tx.notifyHolonChanged(hypothesisID)
+		return nil
+	})
+	if err != nil {
+		t.AuditLog("quint_move", "move_hypothesis", t.actor(), hypothesisID, "ERROR", input, err.Error())
+		return "", err
 	}
-
-	t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"from": sourceLevel, "to": destLevel}, "")
 	return destPath, nil
 }
 
 func (t *Tools) InitProject() error {
-	dirs := []string{
-		"evidence",
-		"decisions",
-		"sessions",
-		"knowledge/L0",
-		"knowledge/L1",
-		"knowledge/L2",
-		"knowledge/invalid",
-		"agents",
-	}
-
-	for _, d := range dirs {
-		path := filepath.Join(t.GetFPFDir(), d)
-		if err := os.MkdirAll(path, 0755); err != nil {
-			return err
-		}
-		if err := os.WriteFile(filepath.Join(path, ".gitkeep"), []byte(""), 0644); err != nil {
-			return fmt.Errorf("failed to write .gitkeep file: %v", err)
-		}
+	if err := createWorkspaceDirs(t.GetContextDir()); err != nil {
+		return err
+	}
+
+	agentsDir := filepath.Join(t.GetFPFDir(), "agents")
+	if err := os.MkdirAll(agentsDir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(agentsDir, ".gitkeep"), []byte(""), 0644); err != nil {
+		return fmt.Errorf("failed to write .gitkeep file: %v", err)
 	}
 
 	if t.DB == nil {
@@ -141,7 +177,7 @@ func (t *Tools) RecordContext(vocabulary, invariants string) (string, error) {
 	invFormatted := formatInvariants(invariants)
 
 	content := fmt.Sprintf("# Bounded Context\n\n## Vocabulary\n\n%s\n\n## Invariants\n\n%s\n", vocabFormatted, invFormatted)
-	path := filepath.Join(t.GetFPFDir(), "context.md")
+	path := filepath.Join(t.GetContextDir(), "context.md")
 
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		return "", err
@@ -246,73 +282,149 @@ func (t *Tools) RecordWork(methodName string, start time.Time) {
 	}
 }
 
-func (t *Tools) ProposeHypothesis(title, content, scope, kind, rationale string, decisionContext string, dependsOn []string, dependencyCL int) (string, error) {
+func (t *Tools) ProposeHypothesis(title, content, scope, kind, rationale string, decisionContext string, dependsOn []string, dependencyCL int, formality int, claimScope string, contextID string) (string, error) {
 	defer t.RecordWork("ProposeHypothesis", time.Now())
 
+	contextID, err := t.ensureContext(contextID)
+	if err != nil {
+		return "", err
+	}
+
+	if formality < assurance.MinFormality || formality > assurance.MaxFormality {
+		return "", fmt.Errorf("invalid formality F%d: expected F0-F9", formality)
+	}
+	g, err := assurance.ParseClaimScope(claimScope)
+	if err != nil {
+		return "", err
+	}
+
 	slug := t.Slugify(title)
 	filename := fmt.Sprintf("%s.md", slug)
-	path := filepath.Join(t.GetFPFDir(), "knowledge", "L0", filename)
+	path := filepath.Join(t.contextDir(contextID), "knowledge", "L0", filename)
+
+	if t.DB != nil {
+		if existing, err := t.DB.GetHolon(context.Background(), slug); err == nil && existing.ContextID != contextID {
+			return "", fmt.Errorf("holon id %q is already used in context %q", slug, existing.ContextID)
+		}
+	}
 
 	body := fmt.Sprintf("\n# Hypothesis: %s\n\n%s\n\n## Rationale\n%s", title, content, rationale)
 	fields := map[string]string{
-		"scope": scope,
-		"kind":  kind,
+		"scope":     scope,
+		"kind":      kind,
+		"formality": assurance.FormatFormality(formality),
 	}
-
-	if err := WriteWithHash(path, fields, body); err != nil {
-		t.AuditLog("quint_propose", "create_hypothesis", "agent", slug, "ERROR", map[string]string{"title": title, "kind": kind}, err.Error())
-		return "", err
+	if !g.IsUnbounded() {
+		fields["claim_scope"] = g.String()
+	}
+	if dependencyCL < 1 || dependencyCL > 3 {
+		dependencyCL = 3
+	}
+	// Relations are part of the projection so rebuild-db can restore them.
+	if decisionContext != "" {
+		fields["decision_context"] = decisionContext
+	}
+	var dependencyCLs []int
+	if len(dependsOn) > 0 {
+		if dependencyCLs, err = t.dependencyCLs(contextID, dependsOn, dependencyCL); err != nil {
+			return "", err
+		}
+		fields["depends_on"] = strings.Join(dependsOn, ", ")
+		fields["dependency_cl"] = formatDependencyCLs(dependencyCLs)
 	}
 
-	if t.DB != nil {
-		if err := t.DB.CreateHolon(context.Background(), slug, "hypothesis", kind, "L0", title, body, "default", scope, ""); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to create holon in DB: %v\n", err)
+	err = t.inUnit(func(tx *Tools) error {
+		if err := tx.uow.writeProjection(path, fields, body); err != nil {
+			return err
+		}
+		if tx.DB != nil {
+			if err := tx.linkHypothesis(slug, kind, title, body, contextID, scope, formality, g, decisionContext, dependsOn, dependencyCLs); err != nil {
+				return err
+			}
 		}
+		tx.AuditLog("quint_propose", "create_hypothesis", tx.actor(), slug, "SUCCESS", map[string]string{"title": title, "kind": kind, "scope": scope}, "")
+		return nil
+	})
+	if err != nil {
+		t.AuditLog("quint_propose", "create_hypothesis", t.actor(), slug, "ERROR", map[string]string{"title": title, "kind": kind}, err.Error())
+		return "", err
 	}
 
+	return path, nil
+}
+
+// linkHypothesis records a proposed hypothesis and its relations in the
+// database. References to holons that do not exist, and dependencies that
+// would close a cycle, are skipped with a warning as before; any other
+// failure aborts the proposal.
+func (t *Tools) linkHypothesis(slug, kind, title, body, contextID, scope string, formality int, g assurance.ClaimScope, decisionContext string, dependsOn []string, dependencyCLs []int) error {
 	ctx := context.Background()
 
-	if decisionContext != "" && t.DB != nil {
-		if _, err := t.DB.GetHolon(ctx, decisionContext); err != nil {
+	if err := t.DB.CreateHolon(ctx, slug, "hypothesis", kind, "L0", title, body, contextID, scope, ""); err != nil {
+		return fmt.Errorf("failed to create holon in DB: %v", err)
+	}
+	if err := t.DB.UpdateHolonClaim(ctx, slug, formality, g.JSON()); err != nil {
+		return fmt.Errorf("failed to record F-G for holon: %v", err)
+	}
+
+	if decisionContext != "" {
+		_, err := t.DB.GetHolon(ctx, decisionContext)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
 			fmt.Fprintf(os.Stderr, "Warning: decision_context '%s' not found, skipping MemberOf\n", decisionContext)
-		} else {
+		case err != nil:
+			return err
+		default:
 			if err := t.createRelation(ctx, slug, "memberOf", decisionContext, 3); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create MemberOf relation: %v\n", err)
+				return fmt.Errorf("failed to create MemberOf relation: %v", err)
 			}
 		}
 	}
 
-	if len(dependsOn) > 0 && t.DB != nil {
-		if dependencyCL < 1 || dependencyCL > 3 {
-			dependencyCL = 3
-		}
+	relationType := "componentOf"
+	if kind == "episteme" {
+		relationType = "constituentOf"
+	}
 
-		relationType := "componentOf"
-		if kind == "episteme" {
-			relationType = "constituentOf"
+	for i, depID := range dependsOn {
+		_, err := t.DB.GetHolon(ctx, depID)
+		if errors.Is(err, sql.ErrNoRows) {
+			fmt.Fprintf(os.Stderr, "Warning: dependency '%s' not found, skipping\n", depID)
+			continue
+		}
+		if err != nil {
+			return err
 		}
 
-		for _, depID := range dependsOn {
-			if _, err := t.DB.GetHolon(ctx, depID); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: dependency '%s' not found, skipping\n", depID)
-				continue
-			}
-
-			if cyclic, _ := t.wouldCreateCycle(ctx, depID, slug); cyclic {
-				fmt.Fprintf(os.Stderr, "Warning: dependency on '%s' would create cycle, skipping\n", depID)
-				continue
-			}
+		cyclic, err := t.wouldCreateCycle(ctx, depID, slug)
+		if err != nil {
+			return err
+		}
+		if cyclic {
+			fmt.Fprintf(os.Stderr, "Warning: dependency on '%s' would create cycle, skipping\n", depID)
+			continue
+		}
 
-			if err := t.createRelation(ctx, depID, relationType, slug, dependencyCL); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create %s relation to %s: %v\n",
-					relationType, depID, err)
-			}
+		if err := t.createRelation(ctx, depID, relationType, slug, dependencyCLs[i]); err != nil {
+			return fmt.Errorf("failed to create %s relation to %s: %v", relationType, depID, err)
 		}
 	}
+	return nil
+}
 
-	t.AuditLog("quint_propose", "create_hypothesis", "agent", slug, "SUCCESS", map[string]string{"title": title, "kind": kind, "scope": scope}, "")
-
-	return path, nil
+// formatDependencyCLs writes dependency_cl: one level when all dependencies
+// share it, otherwise one per dependency in depends_on order.
+func formatDependencyCLs(cls []int) string {
+	levels := make([]string, len(cls))
+	same := true
+	for i, cl := range cls {
+		levels[i] = strconv.Itoa(cl)
+		same = same && cl == cls[0]
+	}
+	if same && len(cls) > 0 {
+		return levels[0]
+	}
+	return strings.Join(levels, ", ")
 }
 
 func (t *Tools) createRelation(ctx context.Context, sourceID, relationType, targetID string, cl int) error {
@@ -324,7 +436,7 @@ func (t *Tools) createRelation(ctx context.Context, sourceID, relationType, targ
 		return err
 	}
 
-	t.AuditLog("quint_propose", "create_relation", "agent", sourceID, "SUCCESS",
+	t.AuditLog("quint_propose", "create_relation", t.actor(), sourceID, "SUCCESS",
 		map[string]string{"relation": relationType, "target": targetID, "cl": fmt.Sprintf("%d", cl)}, "")
 
 	return nil
@@ -377,29 +489,31 @@ func (t *Tools) VerifyHypothesis(hypothesisID, checksJSON, verdict string) (stri
 
 	switch strings.ToLower(verdict) {
 	case "pass":
-		_, err := t.MoveHypothesis(hypothesisID, "L0", "L1")
+		// Passing L1 deduction evidence is what promotes the hypothesis, so the
+		// move and the verification record land together or not at all.
+		evidenceContent := fmt.Sprintf("Verification Checks:\n%s", checksJSON)
+		err := t.inUnit(func(tx *Tools) error {
+			if _, err := tx.ManageEvidence(PhaseDeduction, "add", hypothesisID, "verification", evidenceContent, "pass", "L1", carrierRef, ""); err != nil {
+				return err
+			}
+			tx.AuditLog("quint_verify", "verify_hypothesis", tx.actor(), hypothesisID, "SUCCESS", map[string]string{"verdict": "PASS", "result": "L1"}, "")
+			return nil
+		})
 		if err != nil {
-			t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
+			t.AuditLog("quint_verify", "verify_hypothesis", t.actor(), hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
 			return "", err
 		}
-
-		evidenceContent := fmt.Sprintf("Verification Checks:\n%s", checksJSON)
-		if _, err := t.ManageEvidence(PhaseDeduction, "add", hypothesisID, "verification", evidenceContent, "pass", "L1", carrierRef, ""); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to record verification evidence for %s: %v\n", hypothesisID, err)
-		}
-
-		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "PASS", "result": "L1"}, "")
 		return fmt.Sprintf("Hypothesis %s (kind: %s) promoted to L1", hypothesisID, carrierRef), nil
 	case "fail":
 		_, err := t.MoveHypothesis(hypothesisID, "L0", "invalid")
 		if err != nil {
-			t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
+			t.AuditLog("quint_verify", "verify_hypothesis", t.actor(), hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
 			return "", err
 		}
-		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "FAIL", "result": "invalid"}, "")
+		t.AuditLog("quint_verify", "verify_hypothesis", t.actor(), hypothesisID, "SUCCESS", map[string]string{"verdict": "FAIL", "result": "invalid"}, "")
 		return fmt.Sprintf("Hypothesis %s moved to invalid", hypothesisID), nil
 	case "refine":
-		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "REFINE", "result": "L0"}, "")
+		t.AuditLog("quint_verify", "verify_hypothesis", t.actor(), hypothesisID, "SUCCESS", map[string]string{"verdict": "REFINE", "result": "L0"}, "")
 		return fmt.Sprintf("Hypothesis %s requires refinement (staying in L0)", hypothesisID), nil
 	default:
 		return "", fmt.Errorf("unknown verdict: %s", verdict)
@@ -459,57 +573,74 @@ func (t *Tools) ManageEvidence(currentPhase Phase, action, targetID, evidenceTyp
 		}
 	}
 
-	var moveErr error
-	if (normalizedVerdict == "pass") && shouldPromote {
-		switch currentPhase {
-		case PhaseDeduction:
-			_, moveErr = t.MoveHypothesis(targetID, "L0", "L1")
-		case PhaseInduction:
-			if _, err := os.Stat(filepath.Join(t.GetFPFDir(), "knowledge", "L0", targetID+".md")); err == nil {
-				return "", fmt.Errorf("hypothesis %s is still in L0: run /q2-verify to promote it to L1 before testing", targetID)
-			}
-			_, moveErr = t.MoveHypothesis(targetID, "L1", "L2")
-		}
-	} else if normalizedVerdict == "fail" || normalizedVerdict == "refine" {
-		switch currentPhase {
-		case PhaseDeduction:
-			_, moveErr = t.MoveHypothesis(targetID, "L0", "invalid")
-		case PhaseInduction:
-			_, moveErr = t.MoveHypothesis(targetID, "L1", "invalid")
+	if normalizedVerdict == "pass" && shouldPromote && currentPhase == PhaseInduction {
+		if _, err := os.Stat(filepath.Join(t.holonContextDir(targetID), "knowledge", "L0", targetID+".md")); err == nil {
+			return "", fmt.Errorf("hypothesis %s is still in L0: run /q2-verify to promote it to L1 before testing", targetID)
 		}
 	}
 
-	if moveErr != nil {
-		return "", fmt.Errorf("failed to move hypothesis: %v", moveErr)
-	}
-
 	date := time.Now().Format("2006-01-02")
-	filename := fmt.Sprintf("%s-%s-%s.md", date, evidenceType, targetID)
-	path := filepath.Join(t.GetFPFDir(), "evidence", filename)
+	filename, path := t.evidenceFile(date, evidenceType, targetID)
+
+	err := t.inUnit(func(tx *Tools) error {
+		var moveErr error
+		if (normalizedVerdict == "pass") && shouldPromote {
+			switch currentPhase {
+			case PhaseDeduction:
+				_, moveErr = tx.MoveHypothesis(targetID, "L0", "L1")
+			case PhaseInduction:
+				_, moveErr = tx.MoveHypothesis(targetID, "L1", "L2")
+			}
+		} else if normalizedVerdict == "fail" || normalizedVerdict == "refine" {
+			switch currentPhase {
+			case PhaseDeduction:
+				_, moveErr = tx.MoveHypothesis(targetID, "L0", "invalid")
+			case PhaseInduction:
+				_, moveErr = tx.MoveHypothesis(targetID, "L1", "invalid")
+			}
+		}
 
-	body := fmt.Sprintf("\n%s", content)
-	fields := map[string]string{
-		"id":              filename,
-		"type":            evidenceType,
-		"target":          targetID,
-		"verdict":         normalizedVerdict,
-		"assurance_level": assuranceLevel,
-		"carrier_ref":     carrierRef,
-		"valid_until":     validUntil,
-		"date":            date,
-	}
+		if moveErr != nil {
+			return fmt.Errorf("failed to move hypothesis: %v", moveErr)
+		}
 
-	if err := WriteWithHash(path, fields, body); err != nil {
-		return "", err
-	}
+		body := fmt.Sprintf("\n%s", content)
+		fields := map[string]string{
+			"id":              filename,
+			"type":            evidenceType,
+			"target":          targetID,
+			"verdict":         normalizedVerdict,
+			"assurance_level": assuranceLevel,
+			"carrier_ref":     carrierRef,
+			"valid_until":     validUntil,
+			"date":            date,
+		}
 
-	if t.DB != nil {
-		if err := t.DB.AddEvidence(ctx, filename, targetID, evidenceType, content, normalizedVerdict, assuranceLevel, carrierRef, validUntil); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to add evidence to DB: %v\n", err)
+		if err := tx.uow.writeProjection(path, fields, body); err != nil {
+			return err
 		}
-		if err := t.DB.Link(ctx, filename, targetID, "verifiedBy"); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to link evidence in DB: %v\n", err)
+
+		if tx.DB != nil {
+			if err := tx.DB.AddEvidence(ctx, filename, targetID, evidenceType, content, normalizedVerdict, assuranceLevel, carrierRef, validUntil); err != nil {
+				return fmt.Errorf("failed to add evidence to DB: %v", err)
+			}
+			if err := tx.DB.Link(ctx, filename, targetID, "verifiedBy"); err != nil {
+				return fmt.Errorf("failed to link evidence in DB: %v", err)
+			}
+			// Fresh passing empirical evidence supersedes suspicion raised by
+			// changes to the carriers it re-covers; verification and audit
+			// evidence do not re-check the carrier.
+			if normalizedVerdict == "pass" && currentPhase != PhaseDeduction && currentPhase != PhaseDecision {
+				if err := tx.clearRecoveredSuspects(ctx, targetID, carrierRef); err != nil {
+					return fmt.Errorf("failed to clear suspect evidence: %v", err)
+				}
+			}
 		}
+		tx.notifyHolonChanged(targetID)
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	if !shouldPromote && verdict == "PASS" {
@@ -518,6 +649,21 @@ func (t *Tools) ManageEvidence(currentPhase Phase, action, targetID, evidenceTyp
 	return path, nil
 }
 
+// evidenceFile picks the evidence ID and path for a new record. A second
+// record of the same type on the same day gets a numeric suffix rather than
+// overwriting the first.
+func (t *Tools) evidenceFile(date, evidenceType, targetID string) (string, string) {
+	dir := filepath.Join(t.holonContextDir(targetID), "evidence")
+	base := fmt.Sprintf("%s-%s-%s", date, evidenceType, targetID)
+	filename := base + ".md"
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(dir, filename)); os.IsNotExist(err) {
+			return filename, filepath.Join(dir, filename)
+		}
+		filename = fmt.Sprintf("%s-%d.md", base, n)
+	}
+}
+
 func (t *Tools) RefineLoopback(currentPhase Phase, parentID, insight, newTitle, newContent, scope string) (string, error) {
 	defer t.RecordWork("RefineLoopback", time.Now())
 
@@ -531,85 +677,161 @@ func (t *Tools) RefineLoopback(currentPhase Phase, parentID, insight, newTitle,
 		return "", fmt.Errorf("loopback not applicable from phase %s", currentPhase)
 	}
 
-	if _, err := t.MoveHypothesis(parentID, parentLevel, "invalid"); err != nil {
-		return "", fmt.Errorf("failed to move parent hypothesis to invalid: %v", err)
-	}
+	var childPath string
+	err := t.inUnit(func(tx *Tools) error {
+		if _, err := tx.MoveHypothesis(parentID, parentLevel, "invalid"); err != nil {
+			return fmt.Errorf("failed to move parent hypothesis to invalid: %v", err)
+		}
 
-	rationale := fmt.Sprintf(`{"source": "loopback", "parent_id": "%s", "insight": "%s"}`, parentID, insight)
-	childPath, err := t.ProposeHypothesis(newTitle, newContent, scope, "system", rationale, "", nil, 3)
-	if err != nil {
-		return "", fmt.Errorf("failed to create child hypothesis: %v", err)
-	}
+		rationale := fmt.Sprintf(`{"source": "loopback", "parent_id": "%s", "insight": "%s"}`, parentID, insight)
+		var err error
+		childPath, err = tx.ProposeHypothesis(newTitle, newContent, scope, "system", rationale, "", nil, 3, assurance.MinFormality, "", tx.holonContextID(parentID))
+		if err != nil {
+			return fmt.Errorf("failed to create child hypothesis: %v", err)
+		}
 
-	logFile := filepath.Join(t.GetFPFDir(), "sessions", fmt.Sprintf("loopback-%d.md", time.Now().Unix()))
-	logContent := fmt.Sprintf("# Loopback Event\n\nParent: %s (moved to invalid)\nInsight: %s\nChild: %s\n", parentID, insight, childPath)
-	if err := os.WriteFile(logFile, []byte(logContent), 0644); err != nil {
-		return "", fmt.Errorf("failed to write loopback log file: %v", err)
+		logFile := filepath.Join(tx.holonContextDir(parentID), "sessions", fmt.Sprintf("loopback-%d.md", time.Now().Unix()))
+		logContent := fmt.Sprintf("# Loopback Event\n\nParent: %s (moved to invalid)\nInsight: %s\nChild: %s\n", parentID, insight, childPath)
+		if err := tx.uow.writeFile(logFile, []byte(logContent)); err != nil {
+			return fmt.Errorf("failed to write loopback log file: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	return childPath, nil
 }
 
-func (t *Tools) FinalizeDecision(title, winnerID string, rejectedIDs []string, decisionContext, decision, rationale, consequences, characteristics string) (string, error) {
+// FinalizeDecision records a DRR with the given status (accepted unless
+// proposed) and marks every DRR in supersedes as superseded by it.
+func (t *Tools) FinalizeDecision(title, winnerID string, rejectedIDs []string, decisionContext, decision, rationale, consequences, characteristics, status string, supersedes []string) (string, error) {
 	defer t.RecordWork("FinalizeDecision", time.Now())
 
+	if status == "" {
+		status = DRRAccepted
+	}
+	if status != DRRProposed && status != DRRAccepted {
+		return "", fmt.Errorf("invalid DRR status %q: a new DRR is proposed or accepted", status)
+	}
+
 	body := fmt.Sprintf("\n# %s\n\n", title)
 	body += fmt.Sprintf("## Context\n%s\n\n", decisionContext)
-	body += fmt.Sprintf("## Decision\n**Selected Option:** %s\n\n%s\n\n", winnerID, decision)
+	body += fmt.Sprintf("## Decision\n**Selected Option:** %s\n\n", winnerID)
+	if len(supersedes) > 0 {
+		body += fmt.Sprintf("**Supersedes:** %s\n\n", strings.Join(supersedes, ", "))
+	}
+	body += fmt.Sprintf("%s\n\n", decision)
 	body += fmt.Sprintf("## Rationale\n%s\n\n", rationale)
 	if characteristics != "" {
 		body += fmt.Sprintf("### Characteristic Space (C.16)\n%s\n\n", characteristics)
 	}
+	if table := t.decisionComparison(winnerID, rejectedIDs); table != "" {
+		body += fmt.Sprintf("### Multi-Criteria Comparison\n%s\n", table)
+	}
 	body += fmt.Sprintf("## Consequences\n%s\n", consequences)
 
 	now := time.Now()
 	dateStr := now.Format("2006-01-02")
 	drrName := fmt.Sprintf("DRR-%s-%s.md", dateStr, t.Slugify(title))
-	drrPath := filepath.Join(t.GetFPFDir(), "decisions", drrName)
+	// The DRR belongs to the context its winner was proposed in.
+	contextID := t.ContextID()
+	if winnerID != "" {
+		contextID = t.holonContextID(winnerID)
+	}
+	drrPath := filepath.Join(t.contextDir(contextID), "decisions", drrName)
 
+	drrID := t.Slugify(title)
 	fields := map[string]string{
+		"id":        drrID,
 		"type":      "DRR",
 		"winner_id": winnerID,
+		"status":    status,
 		"created":   now.Format(time.RFC3339),
 	}
-
-	if err := WriteWithHash(drrPath, fields, body); err != nil {
-		t.AuditLog("quint_decide", "finalize_decision", "agent", winnerID, "ERROR", map[string]string{"title": title}, err.Error())
-		return "", err
+	if len(rejectedIDs) > 0 {
+		fields["rejected_ids"] = strings.Join(rejectedIDs, ", ")
+	}
+	if len(supersedes) > 0 {
+		fields["supersedes"] = strings.Join(supersedes, ", ")
 	}
 
-	if t.DB != nil {
-		ctx := context.Background()
-		drrID := t.Slugify(title)
-		if err := t.DB.CreateHolon(ctx, drrID, "DRR", "", "DRR", title, body, "default", "", winnerID); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to create DRR holon in DB: %v\n", err)
+	err := t.inUnit(func(tx *Tools) error {
+		// Freeze the assurance the decision rests on before the winner moves to L2.
+		var snaps []holonSnapshot
+		if tx.DB != nil {
+			var err error
+			if snaps, err = tx.decisionSnapshot(context.Background(), winnerID, rejectedIDs); err != nil {
+				return err
+			}
+		}
+		if section := snapshotMarkdown(snaps, now); section != "" {
+			body += "\n" + section
 		}
 
-		// Create selects relation: DRR → winner
-		if winnerID != "" {
-			if err := t.createRelation(ctx, drrID, "selects", winnerID, 3); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create selects relation: %v\n", err)
-			}
+		if err := tx.uow.writeProjection(drrPath, fields, body); err != nil {
+			return err
 		}
 
-		// Create rejects relations: DRR → each rejected alternative
-		for _, rejID := range rejectedIDs {
-			if rejID != "" && rejID != winnerID {
-				if err := t.createRelation(ctx, drrID, "rejects", rejID, 3); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to create rejects relation to %s: %v\n", rejID, err)
+		if tx.DB != nil {
+			ctx := context.Background()
+			if err := tx.DB.CreateHolon(ctx, drrID, "DRR", "", "DRR", title, body, contextID, "", winnerID); err != nil {
+				return fmt.Errorf("failed to create DRR holon in DB: %v", err)
+			}
+			if err := tx.DB.UpdateHolonStatus(ctx, drrID, status); err != nil {
+				return fmt.Errorf("failed to set DRR status: %v", err)
+			}
+			if err := tx.saveDecisionSnapshot(ctx, drrID, snaps); err != nil {
+				return err
+			}
+
+			// Mark each replaced DRR superseded: DRR → supersedes → old DRR
+			for _, oldID := range supersedes {
+				old, err := tx.getDRR(ctx, oldID)
+				if err != nil {
+					return err
+				}
+				if err := tx.createRelation(ctx, drrID, "supersedes", oldID, 3); err != nil {
+					return fmt.Errorf("failed to create supersedes relation to %s: %v", oldID, err)
+				}
+				if err := tx.transitionDRR(ctx, old, DRRSuperseded, map[string]string{"superseded_by": drrID}); err != nil {
+					return err
+				}
+			}
+
+			// Create selects relation: DRR → winner
+			if winnerID != "" {
+				if err := tx.createRelation(ctx, drrID, "selects", winnerID, 3); err != nil {
+					return fmt.Errorf("failed to create selects relation: %v", err)
+				}
+			}
+
+			// Create rejects relations: DRR → each rejected alternative
+			for _, rejID := range rejectedIDs {
+				if rejID != "" && rejID != winnerID {
+					if err := tx.createRelation(ctx, drrID, "rejects", rejID, 3); err != nil {
+						return fmt.Errorf("failed to create rejects relation to %s: %v", rejID, err)
+					}
 				}
 			}
 		}
-	}
 
-	if winnerID != "" {
-		_, err := t.MoveHypothesis(winnerID, "L1", "L2")
-		if err != nil {
-			fmt.Printf("WARNING: Failed to move winner hypothesis %s to L2: %v\n", winnerID, err)
+		// A winner already promoted by induction stays where it is.
+		l2Path := filepath.Join(tx.contextDir(contextID), "knowledge", "L2", winnerID+".md")
+		if _, err := os.Stat(l2Path); winnerID != "" && os.IsNotExist(err) {
+			if _, err := tx.MoveHypothesis(winnerID, "L1", "L2"); err != nil {
+				return fmt.Errorf("failed to move winner hypothesis %s to L2: %v", winnerID, err)
+			}
 		}
-	}
 
-	t.AuditLog("quint_decide", "finalize_decision", "agent", winnerID, "SUCCESS", map[string]string{"title": title, "drr": drrName}, "")
+		tx.AuditLog("quint_decide", "finalize_decision", tx.actor(), winnerID, "SUCCESS", map[string]string{"title": title, "drr": drrName, "status": status}, "")
+		return nil
+	})
+	if err != nil {
+		t.AuditLog("quint_decide", "finalize_decision", t.actor(), winnerID, "ERROR", map[string]string{"title": title}, err.Error())
+		return "", err
+	}
 	return drrPath, nil
 }
 
@@ -625,7 +847,7 @@ func (t *Tools) RunDecay() error {
 		return err
 	}
 
-	calc := assurance.New(t.DB.GetRawDB())
+	calc := t.FSM.NewCalculator(t.DB)
 	updatedCount := 0
 
 	for _, id := range ids {
@@ -651,7 +873,7 @@ func (t *Tools) VisualizeAudit(rootID string) (string, error) {
 		return "Please specify a root ID for the audit tree.", nil
 	}
 
-	calc := assurance.New(t.DB.GetRawDB())
+	calc := t.FSM.NewCalculator(t.DB)
 	return t.buildAuditTree(rootID, 0, calc)
 }
 
@@ -663,13 +885,17 @@ func (t *Tools) buildAuditTree(holonID string, level int, calc *assurance.Calcul
 	}
 
 	indent := strings.Repeat("  ", level)
-	tree := fmt.Sprintf("%s[%s R:%.2f] %s\n", indent, holonID, report.FinalScore, t.getHolonTitle(holonID))
+	tree := fmt.Sprintf("%s[%s R:%.2f %s G:%s] %s\n", indent, holonID, report.FinalScore,
+		assurance.FormatFormality(report.Formality), report.ClaimScope, t.getHolonTitle(holonID))
 
 	if len(report.Factors) > 0 {
 		for _, f := range report.Factors {
 			tree += fmt.Sprintf("%s  ! %s\n", indent, f)
 		}
 	}
+	for _, f := range t.decisionFlags(ctx, holonID) {
+		tree += fmt.Sprintf("%s  ! %s\n", indent, f)
+	}
 
 	// Show componentOf/constituentOf dependencies (these propagate WLNK)
 	components, err := t.DB.GetComponentsOf(ctx, holonID)
@@ -690,7 +916,7 @@ func (t *Tools) buildAuditTree(holonID string, level int, calc *assurance.Calcul
 	}
 
 	// Show memberOf relations (alternatives grouped under decision context)
-	// Note: memberOf does NOT propagate R, shown for visibility only
+	// Note: memberOf does NOT propagate R or F; G is the union of members
 	members, err := t.DB.GetCollectionMembers(ctx, holonID)
 	if err == nil && len(members) > 0 {
 		tree += fmt.Sprintf("%s  [members]\n", indent)
@@ -700,7 +926,8 @@ func (t *Tools) buildAuditTree(holonID string, level int, calc *assurance.Calcul
 				tree += fmt.Sprintf("%s    - %s (error)\n", indent, m.SourceID)
 				continue
 			}
-			tree += fmt.Sprintf("%s    - [%s R:%.2f] %s\n", indent, m.SourceID, memberReport.FinalScore, t.getHolonTitle(m.SourceID))
+			tree += fmt.Sprintf("%s    - [%s R:%.2f %s G:%s] %s\n", indent, m.SourceID, memberReport.FinalScore,
+				assurance.FormatFormality(memberReport.Formality), memberReport.ClaimScope, t.getHolonTitle(m.SourceID))
 		}
 	}
 
@@ -756,7 +983,7 @@ func (t *Tools) Actualize() (string, error) {
 		if lastCommit == "" {
 			report.WriteString(fmt.Sprintf("RECONCILIATION: Initializing baseline commit to %s\n", currentCommit))
 			t.FSM.State.LastCommit = currentCommit
-			if err := t.FSM.SaveState("default"); err != nil {
+			if err := t.FSM.SaveState(t.ContextID()); err != nil {
 				report.WriteString(fmt.Sprintf("Warning: Failed to save state: %v\n", err))
 			}
 		} else if currentCommit != lastCommit {
@@ -767,12 +994,18 @@ func (t *Tools) Actualize() (string, error) {
 			if err == nil {
 				report.WriteString("Changed files:\n")
 				report.WriteString(string(diffOutput))
+
+				suspect, err := t.reconcileCarriers(ParseNameStatus(string(diffOutput)), lastCommit, currentCommit)
+				report.WriteString(suspect)
+				if err != nil {
+					report.WriteString(fmt.Sprintf("Warning: Failed to check evidence carriers: %v\n", err))
+				}
 			} else {
 				report.WriteString(fmt.Sprintf("Warning: Failed to get diff: %v\n", err))
 			}
 
 			t.FSM.State.LastCommit = currentCommit
-			if err := t.FSM.SaveState("default"); err != nil {
+			if err := t.FSM.SaveState(t.ContextID()); err != nil {
 				report.WriteString(fmt.Sprintf("Warning: Failed to save state: %v\n", err))
 			}
 		} else {
@@ -792,21 +1025,35 @@ func (t *Tools) GetHolon(id string) (db.Holon, error) {
 	return t.DB.GetHolon(context.Background(), id)
 }
 
+// maxReportActions caps the validation steps listed in the markdown report;
+// the structured report keeps all of them.
+const maxReportActions = 5
+
 func (t *Tools) CalculateR(holonID string) (string, error) {
+	output, _, err := t.ExplainR(holonID)
+	return output, err
+}
+
+// ExplainR calculates R_eff with its sensitivity analysis and returns both the
+// markdown report and the structured one.
+func (t *Tools) ExplainR(holonID string) (string, *assurance.AssuranceReport, error) {
 	defer t.RecordWork("CalculateR", time.Now())
 	if t.DB == nil {
-		return "", fmt.Errorf("DB not initialized")
+		return "", nil, fmt.Errorf("DB not initialized")
 	}
 
-	calc := assurance.New(t.DB.GetRawDB())
-	report, err := calc.CalculateReliability(context.Background(), holonID)
+	calc := t.FSM.NewCalculator(t.DB)
+	report, err := calc.Explain(context.Background(), holonID)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("## Reliability Report: %s\n\n", holonID))
+	result.WriteString(fmt.Sprintf("**⟨F,G,R⟩: ⟨%s, %s, %.2f⟩**\n\n", assurance.FormatFormality(report.Formality), report.ClaimScope, report.FinalScore))
 	result.WriteString(fmt.Sprintf("**R_eff: %.2f**\n", report.FinalScore))
+	result.WriteString(fmt.Sprintf("- Formality (F): %s\n", assurance.FormatFormality(report.Formality)))
+	result.WriteString(fmt.Sprintf("- Claim Scope (G): %s\n", report.ClaimScope))
 	result.WriteString(fmt.Sprintf("- Self Score: %.2f\n", report.SelfScore))
 	if report.WeakestLink != "" {
 		result.WriteString(fmt.Sprintf("- Weakest Link: %s\n", report.WeakestLink))
@@ -814,14 +1061,49 @@ func (t *Tools) CalculateR(holonID string) (string, error) {
 	if report.DecayPenalty > 0 {
 		result.WriteString(fmt.Sprintf("- Decay Penalty: %.2f\n", report.DecayPenalty))
 	}
+	result.WriteString(fmt.Sprintf("- Φ(CL) Profile: %s\n", report.PhiProfile))
+	result.WriteString(fmt.Sprintf("- Decay Profile: %s\n", report.DecayProfile))
+	if len(report.CriticalPath) > 1 || len(report.BoundingEvidence) > 0 {
+		result.WriteString(fmt.Sprintf("\n**Critical path:** %s\n", strings.Join(report.CriticalPath, " → ")))
+		if len(report.BoundingEvidence) > 0 {
+			result.WriteString(fmt.Sprintf("- Bounded by evidence: %s\n", strings.Join(report.BoundingEvidence, ", ")))
+		}
+	}
+	if len(report.WeakLinks) > 1 {
+		result.WriteString(fmt.Sprintf("- Tied weak links: %s (raising only one leaves R_eff unchanged)\n", strings.Join(report.WeakLinks, ", ")))
+	}
+	if len(report.Dependencies) > 0 {
+		result.WriteString("\n| Dependency | CL | R_eff | Φ(CL) | Effective |\n")
+		result.WriteString("|------------|----|-------|-------|-----------|\n")
+		for _, d := range report.Dependencies {
+			result.WriteString(fmt.Sprintf("| %s | CL%d | %.2f | %.2f | %.2f |\n", d.ID, d.CL, d.Score, d.Penalty, d.Effective))
+		}
+	}
 	if len(report.Factors) > 0 {
 		result.WriteString("\n**Factors:**\n")
 		for _, f := range report.Factors {
 			result.WriteString(fmt.Sprintf("- %s\n", f))
 		}
 	}
+	if len(report.Actions) > 0 {
+		result.WriteString("\n**Next validation steps (by assurance gain):**\n\n")
+		result.WriteString("| # | Action | R_eff after | Gain |\n")
+		result.WriteString("|---|--------|-------------|------|\n")
+		for i, a := range report.Actions {
+			if i == maxReportActions {
+				result.WriteString(fmt.Sprintf("\n...and %d more.\n", len(report.Actions)-maxReportActions))
+				break
+			}
+			result.WriteString(fmt.Sprintf("| %d | %s | %.2f | %+.2f |\n", i+1, a.Description, a.REff, a.Gain))
+		}
+	}
+	comparison, err := t.snapshotComparison(context.Background(), holonID, report.FinalScore)
+	if err != nil {
+		return "", nil, err
+	}
+	result.WriteString(comparison)
 
-	return result.String(), nil
+	return result.String(), report, nil
 }
 
 func (t *Tools) CheckDecay(deprecate, waiveID, waiveUntil, waiveRationale string) (string, error) {
@@ -850,6 +1132,10 @@ func (t *Tools) deprecateHolon(holonID string) (string, error) {
 		return "", fmt.Errorf("holon not found: %s", holonID)
 	}
 
+	if holon.Type == "DRR" {
+		return t.SetDRRStatus(holonID, DRRDeprecated, "deprecated via quint_check_decay")
+	}
+
 	var newLayer string
 	switch holon.Layer {
 	case "L2":
@@ -909,32 +1195,22 @@ func (t *Tools) createWaiver(evidenceID, until, rationale string) (string, error
 
 func (t *Tools) generateFreshnessReport() (string, error) {
 	ctx := context.Background()
-	rawDB := t.DB.GetRawDB()
-
-	rows, err := rawDB.QueryContext(ctx, `
-		SELECT
-			e.id as evidence_id,
-			e.holon_id,
-			h.title,
-			h.layer,
-			e.type as evidence_type,
-			CAST(JULIANDAY('now') - JULIANDAY(substr(e.valid_until, 1, 10)) AS INTEGER) as days_overdue
-		FROM evidence e
-		JOIN holons h ON e.holon_id = h.id
-		LEFT JOIN (
-			SELECT evidence_id, MAX(waived_until) as latest_waiver
-			FROM waivers
-			GROUP BY evidence_id
-		) w ON e.id = w.evidence_id
-		WHERE e.valid_until IS NOT NULL
-		  AND substr(e.valid_until, 1, 10) < date('now')
-		  AND (w.latest_waiver IS NULL OR w.latest_waiver < datetime('now'))
-		ORDER BY h.id, days_overdue DESC
-	`)
+	now := time.Now()
+	today := now.UTC().Format("2006-01-02")
+
+	waivers, err := t.DB.GetAllActiveWaivers(ctx)
+	if err != nil {
+		return "", err
+	}
+	waived := make(map[string]bool, len(waivers))
+	for _, w := range waivers {
+		waived[w.EvidenceID] = true
+	}
+
+	evidence, err := t.DB.ListAllEvidence(ctx)
 	if err != nil {
 		return "", err
 	}
-	defer rows.Close() //nolint:errcheck
 
 	type evidenceInfo struct {
 		ID          string
@@ -946,34 +1222,32 @@ func (t *Tools) generateFreshnessReport() (string, error) {
 	holonTitles := make(map[string]string)
 	holonLayers := make(map[string]string)
 
-	for rows.Next() {
-		var evidenceID, holonID, title, layer, evidenceType string
-		var daysOverdue int
-		if err := rows.Scan(&evidenceID, &holonID, &title, &layer, &evidenceType, &daysOverdue); err != nil {
+	for _, e := range evidence {
+		// Expired means the valid_until date is before today; an active
+		// waiver suspends it.
+		if !e.ValidUntil.Valid || waived[e.ID] {
+			continue
+		}
+		expiryDay := e.ValidUntil.Time.Format("2006-01-02")
+		if expiryDay >= today {
+			continue
+		}
+		holon, err := t.DB.GetHolon(ctx, e.HolonID)
+		if err != nil {
 			continue
 		}
-		holonTitles[holonID] = title
-		holonLayers[holonID] = layer
-		staleHolons[holonID] = append(staleHolons[holonID], evidenceInfo{
-			ID:          evidenceID,
-			Type:        evidenceType,
-			DaysOverdue: daysOverdue,
+		expiry, _ := time.Parse("2006-01-02", expiryDay)
+		holonTitles[e.HolonID] = holon.Title
+		holonLayers[e.HolonID] = holon.Layer
+		staleHolons[e.HolonID] = append(staleHolons[e.HolonID], evidenceInfo{
+			ID:          e.ID,
+			Type:        e.Type,
+			DaysOverdue: int(now.Sub(expiry).Hours() / 24),
 		})
 	}
-
-	waivedRows, err := rawDB.QueryContext(ctx, `
-		SELECT w.evidence_id, e.holon_id, h.title, w.waived_until, w.waived_by, w.rationale,
-		       CAST(JULIANDAY(w.waived_until) - JULIANDAY('now') AS INTEGER) as days_until_expiry
-		FROM waivers w
-		JOIN evidence e ON w.evidence_id = e.id
-		JOIN holons h ON e.holon_id = h.id
-		WHERE w.waived_until > datetime('now')
-		ORDER BY w.waived_until ASC
-	`)
-	if err != nil {
-		return "", err
+	for _, items := range staleHolons {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].DaysOverdue > items[j].DaysOverdue })
 	}
-	defer waivedRows.Close() //nolint:errcheck
 
 	type waiverInfo struct {
 		EvidenceID      string
@@ -986,12 +1260,24 @@ func (t *Tools) generateFreshnessReport() (string, error) {
 	}
 
 	var activeWaivers []waiverInfo
-	for waivedRows.Next() {
-		var info waiverInfo
-		if err := waivedRows.Scan(&info.EvidenceID, &info.HolonID, &info.HolonTitle, &info.WaivedUntil, &info.WaivedBy, &info.Rationale, &info.DaysUntilExpiry); err != nil {
+	for _, w := range waivers {
+		e, err := t.DB.GetEvidenceByID(ctx, w.EvidenceID)
+		if err != nil {
+			continue
+		}
+		holon, err := t.DB.GetHolon(ctx, e.HolonID)
+		if err != nil {
 			continue
 		}
-		activeWaivers = append(activeWaivers, info)
+		activeWaivers = append(activeWaivers, waiverInfo{
+			EvidenceID:      w.EvidenceID,
+			HolonID:         e.HolonID,
+			HolonTitle:      holon.Title,
+			WaivedUntil:     w.WaivedUntil.Format("2006-01-02"),
+			WaivedBy:        w.WaivedBy,
+			Rationale:       w.Rationale,
+			DaysUntilExpiry: int(w.WaivedUntil.Sub(now).Hours() / 24),
+		})
 	}
 
 	var result strings.Builder
@@ -1016,16 +1302,61 @@ func (t *Tools) generateFreshnessReport() (string, error) {
 		}
 	}
 
+	suspects, err := t.DB.GetSuspectEvidence(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(suspects) > 0 {
+		result.WriteString("---\n\n### SUSPECT (carrier changed since the evidence was recorded)\n\n")
+		result.WriteString("| Holon | Evidence | Changed | Commits |\n")
+		result.WriteString("|-------|----------|---------|---------|\n")
+		for _, s := range suspects {
+			result.WriteString(fmt.Sprintf("| %s | %s | %s | %s..%s |\n", s.HolonID, s.EvidenceID, s.ChangedPaths, shortCommit(s.FromCommit.String), shortCommit(s.ToCommit.String)))
+		}
+		result.WriteString("\nActions:\n  → /q3-validate <holon> (passing test evidence on the changed carrier clears suspicion)\n\n")
+	}
+
+	holons, err := t.DB.ListHolons(ctx)
+	if err != nil {
+		return "", err
+	}
+	var inactive []db.Holon
+	for _, h := range holons {
+		if h.Type == "DRR" && !isActiveDRR(drrStatus(h)) {
+			inactive = append(inactive, h)
+		}
+	}
+	if len(inactive) > 0 {
+		result.WriteString("---\n\n### SUPERSEDED (decisions that no longer stand)\n\n")
+		result.WriteString("| DRR | Status | Winner |\n")
+		result.WriteString("|-----|--------|--------|\n")
+		var orphaned []string
+		for _, h := range inactive {
+			winner := orDash(h.ParentID.String)
+			if w, err := t.DB.GetHolon(ctx, h.ParentID.String); err == nil {
+				winner = fmt.Sprintf("%s (%s)", w.ID, w.Layer)
+				if len(t.decisionFlags(ctx, w.ID)) > 0 && !slices.Contains(orphaned, w.ID) {
+					winner += " ⚠ no standing DRR"
+					orphaned = append(orphaned, w.ID)
+				}
+			}
+			result.WriteString(fmt.Sprintf("| %s | %s | %s |\n", h.ID, t.describeInactiveDRR(ctx, h), winner))
+		}
+		if len(orphaned) > 0 {
+			result.WriteString("\nActions:\n")
+			for _, id := range orphaned {
+				result.WriteString(fmt.Sprintf("  → /q-decay --deprecate %s (the decision that selected it no longer stands)\n", id))
+			}
+		}
+		result.WriteString("\n")
+	}
+
 	if len(activeWaivers) > 0 {
 		result.WriteString("---\n\n### WAIVED (temporary risk acceptance)\n\n")
 		result.WriteString("| Holon | Evidence | Waived Until | By | Rationale |\n")
 		result.WriteString("|-------|----------|--------------|----|-----------|\n")
 		for _, w := range activeWaivers {
-			waivedUntilShort := w.WaivedUntil
-			if len(waivedUntilShort) > 10 {
-				waivedUntilShort = waivedUntilShort[:10]
-			}
-			result.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", w.HolonTitle, w.EvidenceID, waivedUntilShort, w.WaivedBy, w.Rationale))
+			result.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", w.HolonTitle, w.EvidenceID, w.WaivedUntil, w.WaivedBy, w.Rationale))
 		}
 		for _, w := range activeWaivers {
 			if w.DaysUntilExpiry <= 30 {
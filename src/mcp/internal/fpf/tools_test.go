@@ -2,11 +2,14 @@ package fpf
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/m0n0x41d/quint-code/db"
 )
@@ -26,7 +29,7 @@ func setupTools(t *testing.T) (*Tools, *FSM, string) {
 		t.Fatalf("Failed to initialize DB: %v", err)
 	}
 
-	fsm := &FSM{State: State{Phase: PhaseIdle}, DB: database.GetRawDB()} // Initial FSM state with DB
+	fsm := &FSM{State: State{Phase: PhaseIdle}, DB: database} // Initial FSM state with DB
 
 	tools := NewTools(fsm, tempDir, database)
 
@@ -39,6 +42,17 @@ func setupTools(t *testing.T) (*Tools, *FSM, string) {
 	return tools, fsm, tempDir
 }
 
+// sqlDB returns the SQLite handle behind tools for tests that inspect tables
+// directly.
+func sqlDB(t *testing.T, tools *Tools) *sql.DB {
+	t.Helper()
+	store, ok := tools.DB.(*db.Store)
+	if !ok {
+		t.Fatalf("Expected a SQLite store, got %T", tools.DB)
+	}
+	return store.GetRawDB()
+}
+
 func TestSlugify(t *testing.T) {
 
 	tools, _, _ := setupTools(t)
@@ -94,7 +108,7 @@ func TestProposeHypothesis(t *testing.T) {
 	kind := "system"
 	rationale := "This is the rationale."
 
-	path, err := tools.ProposeHypothesis(title, content, scope, kind, rationale, "", nil, 3)
+	path, err := tools.ProposeHypothesis(title, content, scope, kind, rationale, "", nil, 3, 0, "", "")
 	if err != nil {
 		t.Fatalf("ProposeHypothesis failed: %v", err)
 	}
@@ -283,7 +297,7 @@ func TestFinalizeDecision(t *testing.T) {
 	title := "Final Project Decision"
 	content := "This is the DRR content for the decision."
 
-	drrPath, err := tools.FinalizeDecision(title, winnerID, nil, "Context", content, "Rationale", "Consequences", "Characteristics")
+	drrPath, err := tools.FinalizeDecision(title, winnerID, nil, "Context", content, "Rationale", "Consequences", "Characteristics", "", nil)
 	if err != nil {
 		t.Fatalf("FinalizeDecision failed: %v", err)
 	}
@@ -365,6 +379,98 @@ func TestVerifyHypothesis(t *testing.T) {
 	}
 }
 
+func TestProposeHypothesis_RollsBackOnDBFailure(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	path, err := tools.ProposeHypothesis("Use Redis", "Original", "global", "system", "{}", "", nil, 3, 0, "", "")
+	if err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	original, _ := os.ReadFile(path)
+
+	// Same slug in the same context: the file is rewritten, then the insert fails.
+	if _, err := tools.ProposeHypothesis("Use Redis", "Rewritten", "global", "system", "{}", "", nil, 3, 0, "", ""); err == nil {
+		t.Fatal("Expected a duplicate proposal to fail")
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(original) {
+		t.Errorf("Expected the original hypothesis file to be restored, got:\n%s", after)
+	}
+}
+
+func TestManageEvidence_RollsBackOnDBFailure(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	var notified []string
+	tools.OnHolonChanged = func(id string) { notified = append(notified, id) }
+
+	if _, err := tools.ProposeHypothesis("Use Redis", "Redis cache", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	// A stale row with the ID the evidence will get makes the insert fail
+	// after the hypothesis has already been moved.
+	date := time.Now().Format("2006-01-02")
+	if err := tools.DB.AddEvidence(ctx, date+"-logic-use-redis.md", "use-redis", "logic", "stale", "pass", "L1", "", ""); err != nil {
+		t.Fatalf("AddEvidence failed: %v", err)
+	}
+
+	if _, err := tools.ManageEvidence(PhaseDeduction, "add", "use-redis", "logic", "ok", "PASS", "L1", "", ""); err == nil {
+		t.Fatal("Expected ManageEvidence to fail")
+	}
+
+	knowledge := filepath.Join(tempDir, ".quint", "knowledge")
+	if _, err := os.Stat(filepath.Join(knowledge, "L0", "use-redis.md")); err != nil {
+		t.Errorf("Expected hypothesis back in L0: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(knowledge, "L1", "use-redis.md")); !os.IsNotExist(err) {
+		t.Errorf("Expected no L1 copy after rollback")
+	}
+	if holon, _ := tools.DB.GetHolon(ctx, "use-redis"); holon.Layer != "L0" {
+		t.Errorf("Expected DB layer L0 after rollback, got %s", holon.Layer)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, ".quint", "evidence", date+"-logic-use-redis.md")); !os.IsNotExist(err) {
+		t.Errorf("Expected no evidence file after rollback")
+	}
+	if len(notified) != 0 {
+		t.Errorf("Expected no change notifications for a rolled-back call, got %v", notified)
+	}
+}
+
+func TestFinalizeDecision_RollsBackOnFailure(t *testing.T) {
+	tools, _, tempDir := setupTools(t)
+	ctx := context.Background()
+
+	// The winner was never verified, so it cannot be promoted to L2.
+	if _, err := tools.ProposeHypothesis("Use Redis", "Redis cache", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	if _, err := tools.FinalizeDecision("Cache Choice", "use-redis", nil, "Context", "Decision", "Rationale", "Consequences", "", "", nil); err == nil {
+		t.Fatal("Expected FinalizeDecision to fail for an unverified winner")
+	}
+
+	if matches, _ := filepath.Glob(filepath.Join(tempDir, ".quint", "decisions", "DRR-*.md")); len(matches) != 0 {
+		t.Errorf("Expected no DRR file after rollback, got %v", matches)
+	}
+	if _, err := tools.DB.GetHolon(ctx, "cache-choice"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected no DRR holon after rollback, got %v", err)
+	}
+	if rels, _ := tools.DB.GetRelationsByTarget(ctx, "use-redis", "selects"); len(rels) != 0 {
+		t.Errorf("Expected no selects relation after rollback, got %+v", rels)
+	}
+
+	audited := false
+	logs, _ := tools.DB.GetAuditLogByTarget(ctx, "use-redis")
+	for _, l := range logs {
+		if l.ToolName == "quint_decide" {
+			audited = l.Result == "ERROR"
+		}
+	}
+	if !audited {
+		t.Errorf("Expected only the failed decision to be audited, got %+v", logs)
+	}
+}
+
 func TestAuditEvidence(t *testing.T) {
 
 	tools, fsm, _ := setupTools(t)
@@ -428,6 +534,49 @@ func TestCalculateR(t *testing.T) {
 	}
 }
 
+func TestProposeHypothesis_FormalityAndClaimScope(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	if _, err := tools.ProposeHypothesis("Base Proof", "Proven invariant", "global", "system", "{}", "", nil, 3, 7, "env=prod,staging", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis("Built On Proof", "Uses the invariant", "global", "system", "{}", "", []string{"base-proof"}, 3, 4, `{"env":["prod"],"db":["postgres"]}`, ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+
+	holon, err := tools.DB.GetHolon(ctx, "base-proof")
+	if err != nil {
+		t.Fatalf("GetHolon failed: %v", err)
+	}
+	if holon.Formality.Int64 != 7 || holon.ClaimScope.String != `{"env":["prod","staging"]}` {
+		t.Errorf("Unexpected stored F-G: %v %q", holon.Formality, holon.ClaimScope.String)
+	}
+
+	result, err := tools.CalculateR("built-on-proof")
+	if err != nil {
+		t.Fatalf("CalculateR failed: %v", err)
+	}
+	if !strings.Contains(result, "Formality (F): F4") || !strings.Contains(result, "Claim Scope (G): db=postgres; env=prod") {
+		t.Errorf("Expected F-G in report, got: %s", result)
+	}
+
+	tree, err := tools.VisualizeAudit("built-on-proof")
+	if err != nil {
+		t.Fatalf("VisualizeAudit failed: %v", err)
+	}
+	if !strings.Contains(tree, "[base-proof R:0.00 F7 G:env=prod,staging]") {
+		t.Errorf("Expected F-G on dependency node, got: %s", tree)
+	}
+
+	if _, err := tools.ProposeHypothesis("Bad F", "x", "global", "system", "{}", "", nil, 3, 12, "", ""); err == nil {
+		t.Error("Expected error for formality outside F0-F9")
+	}
+	if _, err := tools.ProposeHypothesis("Bad G", "x", "global", "system", "{}", "", nil, 3, 0, "no dimension", ""); err == nil {
+		t.Error("Expected error for malformed claim scope")
+	}
+}
+
 func TestCalculateR_WithDecay(t *testing.T) {
 	tools, _, _ := setupTools(t)
 	ctx := context.Background()
@@ -526,7 +675,7 @@ func TestCheckDecay_Deprecate(t *testing.T) {
 
 	// Create L2 holon with file
 	holonID := "deprecate-test"
-	err := tools.DB.CreateHolon(ctx, holonID, "hypothesis", "system", "L2", "Deprecate Test", "Content", "ctx", "global", "")
+	err := tools.DB.CreateHolon(ctx, holonID, "hypothesis", "system", "L2", "Deprecate Test", "Content", "default", "global", "")
 	if err != nil {
 		t.Fatalf("Failed to create holon: %v", err)
 	}
@@ -678,6 +827,9 @@ func TestVisualizeAudit(t *testing.T) {
 	if !strings.Contains(result, "R:") {
 		t.Errorf("Expected 'R:' score in output, got: %s", result)
 	}
+	if strings.Contains(result, "!") {
+		t.Errorf("Expected no warnings for a healthy holon, got: %s", result)
+	}
 }
 
 func TestPropose_WithDecisionContext(t *testing.T) {
@@ -701,13 +853,16 @@ func TestPropose_WithDecisionContext(t *testing.T) {
 		"caching-decision", // decision_context
 		nil,                // no depends_on
 		3,
+		0,
+		"",
+		"",
 	)
 	if err != nil {
 		t.Fatalf("ProposeHypothesis failed: %v", err)
 	}
 
 	// Verify MemberOf relation was created
-	rawDB := tools.DB.GetRawDB()
+	rawDB := sqlDB(t, tools)
 	var count int
 	err = rawDB.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM relations
@@ -748,13 +903,16 @@ func TestPropose_WithDependsOn(t *testing.T) {
 		"",                                      // no decision_context
 		[]string{"auth-module", "rate-limiter"}, // depends_on
 		3,                                       // CL3
+		0,
+		"",
+		"",
 	)
 	if err != nil {
 		t.Fatalf("ProposeHypothesis failed: %v", err)
 	}
 
 	// Verify componentOf relations were created
-	rawDB := tools.DB.GetRawDB()
+	rawDB := sqlDB(t, tools)
 	var count int
 	err = rawDB.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM relations
@@ -781,7 +939,7 @@ func TestPropose_CycleDetection(t *testing.T) {
 	}
 
 	// Create holon B that depends on A
-	_, err = tools.ProposeHypothesis("Holon B", "B depends on A", "global", "system", "{}", "", []string{"holon-a"}, 3)
+	_, err = tools.ProposeHypothesis("Holon B", "B depends on A", "global", "system", "{}", "", []string{"holon-a"}, 3, 0, "", "")
 	if err != nil {
 		t.Fatalf("ProposeHypothesis for B failed: %v", err)
 	}
@@ -795,7 +953,7 @@ func TestPropose_CycleDetection(t *testing.T) {
 
 	// Try to make A depend on B (would create cycle since B already depends on A)
 	// This should be skipped with a warning, not error
-	_, err = tools.ProposeHypothesis("Holon C Cyclic", "C tries to depend on B", "global", "system", "{}", "", []string{"holon-b"}, 3)
+	_, err = tools.ProposeHypothesis("Holon C Cyclic", "C tries to depend on B", "global", "system", "{}", "", []string{"holon-b"}, 3, 0, "", "")
 	// Should NOT error - cycles are skipped with warning
 	if err != nil {
 		t.Fatalf("ProposeHypothesis should not error on cycle, got: %v", err)
@@ -803,7 +961,7 @@ func TestPropose_CycleDetection(t *testing.T) {
 
 	// The relation should still be created since holon-c-cyclic → holon-b is not itself a cycle
 	// (holon-b → holon-a exists, but holon-a doesn't depend on holon-c-cyclic)
-	rawDB := tools.DB.GetRawDB()
+	rawDB := sqlDB(t, tools)
 	var count int
 	err = rawDB.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM relations
@@ -834,6 +992,9 @@ func TestPropose_InvalidDependency(t *testing.T) {
 		"",
 		[]string{"does-not-exist", "also-missing"}, // These don't exist
 		3,
+		0,
+		"",
+		"",
 	)
 	// Should NOT error - invalid deps are skipped with warning
 	if err != nil {
@@ -841,7 +1002,7 @@ func TestPropose_InvalidDependency(t *testing.T) {
 	}
 
 	// Verify no relations were created
-	rawDB := tools.DB.GetRawDB()
+	rawDB := sqlDB(t, tools)
 	var count int
 	ctx := context.Background()
 	err = rawDB.QueryRowContext(ctx, `
@@ -868,18 +1029,18 @@ func TestPropose_KindDeterminesRelation(t *testing.T) {
 	}
 
 	// Propose system hypothesis - should create componentOf
-	_, err = tools.ProposeHypothesis("System Hypo", "A system thing", "global", "system", "{}", "", []string{"base-claim"}, 3)
+	_, err = tools.ProposeHypothesis("System Hypo", "A system thing", "global", "system", "{}", "", []string{"base-claim"}, 3, 0, "", "")
 	if err != nil {
 		t.Fatalf("ProposeHypothesis for system failed: %v", err)
 	}
 
 	// Propose episteme hypothesis - should create constituentOf
-	_, err = tools.ProposeHypothesis("Episteme Hypo", "An epistemic claim", "global", "episteme", "{}", "", []string{"base-claim"}, 3)
+	_, err = tools.ProposeHypothesis("Episteme Hypo", "An epistemic claim", "global", "episteme", "{}", "", []string{"base-claim"}, 3, 0, "", "")
 	if err != nil {
 		t.Fatalf("ProposeHypothesis for episteme failed: %v", err)
 	}
 
-	rawDB := tools.DB.GetRawDB()
+	rawDB := sqlDB(t, tools)
 
 	// Check system → componentOf
 	var componentCount int
@@ -935,6 +1096,9 @@ func TestWLNK_MemberOf_NoPropagation(t *testing.T) {
 		"bad-decision", // MemberOf the bad decision
 		nil,
 		3,
+		0,
+		"",
+		"",
 	)
 	if err != nil {
 		t.Fatalf("ProposeHypothesis failed: %v", err)
@@ -1003,3 +1167,74 @@ func TestFormatInvariants(t *testing.T) {
 		t.Errorf("Expected line 3 to start with '3. Telethon', got: %s", lines[2])
 	}
 }
+
+func TestCalculateR_UsesConfiguredPhiProfile(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	_ = tools.DB.CreateHolon(ctx, "phi-whole", "hypothesis", "system", "L2", "Whole", "content", "default", "", "")
+	_ = tools.DB.CreateHolon(ctx, "phi-part", "hypothesis", "system", "L2", "Part", "content", "default", "", "")
+	_ = tools.DB.AddEvidence(ctx, "e-whole", "phi-whole", "test", "ok", "pass", "L2", "", "2099-12-31")
+	_ = tools.DB.AddEvidence(ctx, "e-part", "phi-part", "test", "ok", "pass", "L2", "", "2099-12-31")
+	_ = tools.DB.CreateRelation(ctx, "phi-part", "componentOf", "phi-whole", 2)
+
+	tools.FSM.State.CLPenaltyProfile = "linear:0.6"
+
+	result, err := tools.CalculateR("phi-whole")
+	if err != nil {
+		t.Fatalf("CalculateR failed: %v", err)
+	}
+	if !strings.Contains(result, "R_eff: 0.80") {
+		t.Errorf("Expected R_eff 0.80 under linear:0.6 (Φ(CL2)=0.2), got: %s", result)
+	}
+	if !strings.Contains(result, "Φ(CL) Profile: linear [CL0=0.60 CL1=0.40 CL2=0.20 CL3=0.00]") {
+		t.Errorf("Expected profile recorded in report, got: %s", result)
+	}
+}
+
+func TestTools_InMemoryRepository(t *testing.T) {
+	tempDir := t.TempDir()
+	store := db.NewMemoryStore()
+	tools := NewTools(&FSM{State: State{Phase: PhaseIdle}, DB: store}, tempDir, store)
+	if err := tools.InitProject(); err != nil {
+		t.Fatalf("InitProject failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := tools.ProposeHypothesis("Use Redis", "Redis cache", "global", "system", "{}", "", nil, 3, 0, "", ""); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if phase := tools.FSM.GetPhase(); phase != PhaseAbduction {
+		t.Errorf("Expected ABDUCTION derived from the memory store, got %s", phase)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, ".quint", "quint.db")); !os.IsNotExist(err) {
+		t.Error("Expected no database file when running on the memory store")
+	}
+
+	_ = tools.DB.AddEvidence(ctx, "e-fresh", "use-redis", "test", "ok", "pass", "L2", "", "2099-01-01")
+	_ = tools.DB.AddEvidence(ctx, "e-stale", "use-redis", "test", "old", "pass", "L2", "", "2020-01-01")
+
+	r, err := tools.CalculateR("use-redis")
+	if err != nil {
+		t.Fatalf("CalculateR failed: %v", err)
+	}
+	if !strings.Contains(r, "R_eff: 0.55") {
+		t.Errorf("Expected one fresh and one expired evidence to give 0.55, got:\n%s", r)
+	}
+
+	report, err := tools.CheckDecay("", "", "", "")
+	if err != nil {
+		t.Fatalf("CheckDecay failed: %v", err)
+	}
+	if !strings.Contains(report, "| e-stale | test | EXPIRED |") || strings.Contains(report, "e-fresh") {
+		t.Errorf("Expected only e-stale to be reported, got:\n%s", report)
+	}
+
+	if _, err := tools.CheckDecay("", "e-stale", "2099-12-31", "accepted"); err != nil {
+		t.Fatalf("waive failed: %v", err)
+	}
+	report, _ = tools.CheckDecay("", "", "", "")
+	if !strings.Contains(report, "All holons FRESH") || !strings.Contains(report, "| Use Redis | e-stale | 2099-12-31 | user | accepted |") {
+		t.Errorf("Expected the waiver to suspend the expiry, got:\n%s", report)
+	}
+}
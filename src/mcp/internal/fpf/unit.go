@@ -0,0 +1,122 @@
+package fpf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// unitOfWork journals the file changes of one tool call so they can be
+// undone together with its database transaction.
+type unitOfWork struct {
+	undo    []func() error
+	changed []string // holons to announce once the unit commits
+}
+
+// inUnit runs fn as one unit of work. fn gets a copy of the tools whose DB is
+// bound to a transaction and whose projection writes are journaled. If fn or
+// the commit fails, the transaction is rolled back, the files are put back
+// and the error is returned; holon change notifications are only sent after
+// a successful commit. Calling inUnit from inside fn joins the outer unit.
+func (t *Tools) inUnit(fn func(tx *Tools) error) error {
+	if t.uow != nil {
+		return fn(t)
+	}
+
+	uow := &unitOfWork{}
+	run := func(repo db.Repository) error {
+		tx := *t
+		tx.DB, tx.uow = repo, uow
+		return fn(&tx)
+	}
+
+	var err error
+	if t.DB != nil {
+		err = t.DB.WithTx(context.Background(), run)
+	} else {
+		err = run(nil)
+	}
+	if err != nil {
+		if undoErr := uow.rollback(); undoErr != nil {
+			return fmt.Errorf("%w (restoring files also failed: %v)", err, undoErr)
+		}
+		return err
+	}
+
+	for _, id := range uow.changed {
+		t.notifyHolonChanged(id)
+	}
+	return nil
+}
+
+// writeProjection writes a markdown projection, remembering what was there.
+func (u *unitOfWork) writeProjection(path string, fields map[string]string, body string) error {
+	if err := u.preserve(path); err != nil {
+		return err
+	}
+	return WriteWithHash(path, fields, body)
+}
+
+// writeFile writes a plain file, remembering what was there.
+func (u *unitOfWork) writeFile(path string, data []byte) error {
+	if err := u.preserve(path); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// rename moves src to dst; rollback moves it back and restores anything the
+// move overwrote.
+func (u *unitOfWork) rename(src, dst string) error {
+	if err := u.preserve(dst); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return err
+	}
+	u.undo = append(u.undo, func() error { return os.Rename(dst, src) })
+	return nil
+}
+
+// preserve records how to return path to its current state.
+func (u *unitOfWork) preserve(path string) error {
+	prev, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		u.undo = append(u.undo, func() error { return os.WriteFile(path, prev, 0644) })
+	case os.IsNotExist(err):
+		u.undo = append(u.undo, func() error {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return nil
+		})
+	default:
+		return err
+	}
+	return nil
+}
+
+func (u *unitOfWork) holonChanged(holonID string) {
+	for _, id := range u.changed {
+		if id == holonID {
+			return
+		}
+	}
+	u.changed = append(u.changed, holonID)
+}
+
+// rollback undoes the journaled file changes, newest first.
+func (u *unitOfWork) rollback() error {
+	var errs []error
+	for i := len(u.undo) - 1; i >= 0; i-- {
+		if err := u.undo[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	u.undo = nil
+	return errors.Join(errs...)
+}
@@ -16,15 +16,27 @@ SELECT title FROM holons WHERE id = ? LIMIT 1;
 -- name: ListAllHolonIDs :many
 SELECT id FROM holons;
 
+-- name: ListHolons :many
+SELECT * FROM holons ORDER BY layer, id;
+
 -- name: ListHolonsByLayer :many
 SELECT * FROM holons WHERE layer = ? ORDER BY created_at DESC;
 
+-- name: UpdateHolonClaim :exec
+UPDATE holons SET formality = ?, claim_scope = ?, updated_at = ? WHERE id = ?;
+
 -- name: UpdateHolonLayer :exec
 UPDATE holons SET layer = ?, updated_at = ? WHERE id = ?;
 
+-- name: UpdateHolonStatus :exec
+UPDATE holons SET status = ?, updated_at = ? WHERE id = ?;
+
 -- name: UpdateHolonRScore :exec
 UPDATE holons SET cached_r_score = ?, updated_at = ? WHERE id = ?;
 
+-- name: CacheHolonRScore :exec
+UPDATE holons SET cached_r_score = ? WHERE id = ?;
+
 -- name: GetHolonsByParent :many
 SELECT * FROM holons WHERE parent_id = ? ORDER BY created_at DESC;
 
@@ -57,6 +69,9 @@ SELECT * FROM evidence WHERE holon_id = ? ORDER BY created_at DESC;
 -- name: GetEvidenceWithCarrier :many
 SELECT * FROM evidence WHERE carrier_ref IS NOT NULL AND carrier_ref != '';
 
+-- name: ListAllEvidence :many
+SELECT * FROM evidence ORDER BY created_at DESC;
+
 -- Relation queries
 
 -- name: AddRelation :exec
@@ -72,6 +87,9 @@ DO UPDATE SET congruence_level = excluded.congruence_level;
 -- name: GetRelationsByTarget :many
 SELECT * FROM relations WHERE target_id = ? AND relation_type = ?;
 
+-- name: GetRelationsBySource :many
+SELECT * FROM relations WHERE source_id = ? AND relation_type = ?;
+
 -- name: GetComponentsOf :many
 SELECT source_id, congruence_level FROM relations
 WHERE target_id = ? AND relation_type = 'componentOf';
@@ -86,6 +104,13 @@ SELECT source_id, relation_type, congruence_level
 FROM relations
 WHERE target_id = ? AND relation_type IN ('componentOf', 'constituentOf');
 
+-- name: GetImpactedHolons :many
+SELECT source_id AS holon_id FROM relations
+WHERE target_id = ? AND relation_type IN ('dependsOn', 'selects')
+UNION
+SELECT target_id AS holon_id FROM relations
+WHERE source_id = ? AND relation_type IN ('componentOf', 'constituentOf', 'memberOf');
+
 -- name: GetCollectionMembers :many
 SELECT source_id, congruence_level
 FROM relations
@@ -100,17 +125,31 @@ VALUES (?, ?, ?, ?, ?, ?, ?);
 -- Characteristic queries
 
 -- name: AddCharacteristic :exec
-INSERT INTO characteristics (id, holon_id, name, scale, value, unit, created_at)
-VALUES (?, ?, ?, ?, ?, ?, ?);
+-- Recording a characteristic again replaces its value.
+INSERT INTO characteristics (id, holon_id, name, scale, value, unit, direction, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    scale = excluded.scale,
+    value = excluded.value,
+    unit = excluded.unit,
+    direction = excluded.direction,
+    created_at = excluded.created_at;
 
 -- name: GetCharacteristics :many
-SELECT * FROM characteristics WHERE holon_id = ?;
+SELECT * FROM characteristics WHERE holon_id = ? ORDER BY name;
 
 -- Audit log queries
 
 -- name: InsertAuditLog :exec
-INSERT INTO audit_log (id, tool_name, operation, actor, target_id, input_hash, result, details, context_id)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO audit_log (id, timestamp, tool_name, operation, actor, target_id, input_hash, result, details, context_id, seq, prev_hash, entry_hash, signature)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetLastAuditLog :one
+SELECT * FROM audit_log WHERE seq IS NOT NULL ORDER BY seq DESC LIMIT 1;
+
+-- name: ListAuditLog :many
+-- Chain order: entries written before chaining (seq NULL) come first.
+SELECT * FROM audit_log ORDER BY seq, timestamp;
 
 -- name: GetAuditLogByContext :many
 SELECT * FROM audit_log WHERE context_id = ? ORDER BY timestamp DESC;
@@ -121,6 +160,21 @@ SELECT * FROM audit_log WHERE target_id = ? ORDER BY timestamp DESC;
 -- name: GetRecentAuditLog :many
 SELECT * FROM audit_log ORDER BY timestamp DESC LIMIT ?;
 
+-- name: FilterAuditLog :many
+-- Newest first. NULL filters match everything; actor also matches its
+-- role@session form; since/until compare against the stored timestamp text.
+SELECT * FROM audit_log
+WHERE (sqlc.narg(tool_name) IS NULL OR tool_name = sqlc.narg(tool_name))
+  AND (sqlc.narg(operation) IS NULL OR operation = sqlc.narg(operation))
+  AND (sqlc.narg(actor) IS NULL OR actor = sqlc.narg(actor) OR actor LIKE sqlc.narg(actor) || '@%')
+  AND (sqlc.narg(target_id) IS NULL OR target_id = sqlc.narg(target_id))
+  AND (sqlc.narg(result) IS NULL OR result = sqlc.narg(result))
+  AND (sqlc.narg(context_id) IS NULL OR context_id = sqlc.narg(context_id))
+  AND (sqlc.narg(since) IS NULL OR timestamp >= sqlc.narg(since))
+  AND (sqlc.narg(until) IS NULL OR timestamp < sqlc.narg(until))
+ORDER BY seq DESC, timestamp DESC
+LIMIT sqlc.arg(max_rows);
+
 -- Waiver queries
 
 -- name: CreateWaiver :exec
@@ -140,3 +194,86 @@ SELECT * FROM waivers WHERE waived_until > datetime('now') ORDER BY waived_until
 
 -- name: GetEvidenceByID :one
 SELECT * FROM evidence WHERE id = ? LIMIT 1;
+
+-- Context queries
+
+-- name: CreateContext :exec
+INSERT INTO contexts (id, description, created_at) VALUES (?, ?, ?);
+
+-- name: GetContext :one
+SELECT * FROM contexts WHERE id = ? LIMIT 1;
+
+-- name: GetActiveContext :one
+SELECT * FROM contexts WHERE is_active = 1 LIMIT 1;
+
+-- name: ListContexts :many
+SELECT * FROM contexts ORDER BY id;
+
+-- name: SetActiveContext :exec
+UPDATE contexts SET is_active = CASE WHEN id = ? THEN 1 ELSE 0 END;
+
+-- Phase transition queries
+
+-- name: InsertPhaseTransition :exec
+INSERT INTO phase_transitions (context_id, from_phase, to_phase, role, session_id, tool_name, evidence, reason, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetPhaseTransitions :many
+SELECT * FROM phase_transitions WHERE context_id = ? ORDER BY id;
+
+-- name: GetLastPhaseTransition :one
+SELECT * FROM phase_transitions WHERE context_id = ? ORDER BY id DESC LIMIT 1;
+
+-- Suspect evidence queries
+
+-- name: MarkEvidenceSuspect :exec
+INSERT INTO suspect_evidence (evidence_id, holon_id, changed_paths, from_commit, to_commit, detected_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT(evidence_id)
+DO UPDATE SET changed_paths = excluded.changed_paths, to_commit = excluded.to_commit;
+
+-- name: GetSuspectEvidence :many
+SELECT * FROM suspect_evidence ORDER BY holon_id, evidence_id;
+
+-- name: GetSuspectEvidenceByHolon :many
+SELECT * FROM suspect_evidence WHERE holon_id = ? ORDER BY evidence_id;
+
+-- name: ClearSuspectEvidence :exec
+DELETE FROM suspect_evidence WHERE holon_id = ?;
+
+-- name: UnmarkEvidenceSuspect :exec
+DELETE FROM suspect_evidence WHERE evidence_id = ?;
+
+-- DRR snapshot queries
+
+-- name: InsertDRRSnapshot :exec
+INSERT INTO drr_snapshots (drr_id, holon_id, role, r_eff, audit_tree, evidence, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetDRRSnapshots :many
+-- Winner first, then the rejected alternatives.
+SELECT * FROM drr_snapshots WHERE drr_id = ? ORDER BY role = 'winner' DESC, holon_id;
+
+-- name: GetDRRSnapshotsByHolon :many
+SELECT * FROM drr_snapshots WHERE holon_id = ? ORDER BY created_at, drr_id;
+
+-- FSM state queries
+
+-- name: GetFpfState :one
+SELECT * FROM fpf_state WHERE context_id = ? LIMIT 1;
+
+-- name: UpsertFpfState :exec
+INSERT INTO fpf_state (context_id, active_role, active_session_id, active_role_context, last_commit, assurance_threshold, formality_threshold, cl_penalty_profile, strict_mode, decay_profiles, evidence_weighting, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(context_id) DO UPDATE SET
+    active_role = excluded.active_role,
+    active_session_id = excluded.active_session_id,
+    active_role_context = excluded.active_role_context,
+    last_commit = excluded.last_commit,
+    assurance_threshold = excluded.assurance_threshold,
+    formality_threshold = excluded.formality_threshold,
+    cl_penalty_profile = excluded.cl_penalty_profile,
+    strict_mode = excluded.strict_mode,
+    decay_profiles = excluded.decay_profiles,
+    evidence_weighting = excluded.evidence_weighting,
+    updated_at = excluded.updated_at;
@@ -12,6 +12,9 @@ CREATE TABLE holons (
     scope TEXT,
     parent_id TEXT REFERENCES holons(id),
     cached_r_score REAL DEFAULT 0.0 CHECK(cached_r_score BETWEEN 0.0 AND 1.0),
+    formality INTEGER DEFAULT 0 CHECK(formality BETWEEN 0 AND 9),
+    claim_scope TEXT,
+    status TEXT, -- DRR lifecycle: proposed, accepted, superseded, deprecated
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
@@ -36,6 +39,7 @@ CREATE TABLE characteristics (
     scale TEXT NOT NULL,
     value TEXT NOT NULL,
     unit TEXT,
+    direction TEXT, -- higher or lower is better
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY(holon_id) REFERENCES holons(id)
 );
@@ -71,9 +75,15 @@ CREATE TABLE audit_log (
     input_hash TEXT,
     result TEXT NOT NULL,
     details TEXT,
-    context_id TEXT NOT NULL DEFAULT 'default'
+    context_id TEXT NOT NULL DEFAULT 'default',
+    seq INTEGER,            -- position in the hash chain; NULL for entries written before chaining
+    prev_hash TEXT,         -- entry_hash of seq - 1
+    entry_hash TEXT,        -- SHA-256 over seq, prev_hash and the entry's fields
+    signature TEXT          -- optional ed25519 signature of entry_hash
 );
 
+CREATE UNIQUE INDEX idx_audit_log_seq ON audit_log(seq);
+
 CREATE TABLE waivers (
     id TEXT PRIMARY KEY,
     evidence_id TEXT NOT NULL,
@@ -91,10 +101,62 @@ CREATE TABLE fpf_state (
     active_role_context TEXT,
     last_commit TEXT,
     assurance_threshold REAL DEFAULT 0.8 CHECK(assurance_threshold BETWEEN 0.0 AND 1.0),
+    formality_threshold INTEGER DEFAULT 0 CHECK(formality_threshold BETWEEN 0 AND 9),
+    cl_penalty_profile TEXT,
+    strict_mode INTEGER DEFAULT 0,
+    decay_profiles TEXT,
+    evidence_weighting TEXT,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE contexts (
+    id TEXT PRIMARY KEY,
+    description TEXT,
+    is_active INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE phase_transitions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    context_id TEXT NOT NULL DEFAULT 'default',
+    from_phase TEXT NOT NULL,
+    to_phase TEXT NOT NULL,
+    role TEXT,
+    session_id TEXT,
+    tool_name TEXT,
+    evidence TEXT, -- JSON evidence stub (A.10 anchor), if one was given
+    reason TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE suspect_evidence (
+    evidence_id TEXT PRIMARY KEY,
+    holon_id TEXT NOT NULL,
+    changed_paths TEXT NOT NULL, -- carrier paths changed in the diff, comma-separated
+    from_commit TEXT,
+    to_commit TEXT,
+    detected_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(evidence_id) REFERENCES evidence(id)
+);
+
+CREATE TABLE drr_snapshots (
+    drr_id TEXT NOT NULL,
+    holon_id TEXT NOT NULL,
+    role TEXT NOT NULL,   -- winner or rejected
+    r_eff REAL NOT NULL,  -- R_eff when the DRR was recorded
+    audit_tree TEXT,      -- assurance tree of the winner at decision time
+    evidence TEXT,        -- JSON: evidence with valid_until and any active waiver
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (drr_id, holon_id),
+    FOREIGN KEY(drr_id) REFERENCES holons(id)
+);
+
+-- knowledge_fts (FTS5 full-text index) is created by migration 4 and
+-- maintained by hand-written queries in db/search.go.
+
 -- Indexes for WLNK traversal
 CREATE INDEX IF NOT EXISTS idx_relations_target ON relations(target_id, relation_type);
 CREATE INDEX IF NOT EXISTS idx_relations_source ON relations(source_id, relation_type);
 CREATE INDEX IF NOT EXISTS idx_waivers_evidence ON waivers(evidence_id);
+CREATE INDEX IF NOT EXISTS idx_suspect_evidence_holon ON suspect_evidence(holon_id);
+CREATE INDEX IF NOT EXISTS idx_drr_snapshots_holon ON drr_snapshots(holon_id);